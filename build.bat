@@ -17,6 +17,15 @@ if %ERRORLEVEL% NEQ 0 (
 )
 echo Reader built successfully: bin\reader.exe
 
+REM Supervisorアプリをビルド（reader.exeの監視・再起動）
+echo Building supervisor...
+go build -o bin\supervisor.exe .\cmd\supervisor
+if %ERRORLEVEL% NEQ 0 (
+    echo Error: Failed to build supervisor
+    exit /b 1
+)
+echo Supervisor built successfully: bin\supervisor.exe
+
 REM Serverアプリをビルド
 echo.
 echo Building server...
@@ -33,3 +42,4 @@ echo.
 echo Usage:
 echo   Start server: bin\server.exe -port 50051 -db license_server.db
 echo   Start reader: bin\reader.exe -server localhost:50051 -db license_reader.db -reader-id reader01
+echo   Supervise reader: bin\supervisor.exe -reader reader.exe
@@ -52,7 +52,17 @@ Write-Host "Reader built successfully: $readerOutput" -ForegroundColor Green
 Copy-Item $readerOutput "bin/reader.exe" -Force
 Write-Host "Also copied to: bin/reader.exe" -ForegroundColor Cyan
 
+# Supervisorアプリをビルド（reader.exeの監視・再起動）
+Write-Host "`nBuilding supervisor..." -ForegroundColor Yellow
+go build -o bin/supervisor.exe ./cmd/supervisor
+if ($LASTEXITCODE -ne 0) {
+    Write-Host "Error: Failed to build supervisor" -ForegroundColor Red
+    exit 1
+}
+Write-Host "Supervisor built successfully: bin/supervisor.exe" -ForegroundColor Green
+
 Write-Host "`nBuild completed successfully!" -ForegroundColor Green
 Write-Host "`nUsage:" -ForegroundColor Cyan
 Write-Host "  Start server: bin\server.exe -port 50051 -db license_server.db"
 Write-Host "  Start reader: bin\reader.exe -server localhost:50051 -db license_reader.db -reader-id reader01"
+Write-Host "  Supervise reader: bin\supervisor.exe -reader reader.exe"
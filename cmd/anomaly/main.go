@@ -0,0 +1,238 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"menkyo_go/internal/anomaly"
+	"menkyo_go/internal/attendance"
+	"menkyo_go/internal/certs"
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/license"
+	"menkyo_go/internal/pii"
+	"menkyo_go/internal/woffsv"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  anomaly scan    -db <path> [-store <path>] [-woff-sv <url>] [-db-server <addr>] [-day 2025-11-01]
+  anomaly run     -db <path> [-store <path>] [-woff-sv <url>] [-db-server <addr>] [-at 05:30] [-notify]
+  anomaly list    -store <path> [-day 2025-11-01] [-all]
+  anomaly resolve -store <path> -id <id> -by <name> [-note <text>]
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetReaderConfig()
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPath := fs.String("db", cfg.DBPath, "Reader database path (punch_outbox and read_history)")
+	storePath := fs.String("store", "", "Database path to record anomalies (defaults to -db)")
+	woffSvURL := fs.String("woff-sv", "", "woff-sv backend URL to include time card logs (optional)")
+	dbServer := fs.String("db-server", cfg.DBServerAddr, "db_service address for kinmu_taikei (optional)")
+	day := fs.String("day", "", "Work date (YYYY-MM-DD)")
+
+	switch os.Args[1] {
+	case "scan":
+		fs.Parse(os.Args[2:])
+
+		date := time.Now().AddDate(0, 0, -1)
+		if *day != "" {
+			date = parseDate(*day)
+		}
+
+		detector, _, cleanup := newDetector(*dbPath, *storePath, *woffSvURL, *dbServer, cfg.WoffClSecret)
+		defer cleanup()
+
+		created, err := detector.Scan(date)
+		if err != nil {
+			log.Fatalf("Failed to scan: %v", err)
+		}
+		fmt.Printf("%d new anomalies on %s\n", len(created), date.Format("2006-01-02"))
+		printAnomalies(created)
+
+	case "run":
+		at := fs.String("at", "05:30", "Time of day to scan and send the summary (HH:MM)")
+		notify := fs.Bool("notify", false, "Send the summary to woff-sv (requires -woff-sv)")
+		fs.Parse(os.Args[2:])
+
+		runAt, err := time.Parse("15:04", *at)
+		if err != nil {
+			log.Fatalf("Invalid -at: %v", err)
+		}
+
+		detector, store, cleanup := newDetector(*dbPath, *storePath, *woffSvURL, *dbServer, cfg.WoffClSecret)
+		defer cleanup()
+
+		var notifier anomaly.Notifier
+		if *notify {
+			if *woffSvURL == "" {
+				log.Fatalf("-notify requires -woff-sv")
+			}
+			client, err := woffsv.NewAuthClient(*woffSvURL, cfg.WoffClSecret)
+			if err != nil {
+				log.Fatalf("Failed to create woff-sv client: %v", err)
+			}
+			defer client.Close()
+			notifier = client
+		}
+
+		job := anomaly.NewJob(detector, store, notifier,
+			time.Duration(runAt.Hour())*time.Hour+time.Duration(runAt.Minute())*time.Minute,
+			cfg.ReaderID, func(msg string) {
+				log.Print(msg)
+				store.LogMessage("INFO", msg)
+			})
+
+		stop := make(chan struct{})
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			close(stop)
+		}()
+
+		job.Run(stop)
+
+	case "list":
+		all := fs.Bool("all", false, "Include resolved anomalies")
+		fs.Parse(os.Args[2:])
+
+		store := openStore(*dbPath, *storePath)
+		defer store.Close()
+
+		records, err := store.ListAnomalies(*day, *all)
+		if err != nil {
+			log.Fatalf("Failed to list anomalies: %v", err)
+		}
+		printAnomalies(records)
+
+	case "resolve":
+		id := fs.Int64("id", 0, "Anomaly ID")
+		by := fs.String("by", "", "Resolved by")
+		note := fs.String("note", "", "Resolution note")
+		fs.Parse(os.Args[2:])
+
+		if *id == 0 || *by == "" {
+			usage()
+		}
+
+		store := openStore(*dbPath, *storePath)
+		defer store.Close()
+
+		if err := store.ResolveAnomaly(*id, *by, *note); err != nil {
+			log.Fatalf("Failed to resolve anomaly: %v", err)
+		}
+		fmt.Printf("Anomaly %d resolved\n", *id)
+
+	default:
+		usage()
+	}
+}
+
+// newDetector 打刻の取得元と記録先を設定したDetectorを作成
+func newDetector(dbPath, storePath, woffSvURL, dbServer, secret string) (*anomaly.Detector, *database.Logger, func()) {
+	var closers []func()
+	cleanup := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	logger := openLogger(dbPath)
+	closers = append(closers, func() { logger.Close() })
+
+	store := logger
+	if storePath != "" && storePath != dbPath {
+		store = openLogger(storePath)
+		closers = append(closers, func() { store.Close() })
+	}
+
+	sources := []attendance.PunchSource{attendance.NewOutboxSource(logger)}
+
+	if woffSvURL != "" {
+		client, err := woffsv.NewAuthClient(woffSvURL, secret)
+		if err != nil {
+			log.Fatalf("Failed to create woff-sv client: %v", err)
+		}
+		closers = append(closers, func() { client.Close() })
+		sources = append(sources, attendance.NewTimeCardLogSource(client, logger))
+	}
+
+	var drivers attendance.DriverLookup
+	if dbServer != "" {
+		dbTLS, err := certs.ClientTLSConfig(config.GetReaderConfig().DBTLS, nil)
+		if err != nil {
+			log.Fatalf("Failed to load db_service TLS config: %v", err)
+		}
+		client, err := license.NewDBClient(dbServer, license.ClientOptions{DBTLS: dbTLS})
+		if err != nil {
+			log.Fatalf("Failed to connect to db_service: %v", err)
+		}
+		closers = append(closers, func() { client.Close() })
+		drivers = client
+	}
+
+	engine := attendance.NewEngine(drivers, sources...)
+	return anomaly.NewDetector(engine, store), store, cleanup
+}
+
+func openStore(dbPath, storePath string) *database.Logger {
+	if storePath == "" {
+		storePath = dbPath
+	}
+	return openLogger(storePath)
+}
+
+// openLogger データベースを開き、カードの識別子の列を暗号化する鍵束を設定（PII_KEYが空の場合は暗号化しない）
+func openLogger(path string) *database.Logger {
+	logger, err := database.NewLogger(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+
+	cipher, err := pii.Open(config.GetReaderConfig().PIIKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII key: %v", err)
+	}
+	logger.SetCipher(cipher)
+	return logger
+}
+
+func parseDate(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid date: %v", err)
+	}
+	return t
+}
+
+func printAnomalies(records []*database.AnomalyRecord) {
+	for _, a := range records {
+		status := "open"
+		if a.Resolved {
+			status = fmt.Sprintf("resolved by %s", a.ResolvedBy)
+		}
+		fmt.Printf("#%d [%s] %s driver=%d card=%s at %s (%s)\n",
+			a.ID, a.WorkDate, a.Kind, a.DriverID, a.CardID,
+			a.PunchTime.In(time.Local).Format("2006-01-02 15:04:05"), status)
+		if a.Detail != "" {
+			fmt.Printf("  %s\n", a.Detail)
+		}
+	}
+}
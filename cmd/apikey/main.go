@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/license"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  apikey create [-db <path>] -name <name> -role reader -reader <reader-id>
+  apikey create [-db <path>] -name <name> -role admin
+  apikey list   [-db <path>]
+  apikey revoke [-db <path>] -id <id>
+  apikey audit  [-db <path>] [-since 24h] [-limit 100]
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetServerConfig()
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPath := fs.String("db", cfg.DBPath, "Server database path")
+
+	switch os.Args[1] {
+	case "create":
+		name := fs.String("name", "", "Key name (shown in audit logs)")
+		role := fs.String("role", license.RoleReader, "Role: reader, admin")
+		readerID := fs.String("reader", "", "Reader ID the key may send as (reader role)")
+		fs.Parse(os.Args[2:])
+
+		if *name == "" {
+			usage()
+		}
+		switch *role {
+		case license.RoleReader:
+			if *readerID == "" {
+				log.Fatal("-reader is required for the reader role")
+			}
+		case license.RoleAdmin:
+			*readerID = ""
+		default:
+			log.Fatalf("Unknown role: %s", *role)
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		key, hash, err := license.GenerateAPIKey()
+		if err != nil {
+			log.Fatal(err)
+		}
+		record, err := logger.CreateAPIKey(hash, *name, *role, *readerID)
+		if err != nil {
+			log.Fatalf("Failed to create api key: %v", err)
+		}
+
+		// キーはここでしか表示しない（DBにはハッシュのみ保存）
+		fmt.Printf("Created api key #%d (%s, role=%s)\n", record.ID, record.Name, record.Role)
+		fmt.Printf("READER_API_KEY=%s\n", key)
+
+	case "list":
+		fs.Parse(os.Args[2:])
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		keys, err := logger.ListAPIKeys()
+		if err != nil {
+			log.Fatalf("Failed to list api keys: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tROLE\tREADER\tCREATED\tLAST USED\tSTATUS")
+		for _, k := range keys {
+			state := "active"
+			if k.Revoked() {
+				state = "revoked " + formatTime(k.RevokedAt)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				k.ID, k.Name, k.Role, k.ReaderID, formatTime(k.CreatedAt), formatTime(k.LastUsedAt), state)
+		}
+		w.Flush()
+
+	case "revoke":
+		id := fs.Int64("id", 0, "Key ID")
+		fs.Parse(os.Args[2:])
+		if *id == 0 {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		if err := logger.RevokeAPIKey(*id); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Revoked api key #%d\n", *id)
+
+	case "audit":
+		since := fs.Duration("since", 24*time.Hour, "Show denials within this duration")
+		limit := fs.Int("limit", 100, "Maximum number of records")
+		fs.Parse(os.Args[2:])
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		records, err := logger.ListAuthAudit(time.Now().Add(-*since), *limit)
+		if err != nil {
+			log.Fatalf("Failed to list audit log: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tMETHOD\tIDENTITY\tAUTH\tREADER\tPEER\tRESULT\tREASON")
+		for _, r := range records {
+			result := "denied"
+			if r.Allowed {
+				result = "allowed (audit)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				formatTime(r.Timestamp), r.Method, r.Identity, r.AuthType, r.ReaderID, r.PeerAddr, result, r.Reason)
+		}
+		w.Flush()
+
+	default:
+		usage()
+	}
+}
+
+func openDB(path string) *database.Logger {
+	logger, err := database.NewLogger(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	return logger
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"menkyo_go/internal/attendance"
+	"menkyo_go/internal/certs"
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/license"
+	"menkyo_go/internal/pii"
+	"menkyo_go/internal/woffsv"
+)
+
+func main() {
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	cfg := config.GetReaderConfig()
+	today := time.Now().Format("2006-01-02")
+
+	dbPath := flag.String("db", cfg.DBPath, "SQLite database path")
+	from := flag.String("from", today, "Start date (YYYY-MM-DD)")
+	to := flag.String("to", "", "End date (YYYY-MM-DD, inclusive; defaults to -from)")
+	driverID := flag.Int("driver", 0, "Driver ID (0 = all drivers)")
+	woffSvURL := flag.String("woff-sv", "", "woff-sv backend URL to include time card logs (optional)")
+	dbServer := flag.String("db-server", cfg.DBServerAddr, "db_service address for kinmu_taikei (optional)")
+	format := flag.String("format", "csv", "Output format: csv or text")
+	flag.Parse()
+
+	fromDate, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid -from: %v", err)
+	}
+	toDate := fromDate
+	if *to != "" {
+		toDate, err = time.ParseInLocation("2006-01-02", *to, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid -to: %v", err)
+		}
+	}
+
+	logger, err := database.NewLogger(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer logger.Close()
+
+	// 読み取り履歴の個人データの列を暗号化する鍵束（PII_KEYが空の場合は暗号化しない）
+	cipher, err := pii.Open(cfg.PIIKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII key: %v", err)
+	}
+	logger.SetCipher(cipher)
+
+	sources := []attendance.PunchSource{attendance.NewReadHistorySource(logger)}
+
+	if *woffSvURL != "" {
+		woffSvClient, err := woffsv.NewAuthClient(*woffSvURL, cfg.WoffClSecret)
+		if err != nil {
+			log.Fatalf("Failed to create woff-sv client: %v", err)
+		}
+		defer woffSvClient.Close()
+		sources = append(sources, attendance.NewTimeCardLogSource(woffSvClient, logger))
+	}
+
+	// 勤務体系はdb_serviceから取得（未指定の場合は全員DefaultRule）
+	var drivers attendance.DriverLookup
+	if *dbServer != "" {
+		dbTLS, err := certs.ClientTLSConfig(cfg.DBTLS, nil)
+		if err != nil {
+			log.Fatalf("Failed to load db_service TLS config: %v", err)
+		}
+		client, err := license.NewDBClient(*dbServer, license.ClientOptions{DBTLS: dbTLS})
+		if err != nil {
+			log.Fatalf("Failed to connect to db_service: %v", err)
+		}
+		defer client.Close()
+		drivers = client
+	}
+
+	engine := attendance.NewEngine(drivers, sources...)
+	shifts, err := engine.Compute(fromDate, toDate, int32(*driverID))
+	if err != nil {
+		log.Fatalf("Failed to compute work hours: %v", err)
+	}
+
+	switch *format {
+	case "csv":
+		writeCSV(shifts)
+	case "text":
+		writeText(shifts)
+	default:
+		log.Fatalf("Unknown format: %s", *format)
+	}
+}
+
+// writeCSV 給与計算用のCSVを出力
+func writeCSV(shifts []*attendance.Shift) {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{
+		"driver_id", "work_date", "start", "end",
+		"total_minutes", "break_minutes", "overtime_minutes", "late_night_minutes",
+		"missing_clock_in", "missing_clock_out", "in_progress",
+	})
+
+	for _, s := range shifts {
+		w.Write([]string{
+			strconv.Itoa(int(s.DriverID)),
+			s.WorkDate,
+			formatTime(s.Start),
+			formatTime(s.End),
+			strconv.Itoa(s.TotalMinutes),
+			strconv.Itoa(s.BreakMinutes),
+			strconv.Itoa(s.OvertimeMinutes),
+			strconv.Itoa(s.LateNightMinutes),
+			strconv.FormatBool(s.MissingIn),
+			strconv.FormatBool(s.MissingOut),
+			strconv.FormatBool(s.InProgress),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatalf("Failed to write CSV: %v", err)
+	}
+}
+
+// writeText 端末表示用に出力
+func writeText(shifts []*attendance.Shift) {
+	for _, s := range shifts {
+		fmt.Printf("[%s] driver=%d %s - %s\n", s.WorkDate, s.DriverID, formatTime(s.Start), formatTime(s.End))
+		fmt.Printf("  Total: %dmin, Break: %dmin, Overtime: %dmin, Late-night: %dmin\n",
+			s.TotalMinutes, s.BreakMinutes, s.OvertimeMinutes, s.LateNightMinutes)
+		if s.MissingIn {
+			fmt.Println("  ! Missing clock-in")
+		}
+		if s.MissingOut {
+			fmt.Println("  ! Missing clock-out")
+		}
+		if s.InProgress {
+			fmt.Println("  (in progress)")
+		}
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(time.Local).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"menkyo_go/internal/certs"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  certgen ca     [-dir certs] [-cn "menkyo_go CA"] [-days 3650]
+  certgen server [-dir certs] -host <name,ip,...> [-days 825] [-force]
+  certgen reader [-dir certs] -id <reader-id> [-days 825] [-force]
+  certgen admin  [-dir certs] -name <name> [-days 825] [-force]
+
+Use -force to replace an existing certificate; running servers and readers
+pick up the new files without a restart.
+
+Files are written to -dir:
+  ca:     ca.pem, ca-key.pem
+  server: server.pem, server-key.pem
+  reader: reader-<id>.pem, reader-<id>-key.pem
+  admin:  admin-<name>.pem, admin-<name>-key.pem
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := fs.String("dir", "certs", "Output directory (also where ca.pem and ca-key.pem are read from)")
+	days := fs.Int("days", 825, "Validity in days")
+	force := fs.Bool("force", false, "Overwrite existing files")
+
+	switch os.Args[1] {
+	case "ca":
+		cn := fs.String("cn", "menkyo_go CA", "CA common name")
+		fs.Parse(os.Args[2:])
+		if !isSet(fs, "days") {
+			*days = 3650
+		}
+
+		_, certPEM, keyPEM, err := certs.NewCA(*cn, validity(*days))
+		if err != nil {
+			log.Fatalf("Failed to create CA: %v", err)
+		}
+		write(*dir, "ca", certPEM, keyPEM, *force)
+
+	case "server":
+		hosts := fs.String("host", "", "Server host names or IP addresses (comma separated)")
+		fs.Parse(os.Args[2:])
+
+		var names []string
+		for _, host := range strings.Split(*hosts, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				names = append(names, host)
+			}
+		}
+		if len(names) == 0 {
+			usage()
+		}
+
+		certPEM, keyPEM, err := loadCA(*dir).IssueServer(names, validity(*days))
+		if err != nil {
+			log.Fatalf("Failed to issue server certificate: %v", err)
+		}
+		write(*dir, "server", certPEM, keyPEM, *force)
+
+	case "reader":
+		readerID := fs.String("id", "", "Reader ID (set as the certificate common name)")
+		fs.Parse(os.Args[2:])
+		if *readerID == "" {
+			usage()
+		}
+
+		certPEM, keyPEM, err := loadCA(*dir).IssueReader(*readerID, validity(*days))
+		if err != nil {
+			log.Fatalf("Failed to issue reader certificate: %v", err)
+		}
+		write(*dir, "reader-"+*readerID, certPEM, keyPEM, *force)
+
+	case "admin":
+		name := fs.String("name", "", "Administrator name (set as the certificate common name)")
+		fs.Parse(os.Args[2:])
+		if *name == "" {
+			usage()
+		}
+
+		certPEM, keyPEM, err := loadCA(*dir).IssueAdmin(*name, validity(*days))
+		if err != nil {
+			log.Fatalf("Failed to issue admin certificate: %v", err)
+		}
+		write(*dir, "admin-"+*name, certPEM, keyPEM, *force)
+
+	default:
+		usage()
+	}
+}
+
+func loadCA(dir string) *certs.CA {
+	ca, err := certs.LoadCA(filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		log.Fatalf("Failed to load CA (run 'certgen ca' first): %v", err)
+	}
+	return ca
+}
+
+func write(dir, name string, certPEM, keyPEM []byte, overwrite bool) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatalf("Failed to create %s: %v", dir, err)
+	}
+
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	if err := certs.WritePair(certFile, certPEM, keyFile, keyPEM, overwrite); err != nil {
+		log.Fatalf("Failed to write %s: %v", name, err)
+	}
+
+	fmt.Printf("Wrote %s and %s\n", certFile, keyFile)
+}
+
+func validity(days int) time.Duration {
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"menkyo_go/internal/certs"
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/license"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ライセンスサーバーのgrpc.health.v1を確認する（SERVINGの場合は終了コード0、それ以外は1）
+// 監視ツールやコンテナのヘルスチェックから使う
+func main() {
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetReaderConfig()
+
+	addr := flag.String("addr", cfg.ServerAddr, "License server address")
+	service := flag.String("service", "", "Service name (empty for the whole server)")
+	timeout := flag.Duration("timeout", 5*time.Second, "Timeout")
+	flag.Parse()
+
+	serverTLS, err := certs.ClientTLSConfig(cfg.ServerTLS, nil)
+	if err != nil {
+		log.Fatalf("Failed to load TLS config: %v", err)
+	}
+
+	client, err := license.NewClientWithOptions(*addr, "", license.ClientOptions{
+		ServerTLS: serverTLS,
+		APIKey:    cfg.APIKey,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	status, err := client.CheckHealth(ctx, *service)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println(status)
+	if status != healthpb.HealthCheckResponse_SERVING {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  migrate status [-db <path>]
+  migrate up     [-db <path>]
+
+The server and reader apply pending migrations on startup; use "up" to apply
+them ahead of a deployment. Reader databases: -db license_reader.db
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetServerConfig()
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPath := fs.String("db", cfg.DBPath, "Database path (server or reader)")
+
+	switch os.Args[1] {
+	case "status":
+		fs.Parse(os.Args[2:])
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		migrations, err := logger.MigrationStatus()
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		current, err := logger.SchemaVersion()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pending := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED")
+		for _, m := range migrations {
+			state := "applied"
+			if m.AppliedAt.IsZero() {
+				state = "pending"
+				pending++
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", m.Version, m.Name, state, formatTime(m.AppliedAt))
+		}
+		w.Flush()
+
+		fmt.Printf("\nSchema version: %d (latest: %d, pending: %d)\n", current, len(migrations), pending)
+		if current > len(migrations) {
+			fmt.Println("Warning: the database was migrated by a newer version")
+		}
+
+	case "up":
+		fs.Parse(os.Args[2:])
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		result, err := logger.Migrate()
+		if result != nil && result.BackupPath != "" {
+			fmt.Printf("Backed up database to %s\n", result.BackupPath)
+		}
+		if result != nil {
+			for _, m := range result.Applied {
+				fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+			}
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+
+		if len(result.Applied) == 0 {
+			fmt.Printf("Database is up to date (schema version %d)\n", result.ToVersion)
+			return
+		}
+		fmt.Printf("Migrated schema from version %d to %d\n", result.FromVersion, result.ToVersion)
+
+	default:
+		usage()
+	}
+}
+
+// openDB マイグレーションを適用せずに開く
+func openDB(path string) *database.Logger {
+	logger, err := database.OpenLogger(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	return logger
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/pii"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  piikey init      [-key <provider>]
+  piikey rotate    [-key <provider>]
+  piikey status    [-key <provider>]
+  piikey reencrypt [-key <provider>] [-db <path>] [-batch 1000]
+  piikey redact    [-db <path>] [-batch 1000]
+
+Providers (PII_KEY): file:<path>, env:<name>, keystore:<name>
+After "rotate", run "reencrypt" on every reader and server database.
+"reencrypt" also replaces card IDs in existing logs with card_ref pseudonyms.
+"redact" replaces card IDs in existing logs with [redacted] when no key is configured.
+Server databases: -db license_server.db
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetReaderConfig()
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	keySpec := fs.String("key", cfg.PIIKey, "Key provider: file:<path>, env:<name>, keystore:<name>")
+
+	switch os.Args[1] {
+	case "init":
+		fs.Parse(os.Args[2:])
+		provider := openProvider(*keySpec)
+
+		if _, err := provider.Load(); err == nil {
+			log.Fatalf("Keyring already exists in %s (use rotate to add a new key)", provider)
+		} else if !errors.Is(err, pii.ErrNotFound) {
+			log.Fatal(err)
+		}
+
+		keyring, err := pii.GenerateKeyring()
+		if err != nil {
+			log.Fatal(err)
+		}
+		save(provider, keyring)
+		fmt.Printf("Created keyring in %s (active key %d)\n", provider, keyring.Active)
+
+	case "rotate":
+		fs.Parse(os.Args[2:])
+		provider := openProvider(*keySpec)
+
+		keyring, err := provider.Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+		id, err := keyring.Rotate()
+		if err != nil {
+			log.Fatal(err)
+		}
+		save(provider, keyring)
+		fmt.Printf("Added key %d to %s; new values are encrypted with key %d\n", id, provider, id)
+		fmt.Println("Run \"piikey reencrypt\" on each database to re-encrypt existing values.")
+
+	case "status":
+		fs.Parse(os.Args[2:])
+		provider := openProvider(*keySpec)
+
+		keyring, err := provider.Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("Provider: %s\n\n", provider)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tSTATUS")
+		for _, id := range keyring.KeyIDs() {
+			state := "decrypt only"
+			if id == keyring.Active {
+				state = "active"
+			}
+			fmt.Fprintf(w, "%d\t%s\n", id, state)
+		}
+		w.Flush()
+
+	case "reencrypt":
+		dbPath := fs.String("db", cfg.DBPath, "Database path (reader or server)")
+		batch := fs.Int("batch", 1000, "Rows per transaction")
+		fs.Parse(os.Args[2:])
+
+		cipher, err := pii.Open(*keySpec)
+		if err != nil {
+			log.Fatalf("Failed to load PII key: %v", err)
+		}
+		if cipher == nil {
+			log.Fatal("PII key is not configured (set PII_KEY or -key)")
+		}
+
+		logger, err := database.NewLogger(*dbPath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		defer logger.Close()
+		logger.SetCipher(cipher)
+
+		var updated int64
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TABLE\tROWS\tUPDATED")
+		for _, table := range database.PIITables() {
+			result, err := logger.ReencryptPII(table, *batch)
+			if result != nil {
+				fmt.Fprintf(w, "%s\t%d\t%d\n", result.Table, result.Scanned, result.Updated)
+				updated += result.Updated
+			}
+			if err != nil {
+				w.Flush()
+				log.Fatalf("Failed to re-encrypt %s: %v", table, err)
+			}
+		}
+		// PII_KEYを設定する前のログのカードの識別子を仮名にする
+		result, err := logger.PseudonymizeLogs(*batch)
+		if result != nil {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", result.Table, result.Scanned, result.Updated)
+			updated += result.Updated
+		}
+		if err != nil {
+			w.Flush()
+			log.Fatalf("Failed to pseudonymize logs: %v", err)
+		}
+		w.Flush()
+
+		// 暗号化する前の値・古い鍵で暗号化した値が空きページに残らないようにする
+		if updated > 0 {
+			if err := logger.ScrubFreePages(); err != nil {
+				log.Fatal(err)
+			}
+		}
+		fmt.Printf("All values are encrypted with key %d\n", cipher.ActiveKey())
+
+	case "redact":
+		dbPath := fs.String("db", cfg.DBPath, "Database path (reader or server)")
+		batch := fs.Int("batch", 1000, "Rows per transaction")
+		fs.Parse(os.Args[2:])
+
+		// 鍵束を設定している場合はreencryptと同じく仮名に置き換える
+		cipher, err := pii.Open(*keySpec)
+		if err != nil {
+			log.Fatalf("Failed to load PII key: %v", err)
+		}
+
+		logger, err := database.NewLogger(*dbPath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		defer logger.Close()
+		logger.SetCipher(cipher)
+
+		result, err := logger.PseudonymizeLogs(*batch)
+		if err != nil {
+			log.Fatalf("Failed to redact logs: %v", err)
+		}
+		if result.Updated > 0 {
+			if err := logger.ScrubFreePages(); err != nil {
+				log.Fatal(err)
+			}
+		}
+		fmt.Printf("Redacted %d of %d log rows\n", result.Updated, result.Scanned)
+
+	default:
+		usage()
+	}
+}
+
+func openProvider(spec string) pii.KeyProvider {
+	provider, err := pii.ParseProvider(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if provider == nil {
+		log.Fatal("PII key is not configured (set PII_KEY or -key)")
+	}
+	return provider
+}
+
+// save 鍵束を保存（環境変数の場合は設定する値を表示する）
+func save(provider pii.KeyProvider, keyring *pii.Keyring) {
+	err := provider.Save(keyring)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, pii.ErrReadOnly) {
+		log.Fatal(err)
+	}
+
+	data, err := json.Marshal(keyring)
+	if err != nil {
+		log.Fatal(err)
+	}
+	name := strings.TrimPrefix(provider.String(), "env:")
+	fmt.Printf("Set the following environment variable on every reader and server:\n%s=%s\n\n", name, data)
+}
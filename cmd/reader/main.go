@@ -3,24 +3,41 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
 
+	"menkyo_go/internal/certs"
 	"menkyo_go/internal/config"
 	"menkyo_go/internal/database"
+	"menkyo_go/internal/detector"
+	"menkyo_go/internal/dispatch"
+	"menkyo_go/internal/eligibility"
+	"menkyo_go/internal/license"
+	"menkyo_go/internal/logging"
+	"menkyo_go/internal/metrics"
 	"menkyo_go/internal/nfc"
+	"menkyo_go/internal/pii"
+	"menkyo_go/internal/remoteconfig"
+	"menkyo_go/internal/retention"
+	"menkyo_go/internal/tracing"
 	"menkyo_go/internal/woffcl"
 	"menkyo_go/internal/woffsv"
+	pb "menkyo_go/proto/license"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/connectivity"
 )
 
 var (
@@ -86,23 +103,6 @@ func main() {
 
 	defer procReleaseMutex.Call(uintptr(mutex))
 
-	// ログファイル設定（logフォルダに日付ごと）
-	logDir := "log"
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		log.Printf("Warning: failed to create log directory: %v", err)
-	}
-
-	// 日付ごとのログファイル名（例: log/reader_20251026.log）
-	logFileName := fmt.Sprintf("%s/reader_%s.log", logDir, time.Now().Format("20060102"))
-	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err == nil {
-		defer logFile.Close()
-		// コンソールとファイルの両方に出力
-		multiWriter := io.MultiWriter(os.Stdout, logFile)
-		log.SetOutput(multiWriter)
-		log.Printf("Log file: %s", logFileName)
-	}
-
 	// .envファイルを読み込む
 	if err := config.LoadEnv(".env"); err != nil {
 		log.Printf("Warning: %v", err)
@@ -124,10 +124,6 @@ func main() {
 		}
 	}
 
-	log.Printf("Starting license reader v%s (Built: %s)", Version, BuildTime)
-	log.Printf("Reader ID: %s", *readerID)
-	log.Printf("Database path: %s", dbFullPath)
-
 	// データベース初期化
 	logger, err := database.NewLogger(*dbPath)
 	if err != nil {
@@ -135,10 +131,84 @@ func main() {
 	}
 	defer logger.Close()
 
-	logger.LogMessage("INFO", "License reader started")
+	// 読み取り履歴の個人データの列を暗号化する鍵束（PII_KEYが空の場合は暗号化しない）
+	cipher, err := pii.Open(cfg.PIIKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII key: %v", err)
+	}
+	logger.SetCipher(cipher)
+
+	// ログ（コンソール・日付ごとのファイル・DBのlogsテーブルに出力。カードの識別子は仮名にする。例: log/reader_20251026.log）
+	logLevel := new(slog.LevelVar)
+	if err := logging.SetLevel(logLevel, logger, cfg.LogLevel); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	_, logFile := logging.Setup(logging.Options{
+		Level:      logLevel,
+		Console:    os.Stdout,
+		Dir:        cfg.LogDir,
+		FilePrefix: "reader",
+		DB:         logger,
+		Cipher:     cipher,
+	})
+	defer logFile.Close()
+
+	// このリーダーのログには全てreader_idを付ける
+	rlog := slog.With(logging.KeyReaderID, *readerID)
+	rlog.Info("License reader started", "version", Version, "build_time", BuildTime, "db", dbFullPath)
+
+	// トレース（1回のタッチの読み取り・記録・woff-sv/サーバーへの送信を関連付ける）
+	shutdownTracing, err := tracing.Setup(cfg.Tracing, "menkyo-reader", Version, attribute.String("reader.id", *readerID))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	flushTraces := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			rlog.Warn("Failed to flush traces", logging.Err(err))
+		}
+	}
+	defer flushTraces()
+
+	// ライセンスサーバーへの接続（リーダーの登録・死活監視と配車の送信）
+	tlsLog := func(msg string) {
+		rlog.Info(msg, "component", "tls")
+	}
+	serverTLS, err := certs.ClientTLSConfig(cfg.ServerTLS, tlsLog)
+	if err != nil {
+		log.Fatalf("Failed to load license server TLS config: %v", err)
+	}
+	dbTLS, err := certs.ClientTLSConfig(cfg.DBTLS, tlsLog)
+	if err != nil {
+		log.Fatalf("Failed to load db_service TLS config: %v", err)
+	}
+
+	licenseClient, err := license.NewClientWithOptions(cfg.ServerAddr, cfg.DBServerAddr, license.ClientOptions{
+		ServerTLS:   serverTLS,
+		DBTLS:       dbTLS,
+		APIKey:      cfg.APIKey,
+		CallTimeout: time.Duration(cfg.CallTimeout) * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create license client: %v", err)
+	}
+	defer licenseClient.Close()
+
+	// 接続状態の変化を記録（切断時はすぐに再接続を試みる）
+	connCtx, stopConnMonitor := context.WithCancel(context.Background())
+	defer stopConnMonitor()
+	go licenseClient.MonitorConnection(connCtx, func(state connectivity.State) {
+		level := slog.LevelInfo
+		if state == connectivity.TransientFailure {
+			level = slog.LevelWarn
+		}
+		rlog.Log(context.Background(), level, "License server connection state changed", "state", state.String())
+	})
 
 	// woff-cl/woff-svクライアント初期化（スレッドセーフ）
 	var woffSvClient *woffsv.AuthClient
+	var woffSvURL string
 	var woffSvMutex sync.RWMutex
 	var heartbeatTicker *time.Ticker
 
@@ -164,8 +234,7 @@ func main() {
 							 strings.Contains(errMsg, "lookup")
 
 				if isDNSError && attempt < maxRetries {
-					log.Printf("DNS resolution failed (attempt %d/%d), retrying in %v...", attempt, maxRetries, retryDelay)
-					logger.LogMessage("WARNING", fmt.Sprintf("DNS resolution failed (attempt %d/%d)", attempt, maxRetries))
+					rlog.Warn("DNS resolution failed, retrying", "attempt", attempt, "max_attempts", maxRetries, "retry_in", retryDelay)
 					time.Sleep(retryDelay)
 					continue
 				}
@@ -173,8 +242,7 @@ func main() {
 				return fmt.Errorf("heartbeat failed: %w", err)
 			}
 
-			log.Printf("Connected to woff-sv: %s (status: %s, version: %s)", backendURL, hb.Status, hb.Version)
-			logger.LogMessage("INFO", fmt.Sprintf("woff-sv connected: url=%s, status=%s, version=%s", backendURL, hb.Status, hb.Version))
+			rlog.Info("Connected to woff-sv", "url", backendURL, "status", hb.Status, "version", hb.Version)
 
 			// 古いクライアントを閉じて新しいクライアントに置き換え
 			woffSvMutex.Lock()
@@ -182,6 +250,7 @@ func main() {
 				woffSvClient.Close()
 			}
 			woffSvClient = newClient
+			woffSvURL = backendURL
 			woffSvMutex.Unlock()
 
 			return nil
@@ -197,35 +266,79 @@ func main() {
 		return woffSvClient
 	}
 
-	if cfg.WoffClEndpoint != "" && cfg.WoffClSecret != "" {
-		log.Printf("Fetching backend URL from woff-cl: %s", cfg.WoffClEndpoint)
-		woffClClient := woffcl.NewClient(cfg.WoffClEndpoint, cfg.WoffClSecret)
-		backendURL, err := woffClClient.GetBackendURL()
+	// サーバーから配信された設定（.envの値より優先。受信するまでは前回の設定を使う）
+	var remoteSettings atomic.Pointer[remoteconfig.Settings]
+	remoteSettings.Store(&remoteconfig.Settings{})
 
-		if err != nil {
-			log.Printf("Warning: Failed to get backend URL from woff-cl: %v", err)
-			logger.LogMessage("WARNING", fmt.Sprintf("Failed to get backend URL: %v", err))
+	remoteWoffSvURL := func() string {
+		if u := remoteSettings.Load().WoffSvURL; u != nil {
+			return *u
+		}
+		return ""
+	}
 
-			// woff-clにアクセスできない場合、localhostにフォールバック（オフライン対応）
-			log.Printf("Falling back to localhost for offline mode")
-			logger.LogMessage("INFO", "Falling back to localhost for offline mode")
-			backendURL = "http://localhost:50051"
+	applyRemoteConfig := func(s *remoteconfig.Settings) error {
+		if s.WoffSvURL != nil && *s.WoffSvURL != "" {
+			woffSvMutex.RLock()
+			current := woffSvURL
+			woffSvMutex.RUnlock()
 
-			if err := setWoffSvClient(backendURL); err != nil {
-				log.Printf("Warning: Failed to connect to localhost: %v", err)
-				logger.LogMessage("WARNING", fmt.Sprintf("Failed to connect to localhost: %v", err))
-			} else {
-				log.Printf("Connected to localhost (offline mode)")
-				logger.LogMessage("INFO", "Connected to localhost (offline mode)")
+			if *s.WoffSvURL != current {
+				if err := setWoffSvClient(*s.WoffSvURL); err != nil {
+					return fmt.Errorf("failed to connect to woff_sv_url: %w", err)
+				}
 			}
+		}
+
+		level := cfg.LogLevel
+		if s.LogLevel != nil {
+			level = *s.LogLevel
+		}
+		if err := logging.SetLevel(logLevel, logger, level); err != nil {
+			return err
+		}
+
+		remoteSettings.Store(s)
+		return nil
+	}
+
+	configWatcher := remoteconfig.NewWatcher(licenseClient, remoteconfig.NewCache(cfg.ConfigCache),
+		*readerID, cfg.Site, applyRemoteConfig, func(msg string) {
+			rlog.Info(msg, "component", "config")
+		})
+	configWatcher.LoadCached()
+
+	configCtx, stopConfig := context.WithCancel(context.Background())
+	defer stopConfig()
+	go configWatcher.Run(configCtx)
+
+	if cfg.WoffClEndpoint != "" && cfg.WoffClSecret != "" {
+		if u := remoteWoffSvURL(); u != "" {
+			rlog.Info("Using woff-sv URL from server config", "url", u)
 		} else {
-			log.Printf("Backend URL: %s", backendURL)
-			logger.LogMessage("INFO", fmt.Sprintf("Backend URL from woff-cl: %s", backendURL))
+			rlog.Info("Fetching backend URL from woff-cl", "endpoint", cfg.WoffClEndpoint)
+			woffClClient := woffcl.NewClient(cfg.WoffClEndpoint, cfg.WoffClSecret)
+			backendURL, err := woffClClient.GetBackendURL()
+
+			if err != nil {
+				rlog.Warn("Failed to get backend URL from woff-cl", logging.Err(err))
+
+				// woff-clにアクセスできない場合、localhostにフォールバック（オフライン対応）
+				rlog.Info("Falling back to localhost for offline mode")
+				backendURL = "http://localhost:50051"
+
+				if err := setWoffSvClient(backendURL); err != nil {
+					rlog.Warn("Failed to connect to localhost", logging.Err(err))
+				} else {
+					rlog.Info("Connected to localhost (offline mode)")
+				}
+			} else {
+				rlog.Info("Backend URL from woff-cl", "url", backendURL)
 
-			// 初回接続
-			if err := setWoffSvClient(backendURL); err != nil {
-				log.Printf("Warning: Failed to connect to woff-sv: %v", err)
-				logger.LogMessage("WARNING", fmt.Sprintf("Failed to connect to woff-sv: %v", err))
+				// 初回接続
+				if err := setWoffSvClient(backendURL); err != nil {
+					rlog.Warn("Failed to connect to woff-sv", logging.Err(err))
+				}
 			}
 		}
 
@@ -235,23 +348,24 @@ func main() {
 			cfg.WoffClEndpoint,
 			cfg.WoffClSecret,
 			func(newURL string) bool {
-				log.Printf("Backend URL received: %s", newURL)
-				logger.LogMessage("INFO", fmt.Sprintf("Backend URL received: %s", newURL))
+				if u := remoteWoffSvURL(); u != "" {
+					rlog.Info("Ignoring backend URL from woff-cl: woff_sv_url is set by server config", "url", newURL)
+					return true
+				}
+
+				rlog.Info("Backend URL received", "url", newURL)
 
 				// 新しいURLでクライアントを再作成（Heartbeatで接続確認）
 				if err := setWoffSvClient(newURL); err != nil {
-					log.Printf("Failed to connect to backend: %v", err)
-					logger.LogMessage("WARNING", fmt.Sprintf("Failed to connect to backend: %v", err))
+					rlog.Warn("Failed to connect to backend", "url", newURL, logging.Err(err))
 					return false // 接続失敗、WebSocketを継続して待機
 				}
 
-				log.Printf("Successfully connected to backend: %s", newURL)
-				logger.LogMessage("INFO", fmt.Sprintf("Successfully connected to backend: %s", newURL))
+				rlog.Info("Successfully connected to backend", "url", newURL)
 				return true // 接続成功
 			},
 			func(msg string) {
-				log.Printf("[WebSocket] %s", msg)
-				logger.LogMessage("DEBUG", msg)
+				rlog.Debug(msg, "component", "websocket")
 			},
 		)
 		watcher.Start()
@@ -270,23 +384,72 @@ func main() {
 
 				hb, err := client.Heartbeat()
 				if err != nil {
-					log.Printf("Heartbeat failed: %v", err)
-					logger.LogMessage("WARNING", fmt.Sprintf("Heartbeat failed: %v", err))
+					rlog.Warn("Heartbeat failed", logging.Err(err))
 				} else {
-					log.Printf("Heartbeat OK (status: %s, timestamp: %s)", hb.Status, hb.Timestamp)
-					logger.LogMessage("DEBUG", fmt.Sprintf("Heartbeat OK: status=%s", hb.Status))
+					rlog.Debug("Heartbeat OK", "status", hb.Status, "timestamp", hb.Timestamp)
 				}
 			}
 		}()
 	} else {
-		log.Println("woff-cl endpoint or secret not configured, skipping woff-sv integration")
+		rlog.Info("woff-cl endpoint or secret not configured, skipping woff-sv integration")
 	}
 
-	// NFC リーダー初期化（内部ログはログファイルとDBの両方に出力）
+	// woff-svにTimeCardを送信（スレッドセーフ）
+	sendPunch := func(ctx context.Context, punch *database.PunchRecord) {
+		client := getWoffSvClient()
+		if client == nil {
+			return
+		}
+
+		machineIP := punch.ReaderID // Reader IDを使用
+
+		timeCard, err := client.CreateTimeCardAtContext(ctx, punch.Timestamp, punch.DriverID, punch.CardID, punch.State, machineIP)
+		if err != nil {
+			rlog.ErrorContext(ctx, "Failed to send time card to woff-sv", logging.KeyCardID, punch.CardID, logging.Err(err))
+			if punch.ID != 0 {
+				logger.MarkPunchFailed(punch.ID, err)
+			}
+			return
+		}
+
+		rlog.InfoContext(ctx, "Time card sent to woff-sv", "time_card_id", timeCard.Id, logging.KeyCardID, punch.CardID, "state", punch.State)
+		if punch.ID != 0 {
+			if err := logger.MarkPunchSent(punch.ID); err != nil {
+				rlog.ErrorContext(ctx, "Failed to mark punch sent", logging.Err(err))
+			}
+		}
+	}
+
+	// 未送信の打刻を定期的に再送（1分間隔）
+	outboxTicker := time.NewTicker(time.Minute)
+	defer outboxTicker.Stop()
+
+	go func() {
+		for range outboxTicker.C {
+			if getWoffSvClient() == nil {
+				continue
+			}
+
+			pending, err := logger.GetPendingPunches(50)
+			if err != nil {
+				rlog.Error("Failed to get pending punches", logging.Err(err))
+				continue
+			}
+
+			for _, punch := range pending {
+				// タッチ直後の打刻は読み取り処理側で送信中のため除く
+				if time.Since(punch.Timestamp) < 30*time.Second {
+					continue
+				}
+				sendPunch(context.Background(), punch)
+			}
+		}
+	}()
+
+	// NFC リーダー初期化（内部ログはDEBUGでコンソール・ファイル・DBに出力）
+	nfcLog := rlog.With("component", "nfc")
 	licenseReader, err := nfc.NewLicenseReader(func(msg string) {
-		// ログファイルとDBの両方に記録
-		log.Printf("[NFC] %s", msg)
-		logger.LogMessage("DEBUG", msg)
+		nfcLog.Debug(msg)
 	})
 	if err != nil {
 		log.Fatalf("Failed to initialize license reader: %v", err)
@@ -303,9 +466,144 @@ func main() {
 		log.Fatalf("No NFC readers found")
 	}
 
-	log.Printf("Found %d reader(s):", len(readers))
-	for i, reader := range readers {
-		log.Printf("  [%d] %s", i, reader)
+	rlog.Info("Found NFC readers", "count", len(readers), "devices", readers)
+
+	// アルコール検知器（設定されている場合のみ）
+	var correlator *detector.Correlator
+	if cfg.DetectorPort != "" {
+		parser, err := detector.ParserByName(cfg.DetectorFormat)
+		if err != nil {
+			log.Fatalf("Invalid detector format: %v", err)
+		}
+
+		device, err := detector.Open(cfg.DetectorPort)
+		if err != nil {
+			rlog.Warn("Failed to open alcohol detector", "port", cfg.DetectorPort, logging.Err(err))
+		} else {
+			defer device.Close()
+
+			correlator = detector.NewCorrelator(logger, time.Duration(cfg.DetectorWindow)*time.Second)
+			detectorLog := rlog.With("component", "detector")
+			detectorReader := detector.NewReader(device, parser, func(msg string) {
+				detectorLog.Debug(msg)
+			})
+
+			rlog.Info("Alcohol detector opened", "port", cfg.DetectorPort, "format", parser.Name(), "window", cfg.DetectorWindow)
+
+			go func() {
+				err := detectorReader.Run(func(m *detector.Measurement, err error) {
+					if err != nil {
+						detectorLog.Error("Invalid measurement", logging.Err(err))
+						return
+					}
+
+					record, err := correlator.OnMeasurement(*readerID, m)
+					if err != nil {
+						detectorLog.Error("Failed to record alcohol measurement", logging.Err(err))
+						return
+					}
+
+					rlog.Info("Alcohol measurement", "value_mg_l", m.Value, "result", m.Result,
+						"read_history_id", record.ReadHistoryID, logging.KeyCardID, record.CardID)
+				})
+				if err != nil {
+					detectorLog.Error("Alcohol detector stopped", logging.Err(err))
+				}
+			}()
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	stopRegistration := make(chan struct{})
+	defer close(stopRegistration)
+	go licenseClient.RunRegistration(stopRegistration, &pb.RegisterReaderRequest{
+		ReaderId:   *readerID,
+		Site:       cfg.Site,
+		Location:   cfg.Location,
+		Version:    Version,
+		BuildTime:  BuildTime,
+		NfcDevices: readers,
+		Hostname:   hostname,
+	}, func(msg string) {
+		rlog.Warn(msg, "component", "registration")
+	})
+
+	// Prometheusの/metrics（ローカルのみで公開するのがデフォルト）
+	if cfg.MetricsAddr != config.MetricsOff {
+		if err := metrics.RegisterReader(); err != nil {
+			log.Fatalf("Failed to register metrics: %v", err)
+		}
+		metrics.RegisterOutbox("upload", logger.CountPendingUploads)
+		metrics.RegisterOutbox("punch", logger.CountPendingPunches)
+		if cfg.Mode == config.ReaderModeDispatch {
+			metrics.RegisterOutbox("assignment", logger.CountPendingAssignments)
+		}
+		metrics.RegisterDBWriter(func() int { return logger.WriterStats().Queued }, func() uint64 { return logger.WriterStats().Dropped })
+
+		metricsServer, err := metrics.Serve(cfg.MetricsAddr)
+		if err != nil {
+			rlog.Warn("Failed to start metrics listener, metrics disabled", logging.Err(err))
+		} else {
+			defer metricsServer.Close()
+			rlog.Info("Metrics listening", "url", fmt.Sprintf("http://%s/metrics", cfg.MetricsAddr))
+		}
+	}
+
+	// 読み取りをDBの送信待ちに保存し、サーバーにまとめて送信（接続できない間は溜めておく）
+	uploader := license.NewUploader(licenseClient, logger, *readerID, func(msg string) {
+		rlog.Warn(msg, "component", "upload")
+	})
+	stopUpload := make(chan struct{})
+	defer close(stopUpload)
+	go uploader.Run(stopUpload)
+
+	// 保持期間を過ぎたログ・読み取り履歴を削除（端末のディスクが一杯にならないように）
+	retentionPolicy, err := retention.ParsePolicy(cfg.Retention.Policy)
+	if err != nil {
+		log.Fatalf("Invalid retention policy: %v", err)
+	}
+	rlog.Info("Retention configured", "policy", retentionPolicy.String())
+	pruner := retention.NewPruner(logger, retentionPolicy, time.Duration(cfg.Retention.Interval)*time.Hour, cfg.Retention.ArchiveDir, func(msg string) {
+		rlog.Info(msg, "component", "retention")
+	})
+	go pruner.Run(stopUpload)
+
+	// 配車モード（免許証→車検証のタッチで運転者と車両を割り当てる）
+	var pairer *dispatch.Pairer
+	if cfg.Mode == config.ReaderModeDispatch {
+		var cars eligibility.CarLookup
+		if cfg.DBServerAddr != "" {
+			cars = licenseClient
+		}
+
+		dispatchLog := func(msg string) {
+			rlog.Debug(msg, "component", "dispatch")
+		}
+
+		// 免許の種類が車両をカバーしない場合は配車を却下
+		check := eligibility.NewDispatchStep(cars, func(msg string) {
+			rlog.Warn(msg, "component", "eligibility")
+		})
+
+		pairer = dispatch.NewPairer(logger, time.Duration(cfg.DispatchWindow)*time.Second, check,
+			func(a *database.AssignmentRecord) error {
+				_, err := licenseClient.PushAssignment(&pb.Assignment{
+					ReaderId:      a.ReaderID,
+					LicenseCardId: a.LicenseCardID,
+					DriverId:      a.DriverID,
+					VehicleCardId: a.VehicleCardID,
+					VehicleId:     a.VehicleID,
+					AssignedAt:    a.Timestamp.Unix(),
+				})
+				return err
+			},
+			dispatchLog,
+		)
+
+		// 送信に失敗した割り当てを再送
+		go pairer.Run(stopUpload)
+
+		rlog.Info("Dispatch mode enabled", "server", cfg.ServerAddr, "window", cfg.DispatchWindow)
 	}
 
 	// シグナルハンドリング
@@ -314,19 +612,40 @@ func main() {
 
 	go func() {
 		<-sigChan
-		log.Println("\nShutting down...")
-		logger.LogMessage("INFO", "License reader stopped")
+		rlog.Info("License reader stopped")
+		flushTraces()
+		// 書き込みキューに残っているログ・読み取り履歴を書き込む
+		logger.Close()
 		os.Exit(0)
 	}()
 
+	// ログ・読み取り履歴はキューに溜めてまとめて書き込む（カードの読み取りはディスクへの書き込みを待たない）
+	logger.StartWriter(database.WriterOptions{
+		QueueSize:     cfg.DBWriter.QueueSize,
+		BatchSize:     cfg.DBWriter.BatchSize,
+		FlushInterval: time.Duration(cfg.DBWriter.FlushInterval) * time.Millisecond,
+		Overflow:      cfg.DBWriter.Overflow,
+		Logf: func(msg string) {
+			rlog.Warn(msg, "component", "db_writer")
+		},
+	})
+
 	// カード監視開始
-	log.Println("Monitoring for cards... (Press Ctrl+C to exit)")
-	logger.LogMessage("INFO", "Started monitoring for cards")
+	rlog.Info("Started monitoring for cards (Press Ctrl+C to exit)")
+
+	// 同じカードの連続タッチの判定用（MonitorCardsのコールバックからのみ使う）
+	lastTouch := make(map[string]time.Time)
 
-	err = licenseReader.MonitorCards(func(data *nfc.LicenseData, err error) {
+	err = licenseReader.MonitorCards(func(ctx context.Context, data *nfc.LicenseData, err error) {
 		if err != nil {
+			cardType := ""
+			if data != nil {
+				cardType = data.CardType
+			}
+			metrics.ReaderReads.WithLabelValues(metrics.CardTypeLabel(cardType), "error").Inc()
+
 			// エラーをログに記録
-			logger.LogMessage("ERROR", err.Error())
+			rlog.ErrorContext(ctx, "Card read failed", "card_type", cardType, logging.Err(err))
 
 			// データベースに記録
 			record := &database.ReadHistoryRecord{
@@ -336,21 +655,26 @@ func main() {
 				Status:       "error",
 				ErrorMessage: err.Error(),
 			}
-			logger.LogReadHistory(record)
+			logger.LogReadHistory(ctx, record, nil)
+
+			if err := uploader.EnqueueReadLog(&pb.ReadLog{
+				Timestamp:    time.Now().Unix(),
+				ReaderId:     *readerID,
+				Status:       "error",
+				ErrorMessage: err.Error(),
+				TraceParent:  tracing.TraceParent(ctx),
+			}); err != nil {
+				rlog.ErrorContext(ctx, "Failed to enqueue upload", logging.Err(err))
+			}
 
 			return
 		}
 
-		// Expiry DateとFeliCa UIDのみ表示
-		if data.ExpiryDate != "" {
-			log.Printf("Expiry Date: %s", data.ExpiryDate)
-		}
-		if data.FeliCaUID != "" {
-			log.Printf("FeliCa UID: %s", data.FeliCaUID)
-		}
-		if data.ExpiryDate == "" && data.FeliCaUID == "" {
-			log.Printf("Card Type: %s (No expiry date or FeliCa UID)", data.CardType)
-		}
+		metrics.ReaderReads.WithLabelValues(metrics.CardTypeLabel(data.CardType), "success").Inc()
+
+		// このタッチのログには全てcard_idを付ける（出力先では仮名のcard_ref、PII_KEYを設定していない場合は[redacted]に置き換わる）
+		cardLog := rlog.With(logging.KeyCardID, data.CardID)
+		cardLog.InfoContext(ctx, "Card read", "card_type", data.CardType)
 
 		// データベースに記録
 		record := &database.ReadHistoryRecord{
@@ -364,30 +688,112 @@ func main() {
 			Status:      "success",
 			Timestamp:   data.ReadTimestamp,
 		}
-		if err := logger.LogReadHistory(record); err != nil {
-			log.Printf("Failed to log read history: %v", err)
+		logger.LogReadHistory(ctx, record, func(err error) {
+			if err != nil {
+				cardLog.ErrorContext(ctx, "Failed to log read history", logging.Err(err))
+			} else if correlator != nil {
+				// 前後の測定結果をこの読み取りに紐付ける（書き込み後にrecord.IDが設定される）
+				if err := correlator.OnCardRead(record); err != nil {
+					cardLog.ErrorContext(ctx, "Failed to link alcohol measurement", logging.Err(err))
+				}
+			}
+		})
+
+		// サーバーへ送信（免許証はLicenseData、それ以外はReadLog）
+		var uploadErr error
+		if data.CardType == nfc.CardTypeDriverLicense {
+			uploadErr = uploader.EnqueueLicenseData(&pb.LicenseData{
+				CardId:        data.CardID,
+				ExpiryDate:    data.ExpiryDate,
+				ReadTimestamp: data.ReadTimestamp.Unix(),
+				ReaderId:      *readerID,
+				TraceParent:   tracing.TraceParent(ctx),
+			})
+		} else {
+			uploadErr = uploader.EnqueueReadLog(&pb.ReadLog{
+				Timestamp:   data.ReadTimestamp.Unix(),
+				ReaderId:    *readerID,
+				Status:      "success",
+				CardId:      data.CardID,
+				CardType:    data.CardType,
+				TraceParent: tracing.TraceParent(ctx),
+			})
+		}
+		if uploadErr != nil {
+			cardLog.ErrorContext(ctx, "Failed to enqueue upload", logging.Err(uploadErr))
 		}
 
-		// woff-svにTimeCardを送信（スレッドセーフ）
-		client := getWoffSvClient()
-		if client != nil {
-			driverID := int32(0) // 暫定的に0を使用
-			state := "in" // 出勤
-			machineIP := *readerID // Reader IDを使用
+		settings := remoteSettings.Load()
 
-			timeCard, err := client.CreateTimeCard(driverID, data.CardID, state, machineIP)
+		// クールダウン中の同じカードのタッチは打刻しない
+		if settings.DedupCooldownSeconds != nil && *settings.DedupCooldownSeconds > 0 && data.CardID != "" {
+			now := time.Now()
+			cooldown := time.Duration(*settings.DedupCooldownSeconds) * time.Second
+			if last, ok := lastTouch[data.CardID]; ok && now.Sub(last) < cooldown {
+				cardLog.DebugContext(ctx, "Duplicate touch ignored", "cooldown", cooldown)
+				return
+			}
+			lastTouch[data.CardID] = now
+		}
+
+		// 配車モードでは出退勤の代わりに割り当てを処理
+		if pairer != nil {
+			assignment, err := pairer.OnCardRead(record)
 			if err != nil {
-				log.Printf("Failed to send time card to woff-sv: %v", err)
-				logger.LogMessage("ERROR", fmt.Sprintf("Failed to send time card to woff-sv: %v", err))
-			} else {
-				log.Printf("Time card sent to woff-sv successfully: id=%d, state=%s", timeCard.Id, timeCard.State)
-				logger.LogMessage("INFO", fmt.Sprintf("Time card sent to woff-sv: id=%d, card_id=%s, state=%s",
-					timeCard.Id, data.CardID, state))
+				cardLog.ErrorContext(ctx, "Dispatch error", logging.Err(err))
 			}
+			if assignment != nil {
+				cardLog.InfoContext(ctx, "Assignment "+assignment.Status, "driver_id", assignment.DriverID,
+					"vehicle_id", assignment.VehicleID, "reason", assignment.Reason)
+			}
+			return
 		}
+
+		// 打刻を送信待ちに記録してからwoff-svに送信（失敗した場合は後で再送）
+		punch := &database.PunchRecord{
+			Timestamp: time.Now(),
+			ReaderID:  *readerID,
+			CardID:    data.CardID,
+			State:     punchState(logger, settings, data.CardID),
+		}
+		if license, err := logger.GetRegisteredLicense(data.CardID); err == nil && license != nil {
+			punch.DriverID = license.DriverID
+		}
+		if err := logger.EnqueuePunch(punch); err != nil {
+			cardLog.ErrorContext(ctx, "Failed to enqueue punch", logging.Err(err))
+		}
+
+		sendPunch(ctx, punch)
 	})
 
 	if err != nil {
-		log.Fatalf("Monitor error: %v", err)
+		rlog.Error("Monitor error", logging.Err(err))
+		logger.Close()
+		os.Exit(1)
+	}
+}
+
+// punchState 設定に従って打刻の種類を決める（設定がない場合は出勤）
+func punchState(logger *database.Logger, settings *remoteconfig.Settings, cardID string) string {
+	rule := remoteconfig.PunchStateIn
+	if settings.PunchState != nil {
+		rule = *settings.PunchState
+	}
+
+	if rule != remoteconfig.PunchStateAuto {
+		return rule
+	}
+
+	// 当日の直前の打刻が出勤なら退勤
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	last, err := logger.LastPunch(cardID, today)
+	if err != nil {
+		slog.Error("Failed to get last punch", logging.KeyCardID, cardID, logging.Err(err))
+		return remoteconfig.PunchStateIn
+	}
+	if last != nil && last.State == remoteconfig.PunchStateIn {
+		return remoteconfig.PunchStateOut
 	}
+	return remoteconfig.PunchStateIn
 }
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/remoteconfig"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  readerconfig set      [-db <path>] -scope global|site|reader [-target <site|reader-id>] (-file <settings.json> | -json '<json>') [-by <name>] [-note <text>]
+  readerconfig delete   [-db <path>] -scope global|site|reader [-target <site|reader-id>] [-by <name>] [-note <text>]
+  readerconfig list     [-db <path>] [-history]
+  readerconfig rollback [-db <path>] -version <n> [-by <name>]
+  readerconfig show     [-db <path>] -reader <reader-id> [-site <site>]
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetServerConfig()
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPath := fs.String("db", cfg.DBPath, "Server database path")
+
+	switch os.Args[1] {
+	case "set", "delete":
+		scope := fs.String("scope", database.ConfigScopeGlobal, "Scope: global, site, reader")
+		target := fs.String("target", "", "Site name (site scope) or reader ID (reader scope)")
+		by := fs.String("by", currentUser(), "Author recorded with the version")
+		note := fs.String("note", "", "Note recorded with the version")
+		var file, inline *string
+		if os.Args[1] == "set" {
+			file = fs.String("file", "", "Settings JSON file")
+			inline = fs.String("json", "", "Settings JSON")
+		}
+		fs.Parse(os.Args[2:])
+
+		if err := checkScope(*scope, target); err != nil {
+			log.Fatal(err)
+		}
+
+		settings := ""
+		if os.Args[1] == "set" {
+			data := *inline
+			if *file != "" {
+				b, err := os.ReadFile(*file)
+				if err != nil {
+					log.Fatalf("Failed to read settings file: %v", err)
+				}
+				data = string(b)
+			}
+			if data == "" {
+				usage()
+			}
+
+			// 保存前に検証し、正規化したJSONを保存する
+			s, err := remoteconfig.Parse(data)
+			if err != nil {
+				log.Fatal(err)
+			}
+			settings = s.JSON()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		version, err := logger.SaveReaderConfig(*scope, *target, settings, *by, *note)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if settings == "" {
+			fmt.Printf("Deleted %s config (version %d)\n", describe(*scope, *target), version)
+		} else {
+			fmt.Printf("Saved %s config as version %d: %s\n", describe(*scope, *target), version, settings)
+		}
+
+	case "list":
+		history := fs.Bool("history", false, "Show all versions including deletions")
+		fs.Parse(os.Args[2:])
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		records, err := logger.ListReaderConfigs(*history)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSCOPE\tTARGET\tCREATED\tBY\tSETTINGS\tNOTE")
+		for _, r := range records {
+			settings := r.Settings
+			if r.Deleted() {
+				settings = "(deleted)"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Version, r.Scope, r.Target, formatTime(r.CreatedAt), r.CreatedBy, settings, r.Note)
+		}
+		w.Flush()
+
+	case "rollback":
+		version := fs.Int64("version", 0, "Version to restore")
+		by := fs.String("by", currentUser(), "Author recorded with the version")
+		fs.Parse(os.Args[2:])
+		if *version == 0 {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		// 古いバージョンの内容を新しいバージョンとして保存する（リーダーには新しいバージョンとして配信される）
+		old, err := logger.GetReaderConfigVersion(*version)
+		if err != nil {
+			log.Fatal(err)
+		}
+		newVersion, err := logger.SaveReaderConfig(old.Scope, old.Target, old.Settings, *by,
+			fmt.Sprintf("rollback to version %d", old.Version))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Restored %s config from version %d as version %d\n", describe(old.Scope, old.Target), old.Version, newVersion)
+
+	case "show":
+		readerID := fs.String("reader", "", "Reader ID")
+		site := fs.String("site", "", "Site (defaults to the site the reader registered with)")
+		fs.Parse(os.Args[2:])
+		if *readerID == "" {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		if *site == "" {
+			readers, err := logger.ListReaders("")
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, r := range readers {
+				if r.ReaderID == *readerID {
+					*site = r.Site
+				}
+			}
+		}
+
+		records, err := logger.ApplicableReaderConfigs(*readerID, *site)
+		if err != nil {
+			log.Fatal(err)
+		}
+		version, settings, err := remoteconfig.Effective(records)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, r := range records {
+			if r.Deleted() {
+				continue
+			}
+			fmt.Printf("  %-28s version %d: %s\n", describe(r.Scope, r.Target), r.Version, r.Settings)
+		}
+		fmt.Printf("Effective config for %s (site %q): version %d\n%s\n", *readerID, *site, version, settings.JSON())
+
+	default:
+		usage()
+	}
+}
+
+// checkScope 適用範囲と対象を検証（globalの場合は対象を空にする）
+func checkScope(scope string, target *string) error {
+	switch scope {
+	case database.ConfigScopeGlobal:
+		*target = ""
+	case database.ConfigScopeSite, database.ConfigScopeReader:
+		if *target == "" {
+			return fmt.Errorf("-target is required for the %s scope", scope)
+		}
+	default:
+		return fmt.Errorf("unknown scope: %s", scope)
+	}
+	return nil
+}
+
+func describe(scope, target string) string {
+	if target == "" {
+		return scope
+	}
+	return fmt.Sprintf("%s %q", scope, target)
+}
+
+func currentUser() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return os.Getenv("USERNAME")
+}
+
+func openDB(path string) *database.Logger {
+	logger, err := database.NewLogger(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	return logger
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
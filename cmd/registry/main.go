@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/pii"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  registry license -db <path> -card <card_id> -driver <driver_id> -classes 普通,準中型 [-conditions 5t,AT]
+  registry vehicle -db <path> -card <card_id> -vehicle <vehicle_id> -inspection-expiry 2026-05-31
+                   [-class 中型] [-gross-weight 7980] [-max-load 3500] [-capacity 3] [-towing] [-manual]
+  registry show -db <path> -card <card_id>
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPath := fs.String("db", "license_reader.db", "SQLite database path")
+	cardID := fs.String("card", "", "Card ID (as shown in read_history)")
+
+	switch os.Args[1] {
+	case "license":
+		driverID := fs.Int("driver", 0, "Driver ID")
+		classes := fs.String("classes", "", "License classes (comma separated)")
+		conditions := fs.String("conditions", "", "License conditions: 5t, 8t, AT (comma separated)")
+		fs.Parse(os.Args[2:])
+
+		if *cardID == "" || *driverID == 0 || *classes == "" {
+			usage()
+		}
+
+		logger := openLogger(*dbPath)
+		defer logger.Close()
+
+		license := &database.RegisteredLicense{
+			CardID:   strings.ToUpper(*cardID),
+			DriverID: int32(*driverID),
+			Classes:  strings.Split(*classes, ","),
+		}
+		if *conditions != "" {
+			license.Conditions = strings.Split(*conditions, ",")
+		}
+		if err := logger.UpsertRegisteredLicense(license); err != nil {
+			log.Fatalf("Failed to register license: %v", err)
+		}
+		fmt.Printf("Registered license %s: driver=%d, classes=%v, conditions=%v\n",
+			license.CardID, license.DriverID, license.Classes, license.Conditions)
+
+	case "vehicle":
+		vehicleID := fs.String("vehicle", "", "Vehicle ID")
+		class := fs.String("class", "", "Vehicle class (普通/準中型/中型/大型/大型特殊/小型特殊)")
+		grossWeight := fs.Int("gross-weight", 0, "Gross vehicle weight in kg (車両総重量)")
+		maxLoad := fs.Int("max-load", 0, "Maximum load in kg (最大積載量)")
+		capacity := fs.Int("capacity", 0, "Passenger capacity (乗車定員)")
+		towing := fs.Bool("towing", false, "Tows a trailer that requires a 牽引 license")
+		manual := fs.Bool("manual", false, "Manual transmission")
+		expiry := fs.String("inspection-expiry", "", "Vehicle inspection expiry (YYYY-MM-DD)")
+		fs.Parse(os.Args[2:])
+
+		if *cardID == "" || *vehicleID == "" || *expiry == "" {
+			usage()
+		}
+		if *class == "" && *grossWeight == 0 && *maxLoad == 0 && *capacity == 0 {
+			log.Fatalf("Either -class or the vehicle specification (-gross-weight/-max-load/-capacity) is required")
+		}
+
+		inspectionExpiry, err := time.ParseInLocation("2006-01-02", *expiry, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid inspection expiry: %v", err)
+		}
+
+		logger := openLogger(*dbPath)
+		defer logger.Close()
+
+		vehicle := &database.RegisteredVehicle{
+			CardID:           strings.ToUpper(*cardID),
+			VehicleID:        *vehicleID,
+			VehicleClass:     *class,
+			GrossWeightKg:    *grossWeight,
+			MaxLoadKg:        *maxLoad,
+			Capacity:         *capacity,
+			Towing:           *towing,
+			Manual:           *manual,
+			InspectionExpiry: inspectionExpiry,
+		}
+		if err := logger.UpsertRegisteredVehicle(vehicle); err != nil {
+			log.Fatalf("Failed to register vehicle: %v", err)
+		}
+		fmt.Printf("Registered vehicle %s: vehicle=%s, class=%s, inspection_expiry=%s\n",
+			vehicle.CardID, vehicle.VehicleID, vehicle.VehicleClass, *expiry)
+
+	case "show":
+		fs.Parse(os.Args[2:])
+		if *cardID == "" {
+			usage()
+		}
+
+		logger := openLogger(*dbPath)
+		defer logger.Close()
+
+		id := strings.ToUpper(*cardID)
+		license, err := logger.GetRegisteredLicense(id)
+		if err != nil {
+			log.Fatalf("Failed to get license: %v", err)
+		}
+		vehicle, err := logger.GetRegisteredVehicle(id)
+		if err != nil {
+			log.Fatalf("Failed to get vehicle: %v", err)
+		}
+
+		switch {
+		case license != nil:
+			fmt.Printf("License %s: driver=%d, classes=%v, conditions=%v\n",
+				license.CardID, license.DriverID, license.Classes, license.Conditions)
+		case vehicle != nil:
+			fmt.Printf("Vehicle %s: vehicle=%s, class=%s, gross_weight=%dkg, max_load=%dkg, capacity=%d, inspection_expiry=%s\n",
+				vehicle.CardID, vehicle.VehicleID, vehicle.VehicleClass, vehicle.GrossWeightKg, vehicle.MaxLoadKg,
+				vehicle.Capacity, vehicle.InspectionExpiry.Format("2006-01-02"))
+		default:
+			fmt.Printf("Card %s is not registered\n", id)
+		}
+
+	default:
+		usage()
+	}
+}
+
+func openLogger(dbPath string) *database.Logger {
+	logger, err := database.NewLogger(dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+
+	// カードの識別子の列を暗号化する鍵束（PII_KEYが空の場合は暗号化しない）
+	cipher, err := pii.Open(config.GetReaderConfig().PIIKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII key: %v", err)
+	}
+	logger.SetCipher(cipher)
+	return logger
+}
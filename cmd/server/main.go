@@ -1,20 +1,45 @@
-// +build windows
-
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
-	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"menkyo_go/internal/attendance"
+	"menkyo_go/internal/certs"
 	"menkyo_go/internal/config"
+	"menkyo_go/internal/dashboard"
 	"menkyo_go/internal/database"
+	"menkyo_go/internal/license"
+	"menkyo_go/internal/logging"
+	"menkyo_go/internal/metrics"
+	"menkyo_go/internal/pii"
+	"menkyo_go/internal/retention"
+	"menkyo_go/internal/tracing"
+	"menkyo_go/internal/webhook"
+	pb "menkyo_go/proto/license"
+
+	"connectrpc.com/connect"
+	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var (
+	// ビルド時に埋め込まれる変数
+	Version   = "dev"
+	BuildTime = "unknown"
 )
 
 func main() {
@@ -24,109 +49,294 @@ func main() {
 	}
 
 	// 環境変数からデフォルト値を取得
-	cfg := config.GetReaderConfig()
+	cfg := config.GetServerConfig()
 
-	// コマンドラインフラグ
-	readerPath := flag.String("reader", "reader.exe", "Path to reader.exe")
-	readerID := flag.String("reader-id", cfg.ReaderID, "Reader ID")
-	dbPath := flag.String("db", "supervisor.db", "Supervisor database path")
-	restartDelay := flag.Duration("restart-delay", 5*time.Second, "Delay before restarting reader")
+	// コマンドラインフラグ（環境変数より優先される）
+	port := flag.Int("port", cfg.Port, "gRPC server port")
+	dbPath := flag.String("db", cfg.DBPath, "SQLite database path")
+	callback := flag.String("callback", cfg.Callback, "License data sink: none, log, jsonl:<path>")
+	shutdownTimeout := flag.Duration("shutdown-timeout", time.Duration(cfg.ShutdownTimeout)*time.Second, "Time to wait for in-flight RPCs on shutdown")
+	httpPort := flag.Int("http-port", cfg.HTTPPort, "Connect/gRPC-Web HTTP port for browsers (0 to disable)")
+	corsOrigins := flag.String("cors-origins", cfg.CORSOrigins, "Allowed CORS origins (comma separated, * for any)")
+	authMode := flag.String("auth", cfg.AuthMode, "Authentication: off, audit (log denials only), enforce")
+	expiryWarning := flag.Int("expiry-warning-days", cfg.ExpiryWarning, "Send webhook expiry warnings for licenses expiring within this many days (0 to disable)")
+	readerStaleAfter := flag.Duration("reader-stale-after", time.Duration(cfg.ReaderStaleAfter)*time.Second, "Flag readers without a heartbeat for this long")
+	dbServer := flag.String("db-server", cfg.DBServerAddr, "db_service address for kinmu_taikei in GetWorkHours (optional)")
 	flag.Parse()
 
-	log.Printf("Starting reader supervisor")
-	log.Printf("Reader path: %s", *readerPath)
-	log.Printf("Reader ID: %s", *readerID)
-	log.Printf("Restart delay: %v", *restartDelay)
+	log.Printf("Starting license server v%s (Built: %s)", Version, BuildTime)
+	log.Printf("Database path: %s", *dbPath)
 
-	// データベース初期化（ログ用）
+	// データベース初期化
 	logger, err := database.NewLogger(*dbPath)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer logger.Close()
 
-	logger.LogMessage("INFO", "Reader supervisor started")
+	// 読み取り履歴の個人データの列を暗号化する鍵束（PII_KEYが空の場合は暗号化しない）
+	cipher, err := pii.Open(cfg.PIIKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII key: %v", err)
+	}
+	logger.SetCipher(cipher)
 
-	// シグナルハンドリング
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// ログ（コンソール・日付ごとのファイル・DBのlogsテーブルに出力。カードの識別子は仮名にする。log.Printfも同じ出力先に送られる）
+	logLevel := new(slog.LevelVar)
+	if err := logging.SetLevel(logLevel, logger, cfg.LogLevel); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	_, logFile := logging.Setup(logging.Options{
+		Level:      logLevel,
+		Console:    os.Stdout,
+		Dir:        cfg.LogDir,
+		FilePrefix: "server",
+		DB:         logger,
+		Cipher:     cipher,
+	})
+	defer logFile.Close()
 
-	stopChan := make(chan struct{})
-	go func() {
-		<-sigChan
-		log.Println("\nShutting down supervisor...")
-		logger.LogMessage("INFO", "Reader supervisor stopped")
-		close(stopChan)
+	// トレース（リーダーから伝搬したトレースにサーバーの処理を追加する）
+	shutdownTracing, err := tracing.Setup(cfg.Tracing, "menkyo-server", Version)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("Failed to flush traces", logging.Err(err))
+		}
 	}()
+	slog.Info("Tracing configured", "exporter", cfg.Tracing)
 
-	// readerプロセスを監視・再起動（無制限）
-	restartCount := 0
-	for {
-		select {
-		case <-stopChan:
-			log.Println("Supervisor stopped")
-			return
-		default:
-			if restartCount > 0 {
-				log.Printf("Restarting reader (attempt %d) in %v...", restartCount, *restartDelay)
-				logger.LogMessage("WARNING", fmt.Sprintf("Restarting reader (attempt %d)", restartCount))
-				time.Sleep(*restartDelay)
-			}
+	// 受信データの通知先
+	sink, err := license.NewCallbackSink(*callback)
+	if err != nil {
+		log.Fatalf("Failed to initialize callback sink: %v", err)
+	}
+	defer sink.Close()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	if err != nil {
+		log.Fatalf("Failed to listen on port %d: %v", *port, err)
+	}
+
+	licenseServer := license.NewServer(logger, sink.Callback)
+	licenseServer.SetReaderStaleAfter(*readerStaleAfter)
+
+	// GetWorkHoursの勤務体系はdb_serviceのdb_Driversから取得（未指定の場合は全員DefaultRule）
+	if *dbServer != "" {
+		dbTLS, err := certs.ClientTLSConfig(cfg.DBTLS, nil)
+		if err != nil {
+			log.Fatalf("Failed to load db_service TLS config: %v", err)
+		}
+		dbClient, err := license.NewDBClient(*dbServer, license.ClientOptions{DBTLS: dbTLS})
+		if err != nil {
+			log.Fatalf("Failed to connect to db_service: %v", err)
+		}
+		defer dbClient.Close()
+		licenseServer.SetAttendanceEngine(attendance.NewEngine(dbClient, attendance.NewReadHistorySource(logger)))
+		slog.Info("Work hours use kinmu_taikei from db_service", "db_server", *dbServer)
+	}
+
+	// 応答のないリーダーを監視
+	stopMonitor := make(chan struct{})
+	defer close(stopMonitor)
+	go licenseServer.MonitorReaders(stopMonitor)
+
+	// Webhook（送信先はcmd/webhookで登録。送信待ちはDBに保存して再送する）
+	webhooks := webhook.NewDispatcher(logger, *expiryWarning, func(msg string) {
+		slog.Warn(msg, "component", "webhook")
+	})
+	licenseServer.SetWebhookDispatcher(webhooks)
+	go webhooks.Run(stopMonitor)
+
+	// 保持期間を過ぎた行を削除
+	retentionPolicy, err := retention.ParsePolicy(cfg.Retention.Policy)
+	if err != nil {
+		log.Fatalf("Invalid retention policy: %v", err)
+	}
+	slog.Info("Retention configured", "policy", retentionPolicy.String())
+	pruner := retention.NewPruner(logger, retentionPolicy, time.Duration(cfg.Retention.Interval)*time.Hour, cfg.Retention.ArchiveDir, func(msg string) {
+		slog.Info(msg, "component", "retention")
+	})
+	go pruner.Run(stopMonitor)
+
+	// TLS（証明書が設定されている場合。ファイルの更新は再起動なしで反映される）
+	tlsConfig, err := certs.ServerTLSConfig(cfg.TLS, func(msg string) {
+		slog.Info(msg, "component", "tls")
+	})
+	if err != nil {
+		log.Fatalf("Failed to load TLS config: %v", err)
+	}
+
+	// 認証・認可（mTLSのクライアント証明書またはAPIキー）
+	authorizer, err := license.NewAuthorizer(*authMode, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize authorizer: %v", err)
+	}
+	slog.Info("Auth configured", "mode", *authMode)
+
+	// Prometheusのメトリクス（/metricsはConnectのHTTPポートで公開）
+	grpcMetrics := grpcprom.NewServerMetrics(grpcprom.WithServerHandlingTimeHistogram())
+	if err := metrics.Register(grpcMetrics); err != nil {
+		log.Fatalf("Failed to register metrics: %v", err)
+	}
+	if err := metrics.RegisterServer(); err != nil {
+		log.Fatalf("Failed to register metrics: %v", err)
+	}
+	webhookCount := func(status string) func() (int, error) {
+		return func() (int, error) {
+			counts, err := logger.CountWebhookDeliveries()
+			return counts[status], err
+		}
+	}
+	metrics.RegisterOutbox("webhook", webhookCount(database.WebhookStatusPending))
+	metrics.RegisterWebhookDeadLetters(webhookCount(database.WebhookStatusDead))
+	metrics.RegisterDBWriter(func() int { return logger.WriterStats().Queued }, func() uint64 { return logger.WriterStats().Dropped })
+
+	// 認証で拒否された呼び出しもメトリクスに含める
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(license.ServerStatsHandler()),
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor(), authorizer.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor(), authorizer.StreamServerInterceptor()),
+	}
+	serverOpts = append(serverOpts, license.ServerKeepaliveOptions()...)
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		slog.Info("TLS enabled", "client_auth", clientAuthName(cfg.TLS))
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterLicenseReaderServer(grpcServer, licenseServer)
 
-			log.Printf("Starting reader process...")
-			logger.LogMessage("INFO", "Starting reader process")
+	// grpc.health.v1（ロードバランサー・監視用。サーバー全体と各サービスの状態）
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(pb.LicenseReader_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	grpcMetrics.InitializeMetrics(grpcServer)
+
+	// ブラウザ向けにConnect/gRPC-WebをHTTP/1.1とHTTP/2（TLS未設定の場合はh2c）で公開
+	var httpServer *http.Server
+	if *httpPort > 0 {
+		tracingInterceptor, err := license.ConnectTracingInterceptor()
+		if err != nil {
+			log.Fatalf("Failed to initialize tracing: %v", err)
+		}
 
-			// reader.exeを起動（supervisorと同じディレクトリを基準）
-			execPath, err := os.Executable()
-			if err != nil {
-				log.Printf("Failed to get executable path: %v", err)
-				logger.LogMessage("ERROR", fmt.Sprintf("Failed to get executable path: %v", err))
-				restartCount++
-				continue
+		mux := http.NewServeMux()
+		path, handler := licenseServer.NewConnectHandler(connect.WithInterceptors(tracingInterceptor, authorizer.ConnectInterceptor()))
+		mux.Handle(path, handler)
+		mux.Handle("/healthz", license.HealthHandler(healthServer))
+		mux.Handle("/metrics", metrics.Handler())
+		mux.Handle("/", dashboard.Handler())
+
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		if tlsConfig != nil {
+			protocols.SetHTTP2(true)
+		} else {
+			protocols.SetUnencryptedHTTP2(true)
+		}
+
+		httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", *httpPort),
+			Handler:           license.WithCORS(mux, splitOrigins(*corsOrigins)),
+			Protocols:         protocols,
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			slog.Info("Connect/gRPC-Web listening", "addr", httpServer.Addr)
+			var err error
+			if tlsConfig != nil {
+				// 証明書はTLSConfigから取得するのでファイル名は指定しない
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
 			}
-			execDir := filepath.Dir(execPath)
-			readerExePath := filepath.Join(execDir, *readerPath)
-
-			cmd := exec.Command(readerExePath, "--reader-id", *readerID)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-
-			if err := cmd.Start(); err != nil {
-				log.Printf("Failed to start reader: %v", err)
-				logger.LogMessage("ERROR", fmt.Sprintf("Failed to start reader: %v", err))
-				restartCount++
-				continue
+			if err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to serve HTTP: %v", err)
 			}
+		}()
+	}
+
+	// シグナルで停止（処理中のRPCの完了を待ち、タイムアウトしたら強制停止）
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-			log.Printf("Reader process started (PID: %d)", cmd.Process.Pid)
-			logger.LogMessage("INFO", fmt.Sprintf("Reader process started (PID: %d)", cmd.Process.Pid))
-
-			// プロセス終了を待機
-			done := make(chan error, 1)
-			go func() {
-				done <- cmd.Wait()
-			}()
-
-			select {
-			case <-stopChan:
-				// 停止シグナルを受信した場合、readerを終了
-				log.Println("Terminating reader process...")
-				if err := cmd.Process.Kill(); err != nil {
-					log.Printf("Failed to kill reader: %v", err)
-				}
-				return
-			case err := <-done:
-				if err != nil {
-					log.Printf("Reader process exited with error: %v", err)
-					logger.LogMessage("ERROR", fmt.Sprintf("Reader process exited with error: %v", err))
-					restartCount++
-				} else {
-					log.Printf("Reader process exited normally")
-					logger.LogMessage("INFO", "Reader process exited normally")
-					// 正常終了の場合は再起動カウントをリセット
-					restartCount = 0
-				}
+	go func() {
+		sig := <-sigChan
+		slog.Info("License server stopping", "signal", sig.String())
+
+		// 停止中は新しいリクエストを受けないようにロードバランサーに伝える
+		healthServer.Shutdown()
+
+		if httpServer != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+			if err := httpServer.Shutdown(ctx); err != nil {
+				slog.Warn("HTTP shutdown failed", logging.Err(err))
+				httpServer.Close()
 			}
+			cancel()
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(*shutdownTimeout):
+			slog.Warn("Graceful stop timed out, forcing stop", "timeout", *shutdownTimeout)
+			grpcServer.Stop()
+		}
+	}()
+
+	// ログ・読み取り履歴はキューに溜めてまとめて書き込む（停止時にlogger.Closeで残りを書き込む）
+	logger.StartWriter(database.WriterOptions{
+		QueueSize:     cfg.DBWriter.QueueSize,
+		BatchSize:     cfg.DBWriter.BatchSize,
+		FlushInterval: time.Duration(cfg.DBWriter.FlushInterval) * time.Millisecond,
+		Overflow:      cfg.DBWriter.Overflow,
+		Logf: func(msg string) {
+			slog.Warn(msg, "component", "db_writer")
+		},
+	})
+
+	slog.Info("License server started", "addr", listener.Addr().String())
+
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+
+	slog.Info("License server stopped")
+}
+
+// clientAuthName ログ表示用のクライアント証明書の要求
+func clientAuthName(t config.TLSConfig) string {
+	switch {
+	case t.ClientAuth != "":
+		return t.ClientAuth
+	case t.CAFile != "":
+		return config.ClientAuthRequest
+	default:
+		return config.ClientAuthNone
+	}
+}
+
+// splitOrigins カンマ区切りのオリジンを分割
+func splitOrigins(s string) []string {
+	var origins []string
+	for _, origin := range strings.Split(s, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
 		}
 	}
+	return origins
 }
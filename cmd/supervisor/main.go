@@ -0,0 +1,194 @@
+// +build windows
+
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"menkyo_go/internal/certs"
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/license"
+	"menkyo_go/internal/logging"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func main() {
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// 環境変数からデフォルト値を取得
+	cfg := config.GetReaderConfig()
+
+	// コマンドラインフラグ
+	readerPath := flag.String("reader", "reader.exe", "Path to reader.exe")
+	readerID := flag.String("reader-id", cfg.ReaderID, "Reader ID")
+	dbPath := flag.String("db", "supervisor.db", "Supervisor database path")
+	restartDelay := flag.Duration("restart-delay", 5*time.Second, "Delay before restarting reader")
+	serverAddr := flag.String("server", cfg.ServerAddr, "License server address for health checks")
+	healthInterval := flag.Duration("health-interval", time.Minute, "License server health check interval (0 to disable)")
+	flag.Parse()
+
+	// データベース初期化（ログ用）
+	logger, err := database.NewLogger(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer logger.Close()
+
+	// ログ（コンソール・日付ごとのファイル・DBのlogsテーブルに出力）
+	logLevel := new(slog.LevelVar)
+	if err := logging.SetLevel(logLevel, logger, cfg.LogLevel); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	_, logFile := logging.Setup(logging.Options{
+		Level:      logLevel,
+		Console:    os.Stdout,
+		Dir:        cfg.LogDir,
+		FilePrefix: "supervisor",
+		DB:         logger,
+	})
+	defer logFile.Close()
+
+	slog.Info("Reader supervisor started", "reader", *readerPath, logging.KeyReaderID, *readerID, "restart_delay", *restartDelay)
+
+	// シグナルハンドリング
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	stopChan := make(chan struct{})
+	go func() {
+		<-sigChan
+		slog.Info("Reader supervisor stopped")
+		close(stopChan)
+	}()
+
+	// ライセンスサーバーの状態を記録（readerの再起動では直らない障害の切り分け用）
+	if *healthInterval > 0 {
+		go monitorServerHealth(*serverAddr, cfg, *healthInterval, stopChan)
+	}
+
+	// readerプロセスを監視・再起動（無制限）
+	restartCount := 0
+	for {
+		select {
+		case <-stopChan:
+			slog.Info("Supervisor stopped")
+			return
+		default:
+			if restartCount > 0 {
+				slog.Warn("Restarting reader", "attempt", restartCount, "delay", *restartDelay)
+				time.Sleep(*restartDelay)
+			}
+
+			slog.Info("Starting reader process")
+
+			// reader.exeを起動（supervisorと同じディレクトリを基準）
+			execPath, err := os.Executable()
+			if err != nil {
+				slog.Error("Failed to get executable path", logging.Err(err))
+				restartCount++
+				continue
+			}
+			execDir := filepath.Dir(execPath)
+			readerExePath := filepath.Join(execDir, *readerPath)
+
+			cmd := exec.Command(readerExePath, "--reader-id", *readerID)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+
+			if err := cmd.Start(); err != nil {
+				slog.Error("Failed to start reader", "path", readerExePath, logging.Err(err))
+				restartCount++
+				continue
+			}
+
+			slog.Info("Reader process started", "pid", cmd.Process.Pid)
+
+			// プロセス終了を待機
+			done := make(chan error, 1)
+			go func() {
+				done <- cmd.Wait()
+			}()
+
+			select {
+			case <-stopChan:
+				// 停止シグナルを受信した場合、readerを終了
+				slog.Info("Terminating reader process", "pid", cmd.Process.Pid)
+				if err := cmd.Process.Kill(); err != nil {
+					slog.Error("Failed to kill reader", logging.Err(err))
+				}
+				return
+			case err := <-done:
+				if err != nil {
+					slog.Error("Reader process exited with error", logging.Err(err))
+					restartCount++
+				} else {
+					slog.Info("Reader process exited normally")
+					// 正常終了の場合は再起動カウントをリセット
+					restartCount = 0
+				}
+			}
+		}
+	}
+}
+
+// monitorServerHealth grpc.health.v1でライセンスサーバーの状態を確認し、変化した場合に記録する
+func monitorServerHealth(addr string, cfg *config.ReaderConfig, interval time.Duration, stop <-chan struct{}) {
+	serverTLS, err := certs.ClientTLSConfig(cfg.ServerTLS, nil)
+	if err != nil {
+		slog.Warn("Health check disabled", logging.Err(err))
+		return
+	}
+
+	client, err := license.NewClientWithOptions(addr, "", license.ClientOptions{
+		ServerTLS: serverTLS,
+		APIKey:    cfg.APIKey,
+	})
+	if err != nil {
+		slog.Warn("Health check disabled", logging.Err(err))
+		return
+	}
+	defer client.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		status, err := client.CheckHealth(ctx, "")
+		cancel()
+
+		current := status.String()
+		if err != nil {
+			current = err.Error()
+		}
+		if current != last {
+			if status == healthpb.HealthCheckResponse_SERVING {
+				slog.Info("License server healthy", "server", addr, "status", current)
+			} else {
+				slog.Warn("License server unhealthy", "server", addr, "status", current)
+			}
+			last = current
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	"menkyo_go/internal/database"
+	"menkyo_go/internal/pii"
 )
 
 func main() {
 	dbPath := flag.String("db", "license_server.db", "Database file path")
 	limit := flag.Int("limit", 30, "Number of logs to display")
+	keySpec := flag.String("key", os.Getenv("PII_KEY"), "PII key provider to decrypt read history: file:<path>, env:<name>, keystore:<name>")
+	showCardID := flag.Bool("show-card-id", false, "Show raw card IDs instead of card_ref")
 	flag.Parse()
 
 	logger, err := database.NewLogger(*dbPath)
@@ -19,6 +23,12 @@ func main() {
 	}
 	defer logger.Close()
 
+	cipher, err := pii.Open(*keySpec)
+	if err != nil {
+		log.Fatalf("Failed to load PII key: %v", err)
+	}
+	logger.SetCipher(cipher)
+
 	fmt.Printf("=== Recent Logs from %s ===\n\n", *dbPath)
 
 	logs, err := logger.GetRecentLogs(*limit)
@@ -34,15 +44,21 @@ func main() {
 		if readerID, ok := logEntry["reader_id"].(string); ok && readerID != "" {
 			fmt.Printf("  Reader: %s\n", readerID)
 		}
-		if cardID, ok := logEntry["card_id"].(string); ok && cardID != "" {
+		if cardID, ok := logEntry["card_id"].(string); ok && cardID != "" && *showCardID {
 			fmt.Printf("  Card: %s\n", cardID)
 		}
+		if cardRef, ok := logEntry["card_ref"].(string); ok && cardRef != "" {
+			fmt.Printf("  Card Ref: %s\n", cardRef)
+		}
+		if attrs, ok := logEntry["attrs"].(string); ok && attrs != "" {
+			fmt.Printf("  Attrs: %s\n", attrs)
+		}
 		fmt.Println()
 	}
 
-	fmt.Println("=== Read History ===\n")
+	fmt.Print("=== Read History ===\n\n")
 
-	history, err := logger.GetReadHistory(*limit)
+	history, _, err := logger.GetReadHistory("", "", 0, 0, int32(*limit))
 	if err != nil {
 		log.Fatalf("Failed to get read history: %v", err)
 	}
@@ -52,7 +68,12 @@ func main() {
 			record.Timestamp.Format("2006-01-02 15:04:05"),
 			record.ReaderID,
 			record.Status)
-		fmt.Printf("  Card ID: %s\n", record.CardID)
+		if *showCardID {
+			fmt.Printf("  Card ID: %s\n", record.CardID)
+		}
+		if record.CardRef != "" {
+			fmt.Printf("  Card Ref: %s\n", record.CardRef)
+		}
 		fmt.Printf("  Card Type: %s\n", record.CardType)
 		if record.ExpiryDate != "" {
 			fmt.Printf("  Expiry Date: %s\n", record.ExpiryDate)
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/webhook"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  webhook add         [-db <path>] -name <name> -url <url> [-format json|slack] [-events <e1,e2>] [-readers <id1,id2>] [-card-types <t1,t2>]
+  webhook list        [-db <path>]
+  webhook enable      [-db <path>] -id <id>
+  webhook disable     [-db <path>] -id <id>
+  webhook remove      [-db <path>] -id <id>
+  webhook test        [-db <path>] -id <id>
+  webhook deliveries  [-db <path>] [-status pending|delivered|dead] [-endpoint <id>] [-limit 50]
+  webhook deadletters [-db <path>] [-endpoint <id>] [-limit 50]
+  webhook retry       [-db <path>] -id <delivery-id>
+
+Events: %s
+`, strings.Join(webhook.EventTypes, ", "))
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	// .envファイルを読み込む
+	if err := config.LoadEnv(".env"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	cfg := config.GetServerConfig()
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPath := fs.String("db", cfg.DBPath, "Server database path")
+
+	switch os.Args[1] {
+	case "add":
+		name := fs.String("name", "", "Endpoint name")
+		rawURL := fs.String("url", "", "Endpoint URL (http or https)")
+		format := fs.String("format", webhook.FormatJSON, "Payload format: json, slack")
+		events := fs.String("events", "", "Comma-separated event types (default: all)")
+		readers := fs.String("readers", "", "Comma-separated reader IDs (default: all)")
+		cardTypes := fs.String("card-types", "", "Comma-separated card types (default: all)")
+		fs.Parse(os.Args[2:])
+
+		if *name == "" || *rawURL == "" {
+			usage()
+		}
+		if u, err := url.Parse(*rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Fatalf("Invalid url: %s", *rawURL)
+		}
+		if *format != webhook.FormatJSON && *format != webhook.FormatSlack {
+			log.Fatalf("Unknown format: %s", *format)
+		}
+		for _, e := range splitList(*events) {
+			if !slices.Contains(webhook.EventTypes, e) {
+				log.Fatalf("Unknown event type: %s (available: %s)", e, strings.Join(webhook.EventTypes, ", "))
+			}
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		endpoint := &database.WebhookEndpoint{
+			Name:      *name,
+			URL:       *rawURL,
+			Secret:    secret,
+			Format:    *format,
+			Events:    splitList(*events),
+			ReaderIDs: splitList(*readers),
+			CardTypes: splitList(*cardTypes),
+			Enabled:   true,
+		}
+		if err := logger.CreateWebhookEndpoint(endpoint); err != nil {
+			log.Fatalf("Failed to create webhook endpoint: %v", err)
+		}
+
+		// 署名の鍵は受信側の設定に必要（listでは表示しない）
+		fmt.Printf("Created webhook endpoint #%d (%s -> %s)\n", endpoint.ID, endpoint.Name, endpoint.URL)
+		fmt.Printf("Signing secret: %s\n", secret)
+
+	case "list":
+		fs.Parse(os.Args[2:])
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		endpoints, err := logger.ListWebhookEndpoints(false)
+		if err != nil {
+			log.Fatalf("Failed to list webhook endpoints: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tURL\tFORMAT\tEVENTS\tREADERS\tCARD TYPES\tSTATUS\tCREATED")
+		for _, e := range endpoints {
+			state := "enabled"
+			if !e.Enabled {
+				state = "disabled"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.ID, e.Name, e.URL, e.Format, joinList(e.Events), joinList(e.ReaderIDs), joinList(e.CardTypes),
+				state, formatTime(e.CreatedAt))
+		}
+		w.Flush()
+
+	case "enable", "disable":
+		id := fs.Int64("id", 0, "Endpoint ID")
+		fs.Parse(os.Args[2:])
+		if *id == 0 {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		enabled := os.Args[1] == "enable"
+		if err := logger.SetWebhookEndpointEnabled(*id, enabled); err != nil {
+			log.Fatal(err)
+		}
+		if enabled {
+			fmt.Printf("Enabled webhook endpoint #%d\n", *id)
+		} else {
+			fmt.Printf("Disabled webhook endpoint #%d\n", *id)
+		}
+
+	case "remove":
+		id := fs.Int64("id", 0, "Endpoint ID")
+		fs.Parse(os.Args[2:])
+		if *id == 0 {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		if err := logger.DeleteWebhookEndpoint(*id); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Removed webhook endpoint #%d\n", *id)
+
+	case "test":
+		id := fs.Int64("id", 0, "Endpoint ID")
+		fs.Parse(os.Args[2:])
+		if *id == 0 {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		endpoint, err := logger.GetWebhookEndpoint(*id)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		dispatcher := webhook.NewDispatcher(logger, 0, func(msg string) { log.Print(msg) })
+		statusCode, err := dispatcher.Test(ctx, endpoint)
+		if err != nil {
+			log.Fatalf("Test delivery to %s failed: %v", endpoint.URL, err)
+		}
+		fmt.Printf("Test delivery to %s succeeded (HTTP %d)\n", endpoint.URL, statusCode)
+
+	case "deliveries", "deadletters":
+		status := fs.String("status", "", "Filter by status: pending, delivered, dead")
+		endpointID := fs.Int64("endpoint", 0, "Filter by endpoint ID")
+		limit := fs.Int("limit", 50, "Maximum number of records")
+		fs.Parse(os.Args[2:])
+
+		if os.Args[1] == "deadletters" {
+			*status = database.WebhookStatusDead
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		deliveries, err := logger.ListWebhookDeliveries(*status, *endpointID, *limit)
+		if err != nil {
+			log.Fatalf("Failed to list webhook deliveries: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tENDPOINT\tEVENT\tSTATUS\tATTEMPTS\tLAST HTTP\tNEXT ATTEMPT\tCREATED\tDELIVERED\tLAST ERROR")
+		for _, d := range deliveries {
+			next := "-"
+			if d.Status == database.WebhookStatusPending {
+				next = formatTime(d.NextAttemptAt)
+			}
+			lastStatus := "-"
+			if d.LastStatus != 0 {
+				lastStatus = fmt.Sprint(d.LastStatus)
+			}
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+				d.ID, d.EndpointID, d.EventType, d.Status, d.Attempts, lastStatus, next,
+				formatTime(d.CreatedAt), formatTime(d.DeliveredAt), d.LastError)
+		}
+		w.Flush()
+
+	case "retry":
+		id := fs.Int64("id", 0, "Delivery ID (dead)")
+		fs.Parse(os.Args[2:])
+		if *id == 0 {
+			usage()
+		}
+
+		logger := openDB(*dbPath)
+		defer logger.Close()
+
+		// 実行中のサーバーが次の確認で送信する
+		if err := logger.RequeueWebhookDelivery(*id); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Requeued webhook delivery #%d\n", *id)
+
+	default:
+		usage()
+	}
+}
+
+func openDB(path string) *database.Logger {
+	logger, err := database.NewLogger(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	return logger
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// splitList カンマ区切りの文字列を分割（空要素は除く）
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// joinList 一覧表示用（空の場合は全て）
+func joinList(items []string) string {
+	if len(items) == 0 {
+		return "*"
+	}
+	return strings.Join(items, ",")
+}
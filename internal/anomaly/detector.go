@@ -0,0 +1,163 @@
+package anomaly
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"menkyo_go/internal/attendance"
+	"menkyo_go/internal/database"
+)
+
+// sameTapWindow 取得元が異なる打刻をこの間隔以内なら同じタッチとみなす
+// （リーダーの送信待ちテーブルとwoff-svの両方に同じ打刻が残るため）
+const sameTapWindow = 5 * time.Second
+
+// Detector 打刻の異常を検出する
+type Detector struct {
+	engine *attendance.Engine
+	store  *database.Logger
+}
+
+// NewDetector 新しいDetectorを作成
+// engineは打刻の取得元と勤務体系、storeは異常の記録先
+func NewDetector(engine *attendance.Engine, store *database.Logger) *Detector {
+	return &Detector{
+		engine: engine,
+		store:  store,
+	}
+}
+
+// Scan dayの打刻を検査して異常を記録し、新規に記録した異常を返す
+// 同じ日を何度検査しても同じ異常は重複して記録されない
+func (d *Detector) Scan(day time.Time) ([]*database.AnomalyRecord, error) {
+	anomalies, err := d.Detect(day)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []*database.AnomalyRecord
+	for _, anomaly := range anomalies {
+		isNew, err := d.store.LogAnomaly(anomaly)
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created = append(created, anomaly)
+		}
+	}
+
+	return created, nil
+}
+
+// Detect dayの打刻の異常を検出する（記録はしない）
+func (d *Detector) Detect(day time.Time) ([]*database.AnomalyRecord, error) {
+	from := startOfDay(day)
+	to := from.AddDate(0, 0, 1)
+	workDate := from.Format("2006-01-02")
+
+	// 日付をまたぐ勤務のため前後1日分の打刻も取得
+	punches, err := d.engine.Punches(from.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	punches = mergeSources(punches)
+
+	var anomalies []*database.AnomalyRecord
+	add := func(kind string, p attendance.Punch, detail string) {
+		anomalies = append(anomalies, &database.AnomalyRecord{
+			WorkDate:  workDate,
+			Kind:      kind,
+			DriverID:  p.DriverID,
+			CardID:    p.CardID,
+			PunchTime: p.Time,
+			Detail:    detail,
+		})
+	}
+
+	byDriver := make(map[int32][]attendance.Punch)
+	for _, p := range punches {
+		inDay := !p.Time.Before(from) && p.Time.Before(to)
+
+		if p.DriverID == 0 {
+			if inDay {
+				add(database.AnomalyUnregisteredCard, p, "card is not registered to a driver")
+			}
+			continue
+		}
+		byDriver[p.DriverID] = append(byDriver[p.DriverID], p)
+	}
+
+	for driverID, driverPunches := range byDriver {
+		rule := d.engine.Rule(driverID)
+
+		var prev *attendance.Punch
+		for i := range driverPunches {
+			p := driverPunches[i]
+			inDay := !p.Time.Before(from) && p.Time.Before(to)
+
+			if inDay && prev != nil && p.Time.Sub(prev.Time) < rule.DuplicateWindow {
+				add(database.AnomalyDuplicateTap, p, fmt.Sprintf("tapped again %s after previous tap", p.Time.Sub(prev.Time).Round(time.Second)))
+			}
+			if inDay && !rule.InWindow(p.Time) {
+				add(database.AnomalyOutsideWindow, p, fmt.Sprintf("punch at %s is outside %s shift window", p.Time.In(time.Local).Format("15:04"), rule.Name))
+			}
+			prev = &driverPunches[i]
+		}
+
+		for _, shift := range attendance.BuildShifts(driverPunches, rule, time.Now()) {
+			if shift.WorkDate != workDate {
+				continue
+			}
+
+			cardID := cardAt(driverPunches, shift)
+			if shift.MissingOut {
+				add(database.AnomalyMissingClockOut, attendance.Punch{DriverID: driverID, CardID: cardID, Time: shift.Start},
+					fmt.Sprintf("no clock-out within %s of clock-in", rule.MaxShift))
+			}
+			if shift.MissingIn {
+				add(database.AnomalyMissingClockIn, attendance.Punch{DriverID: driverID, CardID: cardID, Time: shift.End},
+					"clock-out without clock-in")
+			}
+		}
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool { return anomalies[i].PunchTime.Before(anomalies[j].PunchTime) })
+
+	return anomalies, nil
+}
+
+// mergeSources 取得元が異なる同じタッチを1つにまとめ、時刻順に並べる
+func mergeSources(punches []attendance.Punch) []attendance.Punch {
+	sorted := make([]attendance.Punch, len(punches))
+	copy(sorted, punches)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	lastByCard := make(map[string]attendance.Punch)
+	merged := make([]attendance.Punch, 0, len(sorted))
+	for _, p := range sorted {
+		if last, ok := lastByCard[p.CardID]; ok && last.Source != p.Source && p.Time.Sub(last.Time) <= sameTapWindow {
+			continue
+		}
+		lastByCard[p.CardID] = p
+		merged = append(merged, p)
+	}
+
+	return merged
+}
+
+// cardAt 勤務の打刻に使われたカードID
+func cardAt(punches []attendance.Punch, shift *attendance.Shift) string {
+	for _, p := range punches {
+		if p.Time.Equal(shift.Start) || p.Time.Equal(shift.End) {
+			return p.CardID
+		}
+	}
+	return ""
+}
+
+// startOfDay その日の0時
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,148 @@
+package anomaly
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"menkyo_go/internal/database"
+	authv1 "menkyo_go/proto/auth/v1"
+)
+
+// NoticeState 朝の異常サマリーをwoff-svに送るときのTimeCardLogのstate
+const NoticeState = "anomaly_summary"
+
+// Notifier サマリーの送信先（woffsv.AuthClientが実装）
+type Notifier interface {
+	PostNotice(state string, detail string, machineIP string) (*authv1.TimeCardLog, error)
+}
+
+// kindLabels 異常の種類の表示名
+var kindLabels = map[string]string{
+	database.AnomalyMissingClockOut:  "退勤打刻漏れ",
+	database.AnomalyMissingClockIn:   "出勤打刻漏れ",
+	database.AnomalyDuplicateTap:     "重複タッチ",
+	database.AnomalyUnregisteredCard: "未登録カード",
+	database.AnomalyOutsideWindow:    "時間帯外の打刻",
+}
+
+// Job 毎朝、前日までの打刻を検査してサマリーを送信する
+type Job struct {
+	detector *Detector
+	store    *database.Logger
+	notifier Notifier
+	runAt    time.Duration // 実行時刻（0時からの経過時間）
+	sender   string        // サマリーのmachine_ip
+	logf     func(string)
+}
+
+// NewJob 新しいJobを作成
+// notifierがnilの場合はサマリーを送信せず記録のみ行う
+func NewJob(detector *Detector, store *database.Logger, notifier Notifier, runAt time.Duration, sender string, logf func(string)) *Job {
+	if logf == nil {
+		logf = func(string) {}
+	}
+	return &Job{
+		detector: detector,
+		store:    store,
+		notifier: notifier,
+		runAt:    runAt,
+		sender:   sender,
+		logf:     logf,
+	}
+}
+
+// Run stopが閉じられるまで毎日runAtにRunOnceを実行
+func (j *Job) Run(stop <-chan struct{}) {
+	for {
+		next := nextRun(time.Now(), j.runAt)
+		j.logf(fmt.Sprintf("Next anomaly scan at %s", next.Format("2006-01-02 15:04")))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := j.RunOnce(time.Now()); err != nil {
+			j.logf(fmt.Sprintf("Anomaly scan failed: %v", err))
+		}
+	}
+}
+
+// RunOnce 前日と前々日を検査し、未解決の異常のサマリーを送信
+// 前々日も検査するのは、検査時点で勤務中だった夜勤の退勤打刻漏れを拾うため
+func (j *Job) RunOnce(now time.Time) error {
+	for _, days := range []int{-2, -1} {
+		day := now.AddDate(0, 0, days)
+		created, err := j.detector.Scan(day)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %w", day.Format("2006-01-02"), err)
+		}
+		j.logf(fmt.Sprintf("Anomaly scan %s: %d new", day.Format("2006-01-02"), len(created)))
+	}
+
+	unresolved, err := j.store.ListAnomalies("", false)
+	if err != nil {
+		return err
+	}
+
+	if j.notifier == nil || len(unresolved) == 0 {
+		return nil
+	}
+
+	if _, err := j.notifier.PostNotice(NoticeState, Summarize(unresolved), j.sender); err != nil {
+		return fmt.Errorf("failed to send anomaly summary: %w", err)
+	}
+	j.logf(fmt.Sprintf("Anomaly summary sent: %d unresolved", len(unresolved)))
+
+	return nil
+}
+
+// Summarize 未解決の異常のサマリー文を作成
+func Summarize(anomalies []*database.AnomalyRecord) string {
+	counts := make(map[string]int)
+	for _, a := range anomalies {
+		counts[a.Kind]++
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "未解決の打刻異常 %d件", len(anomalies))
+	for _, kind := range kinds {
+		fmt.Fprintf(&b, " / %s %d件", label(kind), counts[kind])
+	}
+	b.WriteString("\n")
+
+	for _, a := range anomalies {
+		// カードIDはwoff-svに送らない（未登録カードは#IDからanomaly listで確認する）
+		fmt.Fprintf(&b, "#%d %s %s driver=%d %s\n",
+			a.ID, a.WorkDate, label(a.Kind), a.DriverID, a.PunchTime.In(time.Local).Format("15:04"))
+	}
+
+	return b.String()
+}
+
+func label(kind string) string {
+	if l, ok := kindLabels[kind]; ok {
+		return l
+	}
+	return kind
+}
+
+// nextRun now以降で最初のrunAtの時刻
+func nextRun(now time.Time, runAt time.Duration) time.Time {
+	next := startOfDay(now).Add(runAt)
+	if !next.After(now) {
+		next = startOfDay(now.AddDate(0, 0, 1)).Add(runAt)
+	}
+	return next
+}
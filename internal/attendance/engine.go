@@ -0,0 +1,107 @@
+package attendance
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	dbpb "github.com/yhonda-ohishi/db_service/src/proto"
+)
+
+// DriverLookup db_serviceの運転者情報を取得する（license.Clientが実装）
+type DriverLookup interface {
+	GetDriver(id int32) (*dbpb.Db_Drivers, error)
+}
+
+// PunchSource 打刻の取得元
+type PunchSource interface {
+	// Punches from〜toの打刻を取得（運転者が特定できない打刻はDriverIDが0）
+	Punches(from, to time.Time) ([]Punch, error)
+}
+
+// Engine 打刻から勤務時間を集計する
+type Engine struct {
+	sources []PunchSource
+	drivers DriverLookup
+}
+
+// NewEngine 新しいEngineを作成
+// driversがnilの場合は全員をDefaultRuleで集計する
+func NewEngine(drivers DriverLookup, sources ...PunchSource) *Engine {
+	return &Engine{
+		sources: sources,
+		drivers: drivers,
+	}
+}
+
+// Compute from〜toの日付に出勤した勤務を集計する（driverIDが0の場合は全員）
+// fromとtoは日付（時刻は無視）で、toの日を含む
+func (e *Engine) Compute(from, to time.Time, driverID int32) ([]*Shift, error) {
+	from = startOfDay(from)
+	until := startOfDay(to).AddDate(0, 0, 1)
+
+	// 日付をまたぐ勤務のため前後1日分の打刻も取得
+	punches, err := e.Punches(from.AddDate(0, 0, -1), until.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	byDriver := make(map[int32][]Punch)
+	for _, p := range punches {
+		if p.DriverID == 0 || (driverID != 0 && p.DriverID != driverID) {
+			continue
+		}
+		byDriver[p.DriverID] = append(byDriver[p.DriverID], p)
+	}
+
+	var shifts []*Shift
+	for id, driverPunches := range byDriver {
+		for _, shift := range BuildShifts(driverPunches, e.Rule(id), time.Now()) {
+			if shift.WorkDate >= from.Format("2006-01-02") && shift.WorkDate < until.Format("2006-01-02") {
+				shifts = append(shifts, shift)
+			}
+		}
+	}
+
+	sort.Slice(shifts, func(i, j int) bool {
+		if shifts[i].DriverID != shifts[j].DriverID {
+			return shifts[i].DriverID < shifts[j].DriverID
+		}
+		return shifts[i].WorkDate < shifts[j].WorkDate
+	})
+
+	return shifts, nil
+}
+
+// Punches 全ての取得元からfrom〜toの打刻を集める
+func (e *Engine) Punches(from, to time.Time) ([]Punch, error) {
+	var punches []Punch
+	for _, source := range e.sources {
+		p, err := source.Punches(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get punches: %w", err)
+		}
+		punches = append(punches, p...)
+	}
+	return punches, nil
+}
+
+// Rule 運転者の勤務体系のルールを取得
+func (e *Engine) Rule(driverID int32) Rule {
+	if e.drivers == nil {
+		return DefaultRule
+	}
+
+	driver, err := e.drivers.GetDriver(driverID)
+	if err != nil || driver == nil {
+		return DefaultRule
+	}
+
+	return RuleFor(driver.KinmuTaikei)
+}
+
+// startOfDay その日の0時
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
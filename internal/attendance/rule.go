@@ -0,0 +1,67 @@
+package attendance
+
+import "time"
+
+// Rule 勤務体系ごとの集計ルール
+type Rule struct {
+	Name                 string
+	StandardMinutes      int           // 所定労働時間（これを超えると時間外）
+	MaxShift             time.Duration // 1勤務の最大長（超えた場合は退勤打刻漏れ）
+	MaxBreak             time.Duration // 退勤から出勤までがこれ以内なら休憩とみなす
+	DuplicateWindow      time.Duration // この間隔未満の連続打刻は二重打刻として無視
+	DeductStatutoryBreak bool          // 休憩打刻がない場合に法定休憩を差し引く
+	WindowStart          time.Duration // 打刻を受け付ける時間帯の開始（0時からの経過時間）
+	WindowEnd            time.Duration // 打刻を受け付ける時間帯の終了（StartとEndが共に0の場合は制限なし）
+}
+
+// InWindow 打刻が勤務時間帯内か
+func (r Rule) InWindow(t time.Time) bool {
+	if r.WindowStart == 0 && r.WindowEnd == 0 {
+		return true
+	}
+
+	t = t.In(time.Local)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+
+	if r.WindowStart <= r.WindowEnd {
+		return offset >= r.WindowStart && offset < r.WindowEnd
+	}
+	// 日付をまたぐ時間帯（例: 20時〜翌6時）
+	return offset >= r.WindowStart || offset < r.WindowEnd
+}
+
+// DefaultRule 勤務体系が不明な場合のルール
+var DefaultRule = Rule{
+	Name:                 "standard",
+	StandardMinutes:      8 * 60,
+	MaxShift:             20 * time.Hour,
+	MaxBreak:             3 * time.Hour,
+	DuplicateWindow:      time.Minute,
+	DeductStatutoryBreak: true,
+	WindowStart:          4 * time.Hour,
+	WindowEnd:            23 * time.Hour,
+}
+
+// Rules db_serviceのdb_Drivers.kinmu_taikeiごとのルール
+// 勤務体系の値は運用に合わせて追加・変更すること
+var Rules = map[int32]Rule{
+	0: DefaultRule,
+	1: DefaultRule,
+	// 長距離運行（拘束時間が長く、休憩は打刻で管理）
+	2: {
+		Name:                 "long_haul",
+		StandardMinutes:      8 * 60,
+		MaxShift:             24 * time.Hour,
+		MaxBreak:             8 * time.Hour,
+		DuplicateWindow:      time.Minute,
+		DeductStatutoryBreak: false,
+	},
+}
+
+// RuleFor 勤務体系のルールを取得
+func RuleFor(kinmuTaikei int32) Rule {
+	if rule, ok := Rules[kinmuTaikei]; ok {
+		return rule
+	}
+	return DefaultRule
+}
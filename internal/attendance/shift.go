@@ -0,0 +1,236 @@
+package attendance
+
+import (
+	"sort"
+	"time"
+)
+
+// 打刻の種別
+const (
+	StateIn  = "in"  // 出勤
+	StateOut = "out" // 退勤
+)
+
+// Punch 打刻
+type Punch struct {
+	DriverID int32
+	CardID   string
+	Time     time.Time
+	State    string // in/out（不明の場合は空。出勤と退勤を交互に扱う）
+	Source   string // 打刻元（read_history/woff-sv）
+}
+
+// Break 休憩
+type Break struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Shift 1回の勤務（出勤から退勤まで）
+type Shift struct {
+	DriverID         int32
+	WorkDate         string // 勤務日（出勤日。出勤打刻がない場合は退勤日）YYYY-MM-DD
+	Start            time.Time
+	End              time.Time
+	Breaks           []Break
+	MissingIn        bool // 出勤打刻なし
+	MissingOut       bool // 退勤打刻なし
+	InProgress       bool // 勤務中（集計時点で退勤前）
+	TotalMinutes     int  // 実働時間（休憩を除く）
+	BreakMinutes     int  // 休憩時間
+	OvertimeMinutes  int  // 時間外労働
+	LateNightMinutes int  // 深夜労働（22時〜5時）
+}
+
+// Complete 出勤・退勤の両方が揃っているか
+func (s *Shift) Complete() bool {
+	return !s.MissingIn && !s.MissingOut && !s.InProgress
+}
+
+// 打刻の状態
+type punchStatus int
+
+const (
+	statusOff     punchStatus = iota // 勤務外
+	statusWorking                    // 勤務中
+	statusOut                        // 退勤打刻後（休憩の可能性あり）
+)
+
+// BuildShifts 1人分の打刻を勤務に組み立てる
+// 日付をまたぐ勤務は出勤日の勤務とする。asOfは集計時点（それ以降の打刻はない前提）。
+func BuildShifts(punches []Punch, rule Rule, asOf time.Time) []*Shift {
+	sorted := make([]Punch, len(punches))
+	copy(sorted, punches)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var shifts []*Shift
+	var cur *Shift
+	status := statusOff
+	var last time.Time
+
+	closeShift := func() {
+		if cur != nil {
+			shifts = append(shifts, cur)
+		}
+		cur = nil
+		status = statusOff
+	}
+
+	startShift := func(p Punch) {
+		cur = &Shift{DriverID: p.DriverID, Start: p.Time}
+		status = statusWorking
+	}
+
+	for _, p := range sorted {
+		// 短時間の二重打刻は無視
+		if !last.IsZero() && p.Time.Sub(last) < rule.DuplicateWindow {
+			continue
+		}
+		last = p.Time
+
+		// 勤務時間の上限を超えた場合は退勤打刻漏れとして締める
+		if status == statusWorking && p.Time.Sub(cur.Start) > rule.MaxShift {
+			cur.MissingOut = true
+			closeShift()
+		}
+
+		state := p.State
+		if state == "" {
+			if status == statusWorking {
+				state = StateOut
+			} else {
+				state = StateIn
+			}
+		}
+
+		switch {
+		case state == StateIn && status == statusOff:
+			startShift(p)
+
+		case state == StateIn && status == statusWorking:
+			// 出勤が続いた場合は前の勤務の退勤打刻漏れ
+			cur.MissingOut = true
+			closeShift()
+			startShift(p)
+
+		case state == StateIn && status == statusOut:
+			if p.Time.Sub(cur.End) <= rule.MaxBreak && p.Time.Sub(cur.Start) <= rule.MaxShift {
+				// 休憩から戻った
+				cur.Breaks = append(cur.Breaks, Break{Start: cur.End, End: p.Time})
+				cur.End = time.Time{}
+				status = statusWorking
+			} else {
+				closeShift()
+				startShift(p)
+			}
+
+		case state == StateOut && status == statusOff:
+			// 出勤打刻漏れ
+			shifts = append(shifts, &Shift{DriverID: p.DriverID, End: p.Time, MissingIn: true})
+
+		case state == StateOut && status == statusWorking:
+			cur.End = p.Time
+			status = statusOut
+
+		case state == StateOut && status == statusOut:
+			// 退勤が続いた場合は後の打刻を退勤とする
+			cur.End = p.Time
+		}
+	}
+
+	if cur != nil && status == statusWorking {
+		if asOf.Sub(cur.Start) <= rule.MaxShift {
+			cur.InProgress = true
+		} else {
+			cur.MissingOut = true
+		}
+	}
+	closeShift()
+
+	for _, shift := range shifts {
+		shift.compute(rule)
+	}
+
+	return shifts
+}
+
+// compute 勤務日と各時間を計算
+func (s *Shift) compute(rule Rule) {
+	if !s.Start.IsZero() {
+		s.WorkDate = s.Start.In(time.Local).Format("2006-01-02")
+	} else {
+		s.WorkDate = s.End.In(time.Local).Format("2006-01-02")
+	}
+
+	if !s.Complete() {
+		return
+	}
+
+	total := s.End.Sub(s.Start)
+	lateNight := lateNightOverlap(s.Start, s.End)
+	var breaks time.Duration
+	for _, b := range s.Breaks {
+		breaks += b.End.Sub(b.Start)
+		lateNight -= lateNightOverlap(b.Start, b.End)
+	}
+
+	// 休憩の打刻がない場合は法定休憩を差し引く
+	worked := total - breaks
+	if breaks == 0 && rule.DeductStatutoryBreak {
+		breaks = statutoryBreak(worked)
+		worked -= breaks
+	}
+
+	s.BreakMinutes = int(breaks / time.Minute)
+	s.TotalMinutes = int(worked / time.Minute)
+	s.LateNightMinutes = int(lateNight / time.Minute)
+	if overtime := s.TotalMinutes - rule.StandardMinutes; overtime > 0 {
+		s.OvertimeMinutes = overtime
+	}
+}
+
+// statutoryBreak 労働時間に応じた法定休憩（6時間超で45分、8時間超で60分）
+func statutoryBreak(worked time.Duration) time.Duration {
+	switch {
+	case worked > 8*time.Hour:
+		return 60 * time.Minute
+	case worked > 6*time.Hour:
+		return 45 * time.Minute
+	default:
+		return 0
+	}
+}
+
+// lateNightOverlap 期間のうち深夜（22時〜翌5時）に当たる時間
+func lateNightOverlap(start, end time.Time) time.Duration {
+	start = start.In(time.Local)
+	end = end.In(time.Local)
+
+	var total time.Duration
+	// 前日22時から順に深夜帯を確認
+	day := time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, time.Local)
+	for !day.After(end) {
+		nightStart := day.Add(22 * time.Hour)
+		nightEnd := day.AddDate(0, 0, 1).Add(5 * time.Hour)
+		total += overlap(start, end, nightStart, nightEnd)
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return total
+}
+
+// overlap 2つの期間の重なり
+func overlap(aStart, aEnd, bStart, bEnd time.Time) time.Duration {
+	start := aStart
+	if bStart.After(start) {
+		start = bStart
+	}
+	end := aEnd
+	if bEnd.Before(end) {
+		end = bEnd
+	}
+	if end.After(start) {
+		return end.Sub(start)
+	}
+	return 0
+}
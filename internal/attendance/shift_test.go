@@ -0,0 +1,178 @@
+package attendance
+
+import (
+	"testing"
+	"time"
+)
+
+// at 2025年6月の日時（夏時間の切り替えがない時期）
+func at(day, hour, minute int) time.Time {
+	return time.Date(2025, 6, day, hour, minute, 0, 0, time.Local)
+}
+
+func punch(t time.Time, state string) Punch {
+	return Punch{DriverID: 1, Time: t, State: state}
+}
+
+func TestBuildShifts(t *testing.T) {
+	longHaul := RuleFor(2)
+
+	type want struct {
+		workDate   string
+		missingIn  bool
+		missingOut bool
+		inProgress bool
+		breaks     int
+		total      int
+		breakMin   int
+		overtime   int
+		lateNight  int
+	}
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		punches []Punch
+		asOf    time.Time
+		want    []want
+	}{
+		{
+			name:    "day shift with statutory break",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 8, 0), ""), punch(at(10, 17, 0), "")},
+			want:    []want{{workDate: "2025-06-10", total: 480, breakMin: 60}},
+		},
+		{
+			name:    "overnight shift belongs to start date",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 20, 0), ""), punch(at(11, 6, 0), "")},
+			want:    []want{{workDate: "2025-06-10", total: 540, breakMin: 60, overtime: 60, lateNight: 420}},
+		},
+		{
+			name: "out and in within MaxBreak is a break",
+			rule: DefaultRule,
+			punches: []Punch{
+				punch(at(10, 8, 0), ""), punch(at(10, 12, 0), ""),
+				punch(at(10, 13, 0), ""), punch(at(10, 17, 0), ""),
+			},
+			want: []want{{workDate: "2025-06-10", breaks: 1, total: 480, breakMin: 60}},
+		},
+		{
+			name: "out and in beyond MaxBreak starts a new shift",
+			rule: DefaultRule,
+			punches: []Punch{
+				punch(at(10, 8, 0), ""), punch(at(10, 12, 0), ""),
+				punch(at(10, 16, 0), ""), punch(at(10, 18, 0), ""),
+			},
+			want: []want{
+				{workDate: "2025-06-10", total: 240},
+				{workDate: "2025-06-10", total: 120},
+			},
+		},
+		{
+			name: "break during late night is not late-night work",
+			rule: longHaul,
+			punches: []Punch{
+				punch(at(10, 20, 0), ""), punch(at(10, 23, 0), ""),
+				punch(at(11, 1, 0), ""), punch(at(11, 6, 0), ""),
+			},
+			want: []want{{workDate: "2025-06-10", breaks: 1, total: 480, breakMin: 120, lateNight: 300}},
+		},
+		{
+			name:    "duplicate punch is ignored",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 8, 0), ""), punch(at(10, 8, 0).Add(30*time.Second), ""), punch(at(10, 17, 0), "")},
+			want:    []want{{workDate: "2025-06-10", total: 480, breakMin: 60}},
+		},
+		{
+			name:    "consecutive out keeps the later punch",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 8, 0), StateIn), punch(at(10, 17, 0), StateOut), punch(at(10, 17, 30), StateOut)},
+			want:    []want{{workDate: "2025-06-10", total: 510, breakMin: 60, overtime: 30}},
+		},
+		{
+			name:    "consecutive in means missing out",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 8, 0), StateIn), punch(at(10, 10, 0), StateIn), punch(at(10, 18, 0), StateOut)},
+			want: []want{
+				{workDate: "2025-06-10", missingOut: true},
+				{workDate: "2025-06-10", total: 435, breakMin: 45},
+			},
+		},
+		{
+			name:    "out without in",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 17, 0), StateOut)},
+			want:    []want{{workDate: "2025-06-10", missingIn: true}},
+		},
+		{
+			name:    "punch after MaxShift closes the shift",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 8, 0), ""), punch(at(11, 6, 0), "")},
+			want: []want{
+				{workDate: "2025-06-10", missingOut: true},
+				{workDate: "2025-06-11", missingOut: true},
+			},
+		},
+		{
+			name:    "open shift within MaxShift is in progress",
+			rule:    DefaultRule,
+			punches: []Punch{punch(at(10, 8, 0), "")},
+			asOf:    at(10, 10, 0),
+			want:    []want{{workDate: "2025-06-10", inProgress: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asOf := tt.asOf
+			if asOf.IsZero() {
+				asOf = at(20, 0, 0)
+			}
+
+			shifts := BuildShifts(tt.punches, tt.rule, asOf)
+			if len(shifts) != len(tt.want) {
+				t.Fatalf("BuildShifts() returned %d shifts, want %d", len(shifts), len(tt.want))
+			}
+			for i, s := range shifts {
+				got := want{
+					workDate:   s.WorkDate,
+					missingIn:  s.MissingIn,
+					missingOut: s.MissingOut,
+					inProgress: s.InProgress,
+					breaks:     len(s.Breaks),
+					total:      s.TotalMinutes,
+					breakMin:   s.BreakMinutes,
+					overtime:   s.OvertimeMinutes,
+					lateNight:  s.LateNightMinutes,
+				}
+				if got != tt.want[i] {
+					t.Errorf("shift %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLateNightOverlap(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       time.Duration
+	}{
+		{name: "daytime", start: at(10, 8, 0), end: at(10, 17, 0), want: 0},
+		{name: "evening", start: at(10, 21, 0), end: at(10, 23, 0), want: time.Hour},
+		{name: "early morning", start: at(10, 3, 0), end: at(10, 7, 0), want: 2 * time.Hour},
+		{name: "overnight", start: at(10, 20, 0), end: at(11, 8, 0), want: 7 * time.Hour},
+		{name: "two nights", start: at(10, 22, 0), end: at(12, 5, 0), want: 14 * time.Hour},
+		{name: "boundaries", start: at(10, 5, 0), end: at(10, 22, 0), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lateNightOverlap(tt.start, tt.end); got != tt.want {
+				t.Errorf("lateNightOverlap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package attendance
+
+import (
+	"time"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/woffsv"
+)
+
+// cardDrivers カードIDから運転者IDを引く（登録済み免許証を参照）
+type cardDrivers struct {
+	logger *database.Logger
+	cache  map[string]int32
+}
+
+func newCardDrivers(logger *database.Logger) *cardDrivers {
+	return &cardDrivers{logger: logger, cache: make(map[string]int32)}
+}
+
+// lookup 未登録のカードは0を返す
+func (c *cardDrivers) lookup(cardID string) int32 {
+	if id, ok := c.cache[cardID]; ok {
+		return id
+	}
+
+	var driverID int32
+	if license, err := c.logger.GetRegisteredLicense(cardID); err == nil && license != nil {
+		driverID = license.DriverID
+	}
+	c.cache[cardID] = driverID
+
+	return driverID
+}
+
+// ReadHistorySource read_historyの読み取り成功を打刻として扱う
+type ReadHistorySource struct {
+	logger *database.Logger
+}
+
+// NewReadHistorySource 新しいReadHistorySourceを作成
+func NewReadHistorySource(logger *database.Logger) *ReadHistorySource {
+	return &ReadHistorySource{logger: logger}
+}
+
+// Punches from〜toの打刻を取得
+func (s *ReadHistorySource) Punches(from, to time.Time) ([]Punch, error) {
+	records, err := s.logger.ListReadHistoryBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	drivers := newCardDrivers(s.logger)
+	punches := make([]Punch, 0, len(records))
+	for _, record := range records {
+		punches = append(punches, Punch{
+			DriverID: drivers.lookup(record.CardID),
+			CardID:   record.CardID,
+			Time:     record.Timestamp,
+			Source:   "read_history",
+		})
+	}
+
+	return punches, nil
+}
+
+// TimeCardLogSource woff-svのTimeCardLogを打刻として扱う
+// stateがin/outのTimeCardLogのみ打刻とする（異常サマリーなどのお知らせは除く）。
+// 出退勤の区別は打刻の順序から判定するため、stateの値そのものは使わない
+type TimeCardLogSource struct {
+	client *woffsv.AuthClient
+	logger *database.Logger
+}
+
+// NewTimeCardLogSource 新しいTimeCardLogSourceを作成
+// loggerはカードIDから運転者を引くために使う（nilの場合はTimeCardLogのIDのみ使用）
+func NewTimeCardLogSource(client *woffsv.AuthClient, logger *database.Logger) *TimeCardLogSource {
+	return &TimeCardLogSource{client: client, logger: logger}
+}
+
+// Punches from〜toの打刻を取得
+func (s *TimeCardLogSource) Punches(from, to time.Time) ([]Punch, error) {
+	logs, err := s.client.ListTimeCardLogsSince(from)
+	if err != nil {
+		return nil, err
+	}
+
+	var drivers *cardDrivers
+	if s.logger != nil {
+		drivers = newCardDrivers(s.logger)
+	}
+
+	var punches []Punch
+	for _, l := range logs {
+		if l.State != StateIn && l.State != StateOut {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, l.Datetime)
+		if err != nil || t.Before(from) || !t.Before(to) {
+			continue
+		}
+
+		driverID := l.Id
+		if driverID == 0 && drivers != nil {
+			driverID = drivers.lookup(l.CardId)
+		}
+
+		punches = append(punches, Punch{
+			DriverID: driverID,
+			CardID:   l.CardId,
+			Time:     t,
+			Source:   "woff-sv",
+		})
+	}
+
+	return punches, nil
+}
+
+// OutboxSource リーダーの打刻送信待ちテーブル（punch_outbox）を打刻として扱う
+// 送信済みの打刻も含むため、woff-svに届かなかった打刻も集計できる
+type OutboxSource struct {
+	logger *database.Logger
+}
+
+// NewOutboxSource 新しいOutboxSourceを作成
+func NewOutboxSource(logger *database.Logger) *OutboxSource {
+	return &OutboxSource{logger: logger}
+}
+
+// Punches from〜toの打刻を取得
+func (s *OutboxSource) Punches(from, to time.Time) ([]Punch, error) {
+	records, err := s.logger.ListPunchesBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	drivers := newCardDrivers(s.logger)
+	punches := make([]Punch, 0, len(records))
+	for _, record := range records {
+		driverID := record.DriverID
+		if driverID == 0 {
+			driverID = drivers.lookup(record.CardID)
+		}
+
+		punches = append(punches, Punch{
+			DriverID: driverID,
+			CardID:   record.CardID,
+			Time:     record.Timestamp,
+			Source:   "outbox",
+		})
+	}
+
+	return punches, nil
+}
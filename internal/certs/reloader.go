@@ -0,0 +1,122 @@
+// Package certs TLS/mTLSの設定と証明書の再読み込み
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"menkyo_go/internal/config"
+)
+
+// checkInterval 証明書ファイルの更新を確認する間隔
+const checkInterval = 30 * time.Second
+
+// Reloader 証明書・秘密鍵・CA証明書を読み込み、ファイルが更新されたら読み直す
+// 更新の確認はTLSハンドシェイク時に行う（最大checkIntervalに1回）
+type Reloader struct {
+	files config.TLSConfig
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+	logf      func(string)
+}
+
+// NewReloader 新しいReloaderを作成（初回の読み込みに失敗した場合はエラー）
+func NewReloader(files config.TLSConfig, logf func(string)) (*Reloader, error) {
+	if logf == nil {
+		logf = func(string) {}
+	}
+
+	r := &Reloader{
+		files:    files,
+		modTimes: make(map[string]time.Time),
+		logf:     logf,
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.lastCheck = time.Now()
+
+	return r, nil
+}
+
+// load ファイルを読み込む
+func (r *Reloader) load() error {
+	var cert *tls.Certificate
+	if r.files.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.files.CAFile != "" {
+		pem, err := os.ReadFile(r.files.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA file: %s", r.files.CAFile)
+		}
+	}
+
+	r.cert = cert
+	r.pool = pool
+	for _, path := range r.paths() {
+		if info, err := os.Stat(path); err == nil {
+			r.modTimes[path] = info.ModTime()
+		}
+	}
+
+	return nil
+}
+
+func (r *Reloader) paths() []string {
+	var paths []string
+	for _, path := range []string{r.files.CertFile, r.files.KeyFile, r.files.CAFile} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// current 現在の証明書とCA（ファイルが更新されていれば読み直す）
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastCheck) < checkInterval {
+		return r.cert, r.pool
+	}
+	r.lastCheck = time.Now()
+
+	changed := false
+	for _, path := range r.paths() {
+		if info, err := os.Stat(path); err == nil && !info.ModTime().Equal(r.modTimes[path]) {
+			changed = true
+		}
+	}
+
+	if changed {
+		// 証明書と秘密鍵の更新が揃っていない間は失敗するので、前回の証明書を使い続ける
+		if err := r.load(); err != nil {
+			r.logf(fmt.Sprintf("TLS reload failed, keeping previous certificate: %v", err))
+		} else {
+			r.logf("TLS certificates reloaded")
+		}
+	}
+
+	return r.cert, r.pool
+}
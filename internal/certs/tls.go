@@ -0,0 +1,119 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"menkyo_go/internal/config"
+)
+
+// ServerTLSConfig サーバー用のTLS設定を作成
+// files.ClientAuthがrequireの場合はCAFileで検証したクライアント証明書を必須とする（mTLS）
+// 証明書が設定されていない場合はnilを返す（TLSを使わない）
+func ServerTLSConfig(files config.TLSConfig, logf func(string)) (*tls.Config, error) {
+	if !files.ServerEnabled() {
+		return nil, nil
+	}
+
+	clientAuth := tls.NoClientCert
+	switch files.ClientAuth {
+	case "", config.ClientAuthNone:
+		if files.CAFile != "" {
+			clientAuth = tls.VerifyClientCertIfGiven
+		}
+	case config.ClientAuthRequest:
+		clientAuth = tls.VerifyClientCertIfGiven
+	case config.ClientAuthRequire:
+		clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth mode: %s", files.ClientAuth)
+	}
+
+	if clientAuth != tls.NoClientCert && files.CAFile == "" {
+		return nil, fmt.Errorf("client certificate verification requires a CA file")
+	}
+
+	reloader, err := NewReloader(files, logf)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: clientAuth,
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+
+	// ハンドシェイクごとに最新の証明書とCAを使う
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		cert, pool := reloader.current()
+		c := base.Clone()
+		c.GetConfigForClient = nil
+		c.Certificates = []tls.Certificate{*cert}
+		c.ClientCAs = pool
+		return c, nil
+	}
+
+	return base, nil
+}
+
+// ClientTLSConfig クライアント用のTLS設定を作成
+// CAFileが空の場合はOSの証明書ストアでサーバー証明書を検証する
+// CAFileもCertFileも設定されていない場合はnilを返す（TLSを使わない）
+func ClientTLSConfig(files config.TLSConfig, logf func(string)) (*tls.Config, error) {
+	if !files.ClientEnabled() {
+		return nil, nil
+	}
+
+	reloader, err := NewReloader(files, logf)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: files.ServerName,
+	}
+
+	if files.CertFile != "" {
+		c.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := reloader.current()
+			return cert, nil
+		}
+	}
+
+	if files.CAFile != "" {
+		// CAを再読み込みできるよう、標準の検証の代わりに最新のCAで検証する
+		c.InsecureSkipVerify = true
+		c.VerifyConnection = func(cs tls.ConnectionState) error {
+			_, pool := reloader.current()
+			return verifyServer(cs, pool)
+		}
+	}
+
+	return c, nil
+}
+
+// verifyServer サーバー証明書をCAとホスト名で検証
+func verifyServer(cs tls.ConnectionState, pool *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("server presented no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		DNSName:       cs.ServerName,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify server certificate: %w", err)
+	}
+	return nil
+}
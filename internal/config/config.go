@@ -10,20 +10,209 @@ import (
 
 // ServerConfig サーバー設定
 type ServerConfig struct {
-	Port   int
-	DBPath string
+	Port             int
+	DBPath           string
+	Callback         string // 受信した免許証データの通知先（none/log/jsonl:<path>）
+	ShutdownTimeout  int    // 停止時に処理中のRPCの完了を待つ時間（秒）
+	HTTPPort         int    // Connect/gRPC-Web（ブラウザ向け）のポート（0の場合は無効）
+	CORSOrigins      string // CORSで許可するオリジン（カンマ区切り、*で全て許可）
+	TLS              TLSConfig
+	AuthMode         string // 認証・認可（off / audit / enforce）
+	ReaderStaleAfter int    // この時間（秒）応答のないリーダーを応答なしとする
+	LogLevel         string // ログの最低レベル（コンソール・ファイル・DB共通）
+	LogDir           string // 日付ごとのログファイルの保存先（空の場合はファイルに出力しない）
+	ExpiryWarning    int    // 有効期限までこの日数以内の免許証の読み取りでWebhookの警告を送る（0の場合は送らない）
+	Tracing          string // トレースの出力先（none / otlp / file:<path>）
+	Retention        RetentionConfig
+	DBWriter         DBWriterConfig
+	PIIKey           string    // 個人データの列の暗号化の鍵束（file:<path> / env:<name> / keystore:<name>。空の場合は暗号化しない）
+	DBServerAddr     string    // db_serviceのアドレス（GetWorkHoursの勤務体系の取得用。空の場合は全員標準の勤務体系）
+	DBTLS            TLSConfig // db_serviceへの接続のTLS設定
 }
 
+// 認証・認可のモード
+const (
+	AuthModeOff     = "off"     // 認証しない
+	AuthModeAudit   = "audit"   // 拒否すべき呼び出しを監査ログに記録するが通す（導入時の確認用）
+	AuthModeEnforce = "enforce" // 拒否する
+)
+
+// TLSConfig TLS設定（ファイルはPEM形式。更新されると再起動なしで読み直す）
+type TLSConfig struct {
+	CAFile     string // CA証明書（サーバー: クライアント証明書の検証用、クライアント: サーバー証明書の検証用）
+	CertFile   string // 証明書
+	KeyFile    string // 秘密鍵
+	ServerName string // クライアントのみ: サーバー証明書のホスト名（接続先アドレスと異なる場合）
+	ClientAuth string // サーバーのみ: クライアント証明書の要求（none/request/require）
+}
+
+// クライアント証明書の要求
+const (
+	ClientAuthNone    = "none"
+	ClientAuthRequest = "request" // 提示された場合のみ検証
+	ClientAuthRequire = "require" // mTLS
+)
+
+// ServerEnabled サーバーとしてTLSを使うか
+func (t TLSConfig) ServerEnabled() bool {
+	return t.CertFile != ""
+}
+
+// ClientEnabled クライアントとしてTLSを使うか
+func (t TLSConfig) ClientEnabled() bool {
+	return t.CAFile != "" || t.CertFile != ""
+}
+
+// getLogLevel LOG_LEVELを取得（未設定の場合は全て記録）
+func getLogLevel() string {
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		return level
+	}
+	return "DEBUG"
+}
+
+// getLogDir LOG_DIRを取得（offの場合はファイルに出力しない）
+func getLogDir(defaultDir string) string {
+	switch dir := os.Getenv("LOG_DIR"); dir {
+	case "":
+		return defaultDir
+	case "off":
+		return ""
+	default:
+		return dir
+	}
+}
+
+// getTracing TRACING_EXPORTERを取得（未設定の場合は出力しない）
+func getTracing() string {
+	if exporter := os.Getenv("TRACING_EXPORTER"); exporter != "" {
+		return exporter
+	}
+	return "none"
+}
+
+// RetentionConfig 保持期間を過ぎた行の削除
+type RetentionConfig struct {
+	Policy     string // テーブル・ログレベルごとの保持期間（logs.DEBUG=7d,read_history=3y など。offの場合は削除しない）
+	Interval   int    // 削除の間隔（時間）
+	ArchiveDir string // 削除する行の書き出し先（空の場合は書き出さない）
+}
+
+// getRetention RETENTION_*を取得（デフォルトはDEBUGのログを7日、INFOのログを90日保持）
+func getRetention() RetentionConfig {
+	config := RetentionConfig{
+		Policy:   "logs.DEBUG=7d,logs.INFO=90d",
+		Interval: 6,
+	}
+
+	if policy := os.Getenv("RETENTION"); policy != "" {
+		config.Policy = policy
+	}
+
+	if interval := os.Getenv("RETENTION_INTERVAL_HOURS"); interval != "" {
+		if h, err := strconv.Atoi(interval); err == nil && h > 0 {
+			config.Interval = h
+		}
+	}
+
+	config.ArchiveDir = os.Getenv("RETENTION_ARCHIVE_DIR")
+
+	return config
+}
+
+// DBWriterConfig ログ・読み取り履歴の非同期書き込み
+type DBWriterConfig struct {
+	QueueSize     int    // キューの上限（件数。0の場合は非同期にしない）
+	BatchSize     int    // 1トランザクションで書き込む最大件数
+	FlushInterval int    // キューに溜まった行を書き込む間隔（ミリ秒）
+	Overflow      string // キューが満杯の場合の動作（drop: ログを破棄 / block: 空くまで待つ）
+}
+
+// getDBWriter DB_WRITE_*を取得
+func getDBWriter() DBWriterConfig {
+	config := DBWriterConfig{
+		QueueSize:     4096,
+		BatchSize:     256,
+		FlushInterval: 200,
+		Overflow:      "drop",
+	}
+
+	if size := os.Getenv("DB_WRITE_QUEUE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil && n >= 0 {
+			config.QueueSize = n
+		}
+	}
+
+	if size := os.Getenv("DB_WRITE_BATCH"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil && n > 0 {
+			config.BatchSize = n
+		}
+	}
+
+	if interval := os.Getenv("DB_WRITE_FLUSH_MS"); interval != "" {
+		if ms, err := strconv.Atoi(interval); err == nil && ms > 0 {
+			config.FlushInterval = ms
+		}
+	}
+
+	if overflow := os.Getenv("DB_WRITE_OVERFLOW"); overflow != "" {
+		config.Overflow = overflow
+	}
+
+	return config
+}
+
+// getTLSConfig prefixで始まる環境変数からTLS設定を取得
+// 例: prefixが"SERVER_"の場合はSERVER_TLS_CA_FILE、SERVER_TLS_CERT_FILEなど
+func getTLSConfig(prefix string) TLSConfig {
+	return TLSConfig{
+		CAFile:     os.Getenv(prefix + "TLS_CA_FILE"),
+		CertFile:   os.Getenv(prefix + "TLS_CERT_FILE"),
+		KeyFile:    os.Getenv(prefix + "TLS_KEY_FILE"),
+		ServerName: os.Getenv(prefix + "TLS_SERVER_NAME"),
+		ClientAuth: os.Getenv(prefix + "TLS_CLIENT_AUTH"),
+	}
+}
+
+// MetricsOff /metricsを公開しない（READER_METRICS_ADDR）
+const MetricsOff = "off"
+
 // ReaderConfig リーダー設定
 type ReaderConfig struct {
 	ServerAddr     string
 	DBPath         string
 	ReaderID       string
-	MySQLDSN       string // MySQL接続文字列
-	WoffClEndpoint string // woff-clエンドポイント
-	WoffClSecret   string // woff-clシークレット
+	Site           string    // 拠点（RegisterReaderで登録）
+	Location       string    // 設置場所（RegisterReaderで登録）
+	MySQLDSN       string    // MySQL接続文字列
+	WoffClEndpoint string    // woff-clエンドポイント
+	WoffClSecret   string    // woff-clシークレット
+	DetectorPort   string    // アルコール検知器のポート（COM3、/dev/ttyUSB0など。空の場合は無効）
+	DetectorFormat string    // 検知器の出力フォーマット（auto/kv/csv/value）
+	DetectorWindow int       // 測定とカード読み取りを紐付ける時間幅（秒）
+	Mode           string    // 動作モード（timecard: 出退勤、dispatch: 配車）
+	DispatchWindow int       // 免許証タッチ後に車検証タッチを待つ時間（秒）
+	DBServerAddr   string    // db_serviceのアドレス（車両情報の取得用。空の場合は使用しない）
+	ServerTLS      TLSConfig // ライセンスサーバーへの接続のTLS設定
+	CallTimeout    int       // ライセンスサーバー・db_serviceの呼び出しのタイムアウト（秒、再試行を含む）
+	APIKey         string    // ライセンスサーバーのAPIキー（mTLSを使わない場合）
+	DBTLS          TLSConfig // db_serviceへの接続のTLS設定
+	LogLevel       string    // ログの最低レベル（コンソール・ファイル・DB共通。サーバーからの設定で上書きされる）
+	LogDir         string    // 日付ごとのログファイルの保存先（空の場合はファイルに出力しない）
+	ConfigCache    string    // サーバーから受信した設定の保存先
+	MetricsAddr    string    // Prometheusの/metricsを公開するアドレス（offの場合は公開しない）
+	Tracing        string    // トレースの出力先（none / otlp / file:<path>）
+	Retention      RetentionConfig
+	DBWriter       DBWriterConfig
+	PIIKey         string // 個人データの列の暗号化の鍵束（file:<path> / env:<name> / keystore:<name>。空の場合は暗号化しない）
 }
 
+// リーダーの動作モード
+const (
+	ReaderModeTimeCard = "timecard"
+	ReaderModeDispatch = "dispatch"
+)
+
 // LoadEnv 環境変数を読み込む
 func LoadEnv(envFile string) error {
 	// .envファイルが存在する場合のみ読み込む
@@ -38,8 +227,20 @@ func LoadEnv(envFile string) error {
 // GetServerConfig サーバー設定を取得
 func GetServerConfig() *ServerConfig {
 	config := &ServerConfig{
-		Port:   50051,
-		DBPath: "license_server.db",
+		Port:             50051,
+		DBPath:           "license_server.db",
+		Callback:         "log",
+		ShutdownTimeout:  10,
+		HTTPPort:         8080,
+		AuthMode:         AuthModeOff,
+		ReaderStaleAfter: 180,
+		ExpiryWarning:    30,
+		LogLevel:         getLogLevel(),
+		LogDir:           getLogDir(""),
+		Tracing:          getTracing(),
+		Retention:        getRetention(),
+		DBWriter:         getDBWriter(),
+		PIIKey:           os.Getenv("PII_KEY"),
 	}
 
 	// 環境変数から取得
@@ -53,16 +254,70 @@ func GetServerConfig() *ServerConfig {
 		config.DBPath = dbPath
 	}
 
+	if callback := os.Getenv("SERVER_CALLBACK"); callback != "" {
+		config.Callback = callback
+	}
+
+	if timeout := os.Getenv("SERVER_SHUTDOWN_TIMEOUT"); timeout != "" {
+		if t, err := strconv.Atoi(timeout); err == nil {
+			config.ShutdownTimeout = t
+		}
+	}
+
+	if port := os.Getenv("SERVER_HTTP_PORT"); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			config.HTTPPort = p
+		}
+	}
+
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		config.CORSOrigins = origins
+	}
+
+	config.TLS = getTLSConfig("SERVER_")
+
+	if mode := os.Getenv("SERVER_AUTH_MODE"); mode != "" {
+		config.AuthMode = mode
+	}
+
+	if staleAfter := os.Getenv("SERVER_READER_STALE_AFTER"); staleAfter != "" {
+		if s, err := strconv.Atoi(staleAfter); err == nil && s > 0 {
+			config.ReaderStaleAfter = s
+		}
+	}
+
+	if expiryWarning := os.Getenv("SERVER_EXPIRY_WARNING_DAYS"); expiryWarning != "" {
+		if d, err := strconv.Atoi(expiryWarning); err == nil && d >= 0 {
+			config.ExpiryWarning = d
+		}
+	}
+
+	config.DBServerAddr = os.Getenv("DB_SERVER_ADDR")
+	config.DBTLS = getTLSConfig("DB_")
+
 	return config
 }
 
 // GetReaderConfig リーダー設定を取得
 func GetReaderConfig() *ReaderConfig {
 	config := &ReaderConfig{
-		ServerAddr: "localhost:50051",
-		DBPath:     "license_reader.db",
-		ReaderID:   "default",
-		MySQLDSN:   "", // デフォルトは空（環境変数から設定）
+		ServerAddr:     "localhost:50051",
+		DBPath:         "license_reader.db",
+		ReaderID:       "default",
+		LogLevel:       getLogLevel(),
+		LogDir:         getLogDir("log"),
+		Tracing:        getTracing(),
+		Retention:      getRetention(),
+		DBWriter:       getDBWriter(),
+		PIIKey:         os.Getenv("PII_KEY"),
+		ConfigCache:    "reader_config.json",
+		MetricsAddr:    "127.0.0.1:9464",
+		CallTimeout:    5,
+		MySQLDSN:       "", // デフォルトは空（環境変数から設定）
+		DetectorFormat: "auto",
+		DetectorWindow: 60,
+		Mode:           ReaderModeTimeCard,
+		DispatchWindow: 30,
 	}
 
 	// 環境変数から取得
@@ -78,6 +333,23 @@ func GetReaderConfig() *ReaderConfig {
 		config.ReaderID = readerID
 	}
 
+	if callTimeout := os.Getenv("GRPC_CALL_TIMEOUT"); callTimeout != "" {
+		if t, err := strconv.Atoi(callTimeout); err == nil && t > 0 {
+			config.CallTimeout = t
+		}
+	}
+
+	if configCache := os.Getenv("READER_CONFIG_CACHE"); configCache != "" {
+		config.ConfigCache = configCache
+	}
+
+	if metricsAddr := os.Getenv("READER_METRICS_ADDR"); metricsAddr != "" {
+		config.MetricsAddr = metricsAddr
+	}
+
+	config.Site = os.Getenv("READER_SITE")
+	config.Location = os.Getenv("READER_LOCATION")
+
 	if mysqlDSN := os.Getenv("MYSQL_DSN"); mysqlDSN != "" {
 		config.MySQLDSN = mysqlDSN
 	}
@@ -90,5 +362,37 @@ func GetReaderConfig() *ReaderConfig {
 		config.WoffClSecret = woffClSecret
 	}
 
+	if detectorPort := os.Getenv("DETECTOR_PORT"); detectorPort != "" {
+		config.DetectorPort = detectorPort
+	}
+
+	if detectorFormat := os.Getenv("DETECTOR_FORMAT"); detectorFormat != "" {
+		config.DetectorFormat = detectorFormat
+	}
+
+	if window := os.Getenv("DETECTOR_WINDOW"); window != "" {
+		if w, err := strconv.Atoi(window); err == nil {
+			config.DetectorWindow = w
+		}
+	}
+
+	if mode := os.Getenv("READER_MODE"); mode != "" {
+		config.Mode = mode
+	}
+
+	if window := os.Getenv("DISPATCH_WINDOW"); window != "" {
+		if w, err := strconv.Atoi(window); err == nil {
+			config.DispatchWindow = w
+		}
+	}
+
+	if dbServerAddr := os.Getenv("DB_SERVER_ADDR"); dbServerAddr != "" {
+		config.DBServerAddr = dbServerAddr
+	}
+
+	config.ServerTLS = getTLSConfig("GRPC_")
+	config.APIKey = os.Getenv("READER_API_KEY")
+	config.DBTLS = getTLSConfig("DB_")
+
 	return config
 }
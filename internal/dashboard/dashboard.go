@@ -0,0 +1,23 @@
+// Package dashboard ライセンスサーバーに組み込む運用ダッシュボード
+//
+// 静的ファイルのみで構成し、データはConnectのエンドポイント（/license.LicenseReader/*）から取得する。
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler ダッシュボードのHTTPハンドラー
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		// embedのパスは固定のため起こらない
+		panic(err)
+	}
+	return http.FileServer(http.FS(files))
+}
@@ -0,0 +1,401 @@
+// 免許証リーダー ダッシュボード
+// データはConnectプロトコル（JSON）で /license.LicenseReader/* から取得する
+"use strict";
+
+const SERVICE = "/license.LicenseReader/";
+
+const CARD_TYPES = {
+  driver_license: "運転免許証",
+  car_inspection: "車検証",
+  other: "その他",
+};
+
+// ---- Connect ----
+
+// headers リクエストヘッダー（サーバーの認証が有効な場合は管理者のAPIキーを付ける）
+function headers(contentType) {
+  const h = { "Content-Type": contentType, "Connect-Protocol-Version": "1" };
+  const key = localStorage.getItem("apiKey");
+  if (key) h.Authorization = "Bearer " + key;
+  return h;
+}
+
+// unary 単項RPCを呼び出す
+async function unary(method, request) {
+  const res = await fetch(SERVICE + method, {
+    method: "POST",
+    headers: headers("application/json"),
+    body: JSON.stringify(request || {}),
+  });
+  const body = await res.json().catch(() => ({}));
+  if (!res.ok) {
+    throw new Error(`${method}: ${body.message || body.code || res.status}`);
+  }
+  return body;
+}
+
+// serverStream サーバーストリーミングRPCを呼び出し、受信したメッセージごとにonMessageを呼ぶ
+async function serverStream(method, request, onMessage, signal) {
+  const json = new TextEncoder().encode(JSON.stringify(request || {}));
+  const envelope = new Uint8Array(5 + json.length);
+  new DataView(envelope.buffer).setUint32(1, json.length);
+  envelope.set(json, 5);
+
+  const res = await fetch(SERVICE + method, {
+    method: "POST",
+    headers: headers("application/connect+json"),
+    body: envelope,
+    signal,
+  });
+  if (!res.ok) {
+    const body = await res.json().catch(() => ({}));
+    throw new Error(`${method}: ${body.message || body.code || res.status}`);
+  }
+
+  const reader = res.body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = new Uint8Array(0);
+
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) return;
+
+    const merged = new Uint8Array(buffer.length + value.length);
+    merged.set(buffer);
+    merged.set(value, buffer.length);
+    buffer = merged;
+
+    while (buffer.length >= 5) {
+      const flags = buffer[0];
+      const length = new DataView(buffer.buffer, buffer.byteOffset).getUint32(1);
+      if (buffer.length < 5 + length) break;
+
+      const message = JSON.parse(decoder.decode(buffer.subarray(5, 5 + length)) || "{}");
+      buffer = buffer.slice(5 + length);
+
+      if (flags & 0x02) {
+        // ストリーム終了（エラーがあれば含まれる）
+        if (message.error) {
+          const err = new Error(message.error.message || message.error.code);
+          err.code = message.error.code;
+          throw err;
+        }
+        return;
+      }
+      onMessage(message);
+    }
+  }
+}
+
+// ---- 共通 ----
+
+const $ = (id) => document.getElementById(id);
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key === "class") node.className = value;
+    else node.setAttribute(key, value);
+  }
+  for (const child of children) {
+    node.append(child instanceof Node ? child : document.createTextNode(child ?? ""));
+  }
+  return node;
+}
+
+function formatTime(unix) {
+  const n = Number(unix || 0);
+  if (!n) return "";
+  const d = new Date(n * 1000);
+  const pad = (v) => String(v).padStart(2, "0");
+  return `${d.getFullYear()}-${pad(d.getMonth() + 1)}-${pad(d.getDate())} ${pad(d.getHours())}:${pad(d.getMinutes())}:${pad(d.getSeconds())}`;
+}
+
+function formatMinutes(m) {
+  const n = Number(m || 0);
+  return `${Math.floor(n / 60)}:${String(n % 60).padStart(2, "0")}`;
+}
+
+function showError(err) {
+  const node = $("error");
+  if (!err) {
+    node.hidden = true;
+    return;
+  }
+  node.textContent = String(err.message || err);
+  node.hidden = false;
+}
+
+function statusCell(status) {
+  return el("td", { class: "status-" + status }, status === "error" ? "エラー" : "成功");
+}
+
+// ---- 最近の読み取り ----
+
+let readsToken = "";
+
+async function loadReads(append) {
+  try {
+    showError(null);
+    const res = await unary("GetReadHistory", {
+      readerId: $("reads-reader").value.trim(),
+      cardType: $("reads-card-type").value,
+      status: $("reads-status").value,
+      limit: 50,
+      pageToken: append ? readsToken : "",
+    });
+
+    const body = $("reads-body");
+    if (!append) body.replaceChildren();
+    for (const e of res.entries || []) {
+      body.append(el("tr", null,
+        el("td", null, formatTime(e.timestamp)),
+        el("td", null, e.readerId),
+        el("td", null, e.cardId),
+        el("td", null, CARD_TYPES[e.cardType] || e.cardType),
+        el("td", null, e.expiryDate),
+        statusCell(e.status)));
+    }
+
+    readsToken = res.nextPageToken || "";
+    $("reads-more").hidden = !readsToken;
+    $("reads-count").textContent = `全${res.totalCount || 0}件`;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// ---- リーダー別エラー ----
+
+async function loadErrors() {
+  try {
+    showError(null);
+    const days = Number($("errors-range").value);
+    const startTime = Math.floor(Date.now() / 1000) - days * 86400;
+
+    // 期間内のエラーを最大5000件まで集計
+    const byReader = new Map();
+    let token = "";
+    let fetched = 0;
+    do {
+      const res = await unary("GetReadHistory", { status: "error", startTime, limit: 1000, pageToken: token });
+      for (const e of res.entries || []) {
+        const r = byReader.get(e.readerId) || { count: 0, last: 0, message: "" };
+        r.count++;
+        if (Number(e.timestamp) > r.last) {
+          r.last = Number(e.timestamp);
+          r.message = e.errorMessage;
+        }
+        byReader.set(e.readerId, r);
+      }
+      fetched += (res.entries || []).length;
+      token = res.nextPageToken || "";
+    } while (token && fetched < 5000);
+
+    const rows = [...byReader.entries()].sort((a, b) => b[1].count - a[1].count);
+    $("errors-body").replaceChildren(...rows.map(([reader, r]) => el("tr", null,
+      el("td", null, reader),
+      el("td", { class: "status-error" }, String(r.count)),
+      el("td", null, formatTime(r.last)),
+      el("td", { class: "wrap" }, r.message))));
+    $("errors-count").textContent = `${byReader.size}台 / ${fetched}件`;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// ---- ライブ ----
+
+let liveAbort = null;
+let liveEpoch = "";
+let liveSeq = 0;
+
+function liveItem(event) {
+  const time = el("span", { class: "time" }, formatTime(event.timestamp));
+  if (event.licenseData) {
+    const d = event.licenseData;
+    return el("li", null, time, `${event.readerId}: 運転免許証 ${d.cardId} ${d.name || ""}`);
+  }
+  const r = event.readLog || {};
+  const text = r.status === "error"
+    ? `${event.readerId}: エラー ${r.errorMessage || ""}`
+    : `${event.readerId}: ${CARD_TYPES[event.cardType] || "読み取り"} ${r.cardId || ""}`;
+  return el("li", { class: r.status === "error" ? "error" : "" }, time, text);
+}
+
+async function startLive() {
+  liveAbort = new AbortController();
+  $("live-toggle").textContent = "切断";
+
+  while (liveAbort && !liveAbort.signal.aborted) {
+    $("live-status").textContent = "接続中";
+    try {
+      await serverStream("WatchReads", {
+        readerId: $("live-reader").value.trim(),
+        resumeAfterSeq: String(liveSeq),
+        resumeEpoch: liveEpoch,
+      }, (event) => {
+        liveSeq = Number(event.seq);
+        liveEpoch = event.epoch || "";
+        const feed = $("live-feed");
+        feed.prepend(liveItem(event));
+        while (feed.children.length > 200) feed.lastChild.remove();
+      }, liveAbort.signal);
+    } catch (err) {
+      if (liveAbort && liveAbort.signal.aborted) break;
+      if (err.code === "out_of_range") {
+        // 切断中の読み取りがサーバーに残っていない（またはサーバーが再起動した）ため、表示をやり直して最新から受信する
+        liveSeq = 0;
+        liveEpoch = "";
+        $("live-feed").replaceChildren();
+        $("live-status").textContent = "切断中の読み取りを取得できませんでした（「最近の読み取り」で確認してください）";
+      } else {
+        $("live-status").textContent = `再接続待ち: ${err.message}`;
+      }
+    }
+    // 切断された場合は5秒後に続きから再接続
+    await new Promise((resolve) => setTimeout(resolve, 5000));
+  }
+}
+
+function stopLive() {
+  if (liveAbort) liveAbort.abort();
+  liveAbort = null;
+  $("live-toggle").textContent = "接続";
+  $("live-status").textContent = "未接続";
+}
+
+// ---- 運転者タイムライン ----
+
+async function loadTimeline() {
+  try {
+    showError(null);
+    const driverId = Number($("timeline-driver").value);
+    if (!driverId) throw new Error("運転者IDを入力してください");
+
+    const res = await unary("GetWorkHours", {
+      driverId,
+      startDate: $("timeline-from").value,
+      endDate: $("timeline-to").value,
+    });
+    const shifts = res.shifts || [];
+
+    // 24時間の軸に勤務を並べる（日付をまたぐ勤務は翌日分を切り詰めて表示）
+    const chart = $("timeline-chart");
+    const axis = el("div", { class: "axis" });
+    for (let h = 0; h <= 24; h += 3) {
+      const tick = el("span", null, `${h}時`);
+      tick.style.left = `${(h / 24) * 100}%`;
+      axis.append(tick);
+    }
+    chart.replaceChildren(axis);
+
+    for (const s of shifts) {
+      const row = el("div", { class: "row" }, el("span", { class: "label" }, s.workDate));
+      const dayStart = new Date(`${s.workDate}T00:00:00`).getTime() / 1000;
+      const start = Number(s.startTime) || dayStart;
+      const end = Number(s.endTime) || Math.min(Date.now() / 1000, start + 3600);
+      const left = Math.max(0, (start - dayStart) / 86400);
+      const width = Math.max(0.005, Math.min(1 - left, (end - start) / 86400));
+
+      const bar = el("div", { class: "bar" + (s.missingClockIn || s.missingClockOut ? " warn" : "") });
+      bar.style.left = `${left * 100}%`;
+      bar.style.width = `${width * 100}%`;
+      bar.title = `${formatTime(s.startTime) || "出勤打刻なし"} 〜 ${formatTime(s.endTime) || "退勤打刻なし"}`;
+      row.append(bar);
+      chart.append(row);
+    }
+
+    $("timeline-body").replaceChildren(...shifts.map((s) => {
+      const notes = [];
+      if (s.missingClockIn) notes.push("出勤打刻漏れ");
+      if (s.missingClockOut) notes.push("退勤打刻漏れ");
+      if (s.inProgress) notes.push("勤務中");
+      return el("tr", null,
+        el("td", null, s.workDate),
+        el("td", null, formatTime(s.startTime)),
+        el("td", null, formatTime(s.endTime)),
+        el("td", null, formatMinutes(s.totalMinutes)),
+        el("td", null, formatMinutes(s.breakMinutes)),
+        el("td", null, formatMinutes(s.overtimeMinutes)),
+        el("td", null, formatMinutes(s.lateNightMinutes)),
+        el("td", { class: notes.length ? "status-error" : "" }, notes.join("、")));
+    }));
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// ---- リーダー ----
+
+async function loadReaders() {
+  try {
+    showError(null);
+    const res = await unary("ListReaders", {
+      site: $("readers-site").value.trim(),
+      staleOnly: $("readers-stale").checked,
+    });
+    const readers = res.readers || [];
+
+    $("readers-body").replaceChildren(...readers.map((r) => el("tr", null,
+      el("td", null, r.readerId),
+      el("td", null, r.site),
+      el("td", null, r.location),
+      el("td", null, formatTime(r.lastSeen)),
+      el("td", { class: r.stale ? "status-error" : "status-success" }, r.stale ? "応答なし" : "稼働中"),
+      el("td", null, `${r.version || ""} (${r.buildTime || ""})`),
+      el("td", r.configError ? { class: "status-error wrap" } : null,
+        r.configError ? `v${r.configVersion || 0} 適用失敗: ${r.configError}` : (r.configVersion ? `v${r.configVersion}` : "-")),
+      el("td", { class: "wrap" }, (r.nfcDevices || []).join(", ")),
+      el("td", null, `${r.hostname || ""} ${r.peerAddr || ""}`))));
+
+    const stale = readers.filter((r) => r.stale).length;
+    $("readers-count").textContent = `${readers.length}台（応答なし${stale}台、${Math.round((res.staleAfterSeconds || 0) / 60)}分以上応答がない場合）`;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// ---- 画面切り替え ----
+
+function showView(name) {
+  for (const button of document.querySelectorAll("nav button")) {
+    button.classList.toggle("active", button.dataset.view === name);
+  }
+  for (const view of document.querySelectorAll(".view")) {
+    view.classList.toggle("active", view.id === "view-" + name);
+  }
+  showError(null);
+
+  if (name === "reads") loadReads(false);
+  if (name === "errors") loadErrors();
+  if (name === "readers") loadReaders();
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  const apiKey = $("api-key");
+  apiKey.value = localStorage.getItem("apiKey") || "";
+  apiKey.addEventListener("change", () => {
+    localStorage.setItem("apiKey", apiKey.value.trim());
+    showView(document.querySelector("nav button.active").dataset.view);
+  });
+
+  for (const button of document.querySelectorAll("nav button")) {
+    button.addEventListener("click", () => showView(button.dataset.view));
+  }
+
+  $("reads-refresh").addEventListener("click", () => loadReads(false));
+  $("reads-more").addEventListener("click", () => loadReads(true));
+  $("errors-refresh").addEventListener("click", loadErrors);
+  $("live-toggle").addEventListener("click", () => (liveAbort ? stopLive() : startLive()));
+  $("timeline-refresh").addEventListener("click", loadTimeline);
+  $("readers-refresh").addEventListener("click", loadReaders);
+
+  const today = new Date();
+  const weekAgo = new Date(today.getTime() - 6 * 86400000);
+  const iso = (d) => `${d.getFullYear()}-${String(d.getMonth() + 1).padStart(2, "0")}-${String(d.getDate()).padStart(2, "0")}`;
+  $("timeline-from").value = iso(weekAgo);
+  $("timeline-to").value = iso(today);
+
+  showView("reads");
+});
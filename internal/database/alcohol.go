@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AlcoholMeasurementRecord アルコール測定レコード
+type AlcoholMeasurementRecord struct {
+	ID            int64
+	Timestamp     time.Time
+	ReaderID      string
+	ReadHistoryID int64 // 紐付いた読み取り履歴のID（未紐付けは0）
+	CardID        string
+	Value         float64 // 呼気アルコール濃度 (mg/L)
+	Result        string  // 判定結果 (pass/fail)
+	DeviceTime    time.Time
+	Raw           string
+}
+
+// LogAlcoholMeasurement アルコール測定結果を記録
+func (l *Logger) LogAlcoholMeasurement(record *AlcoholMeasurementRecord) error {
+	query := `INSERT INTO alcohol_measurements
+		(reader_id, read_history_id, card_id, card_id_hash, value, result, device_time, raw, process_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	cardID, cardIDHash, err := l.cardColumns("alcohol_measurements.card_id", record.CardID)
+	if err != nil {
+		return err
+	}
+
+	var readHistoryID sql.NullInt64
+	if record.ReadHistoryID != 0 {
+		readHistoryID = sql.NullInt64{Int64: record.ReadHistoryID, Valid: true}
+	}
+
+	var deviceTime sql.NullString
+	if !record.DeviceTime.IsZero() {
+		deviceTime = sql.NullString{String: record.DeviceTime.Format("2006-01-02 15:04:05"), Valid: true}
+	}
+
+	result, err := l.db.Exec(query,
+		record.ReaderID,
+		readHistoryID,
+		cardID,
+		cardIDHash,
+		record.Value,
+		record.Result,
+		deviceTime,
+		record.Raw,
+		l.processID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert alcohol measurement: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	record.ID = id
+
+	return nil
+}
+
+// LinkAlcoholMeasurement 記録済みの測定結果を読み取り履歴に紐付ける
+func (l *Logger) LinkAlcoholMeasurement(id, readHistoryID int64, cardID string) error {
+	query := `UPDATE alcohol_measurements SET read_history_id = ?, card_id = ?, card_id_hash = ? WHERE id = ?`
+
+	encrypted, cardIDHash, err := l.cardColumns("alcohol_measurements.card_id", cardID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := l.db.Exec(query, readHistoryID, encrypted, cardIDHash, id); err != nil {
+		return fmt.Errorf("failed to link alcohol measurement: %w", err)
+	}
+
+	return nil
+}
+
+// GetAlcoholMeasurements 読み取り履歴に紐付いた測定結果を取得
+func (l *Logger) GetAlcoholMeasurements(readHistoryID int64) ([]*AlcoholMeasurementRecord, error) {
+	query := `SELECT id, timestamp, reader_id, read_history_id, card_id, value, result, device_time, raw
+		FROM alcohol_measurements
+		WHERE read_history_id = ?
+		ORDER BY timestamp`
+
+	rows, err := l.db.Query(query, readHistoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alcohol measurements: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*AlcoholMeasurementRecord
+
+	for rows.Next() {
+		record := &AlcoholMeasurementRecord{}
+		var timestamp string
+		var historyID sql.NullInt64
+		var cardID, deviceTime, raw sql.NullString
+
+		if err := rows.Scan(
+			&record.ID,
+			&timestamp,
+			&record.ReaderID,
+			&historyID,
+			&cardID,
+			&record.Value,
+			&record.Result,
+			&deviceTime,
+			&raw,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		record.Timestamp = parseTimestamp(timestamp)
+		if historyID.Valid {
+			record.ReadHistoryID = historyID.Int64
+		}
+		if record.CardID, err = l.cipher.Decrypt("alcohol_measurements.card_id", cardID.String); err != nil {
+			return nil, err
+		}
+		if deviceTime.Valid {
+			record.DeviceTime, _ = time.ParseInLocation("2006-01-02 15:04:05", deviceTime.String, time.Local)
+		}
+		if raw.Valid {
+			record.Raw = raw.String
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAnomalyNotFound 指定したIDの異常がない
+var ErrAnomalyNotFound = errors.New("anomaly not found")
+
+// 打刻の異常の種類
+const (
+	AnomalyMissingClockOut  = "missing_clock_out" // 退勤打刻漏れ
+	AnomalyMissingClockIn   = "missing_clock_in"  // 出勤打刻漏れ
+	AnomalyDuplicateTap     = "duplicate_tap"     // 短時間の重複タッチ
+	AnomalyUnregisteredCard = "unregistered_card" // 未登録カードの打刻
+	AnomalyOutsideWindow    = "outside_window"    // 勤務時間帯外の打刻
+)
+
+// AnomalyRecord 打刻の異常レコード
+type AnomalyRecord struct {
+	ID             int64
+	DetectedAt     time.Time
+	WorkDate       string // YYYY-MM-DD
+	Kind           string
+	DriverID       int32
+	CardID         string
+	PunchTime      time.Time
+	Detail         string
+	Resolved       bool
+	ResolvedAt     time.Time
+	ResolvedBy     string
+	ResolutionNote string
+}
+
+// LogAnomaly 異常を記録（同じ打刻の同じ種類の異常が既にある場合は記録しない）
+// 新規に記録した場合はtrueを返す。暗号化する場合はcard_id_hashで重複を判定する
+func (l *Logger) LogAnomaly(record *AnomalyRecord) (bool, error) {
+	query := `INSERT OR IGNORE INTO punch_anomalies (work_date, kind, driver_id, card_id, card_id_hash, punch_time, detail)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	cardID, cardIDHash, err := l.cardColumns("punch_anomalies.card_id", record.CardID)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := l.db.Exec(query,
+		record.WorkDate,
+		record.Kind,
+		record.DriverID,
+		cardID,
+		cardIDHash,
+		record.PunchTime.UTC().Format(timestampLayout),
+		record.Detail,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert anomaly: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	id, _ := result.LastInsertId()
+	record.ID = id
+
+	return true, nil
+}
+
+// ListAnomalies 異常一覧を取得（workDateが空の場合は全日、includeResolvedがfalseの場合は未解決のみ）
+func (l *Logger) ListAnomalies(workDate string, includeResolved bool) ([]*AnomalyRecord, error) {
+	query := `SELECT id, detected_at, work_date, kind, driver_id, card_id, punch_time, detail,
+		resolved, resolved_at, resolved_by, resolution_note
+		FROM punch_anomalies WHERE 1=1`
+	args := []interface{}{}
+
+	if workDate != "" {
+		query += " AND work_date = ?"
+		args = append(args, workDate)
+	}
+
+	if !includeResolved {
+		query += " AND resolved = 0"
+	}
+
+	query += " ORDER BY work_date, punch_time, id"
+
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*AnomalyRecord
+
+	for rows.Next() {
+		record := &AnomalyRecord{}
+		var detectedAt, punchTime string
+		var driverID sql.NullInt64
+		var detail, resolvedAt, resolvedBy, note sql.NullString
+
+		if err := rows.Scan(&record.ID, &detectedAt, &record.WorkDate, &record.Kind, &driverID,
+			&record.CardID, &punchTime, &detail, &record.Resolved, &resolvedAt, &resolvedBy, &note); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if record.CardID, err = l.cipher.Decrypt("punch_anomalies.card_id", record.CardID); err != nil {
+			return nil, err
+		}
+
+		record.DetectedAt = parseTimestamp(detectedAt)
+		record.PunchTime = parseTimestamp(punchTime)
+		record.DriverID = int32(driverID.Int64)
+		record.Detail = detail.String
+		if resolvedAt.Valid {
+			record.ResolvedAt = parseTimestamp(resolvedAt.String)
+		}
+		record.ResolvedBy = resolvedBy.String
+		record.ResolutionNote = note.String
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// ResolveAnomaly 異常を解決済みにする
+func (l *Logger) ResolveAnomaly(id int64, resolvedBy, note string) error {
+	query := `UPDATE punch_anomalies
+		SET resolved = 1, resolved_at = CURRENT_TIMESTAMP, resolved_by = ?, resolution_note = ?
+		WHERE id = ?`
+
+	result, err := l.db.Exec(query, resolvedBy, note, id)
+	if err != nil {
+		return fmt.Errorf("failed to resolve anomaly: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %d", ErrAnomalyNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// APIKey APIキーのレコード（キー自体は保存しない）
+type APIKey struct {
+	ID         int64
+	Name       string
+	Role       string
+	ReaderID   string // roleがreaderの場合のリーダーID
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+}
+
+// Revoked 失効済みか
+func (k *APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// AuthAuditRecord 認証・認可の監査ログ
+type AuthAuditRecord struct {
+	ID        int64
+	Timestamp time.Time
+	Method    string
+	Identity  string // 証明書のCNまたはAPIキー名（認証できなかった場合は空）
+	AuthType  string // mtls / api_key
+	ReaderID  string // リクエストのリーダーID
+	PeerAddr  string
+	Reason    string
+	Allowed   bool // 監査モードで拒否せずに通した場合はtrue
+}
+
+// CreateAPIKey APIキーを登録
+func (l *Logger) CreateAPIKey(keyHash, name, role, readerID string) (*APIKey, error) {
+	query := `INSERT INTO api_keys (key_hash, name, role, reader_id) VALUES (?, ?, ?, ?)`
+
+	result, err := l.db.Exec(query, keyHash, name, role, readerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+
+	return &APIKey{
+		ID:        id,
+		Name:      name,
+		Role:      role,
+		ReaderID:  readerID,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// LookupAPIKey キーのハッシュから有効なAPIキーを取得（見つからない・失効済みの場合はnil）
+// 最終使用日時は1分単位で更新する
+func (l *Logger) LookupAPIKey(keyHash string) (*APIKey, error) {
+	query := `SELECT id, name, role, reader_id, created_at, last_used_at, revoked_at
+		FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL`
+
+	key, err := scanAPIKey(l.db.QueryRow(query, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(key.LastUsedAt) >= time.Minute {
+		if _, err := l.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`,
+			now.Format(timestampLayout), key.ID); err != nil {
+			return nil, fmt.Errorf("failed to update api key: %w", err)
+		}
+		key.LastUsedAt = now
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys APIキー一覧を取得
+func (l *Logger) ListAPIKeys() ([]*APIKey, error) {
+	query := `SELECT id, name, role, reader_id, created_at, last_used_at, revoked_at
+		FROM api_keys ORDER BY id`
+
+	rows, err := l.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey APIキーを失効させる
+func (l *Logger) RevokeAPIKey(id int64) error {
+	result, err := l.db.Exec(`UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("api key not found or already revoked: %d", id)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	key := &APIKey{}
+	var createdAt string
+	var readerID, lastUsedAt, revokedAt sql.NullString
+
+	if err := row.Scan(&key.ID, &key.Name, &key.Role, &readerID, &createdAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	key.ReaderID = readerID.String
+	key.CreatedAt = parseTimestamp(createdAt)
+	if lastUsedAt.Valid {
+		key.LastUsedAt = parseTimestamp(lastUsedAt.String)
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = parseTimestamp(revokedAt.String)
+	}
+
+	return key, nil
+}
+
+// LogAuthAudit 認証・認可の監査ログを記録
+func (l *Logger) LogAuthAudit(record *AuthAuditRecord) error {
+	query := `INSERT INTO auth_audit (method, identity, auth_type, reader_id, peer_addr, reason, allowed)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := l.db.Exec(query,
+		record.Method,
+		record.Identity,
+		record.AuthType,
+		record.ReaderID,
+		record.PeerAddr,
+		record.Reason,
+		record.Allowed,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert auth audit: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuthAudit 監査ログを新しい順に取得
+func (l *Logger) ListAuthAudit(since time.Time, limit int) ([]*AuthAuditRecord, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT id, timestamp, method, identity, auth_type, reader_id, peer_addr, reason, allowed
+		FROM auth_audit WHERE timestamp >= ? ORDER BY timestamp DESC, id DESC LIMIT ?`
+
+	rows, err := l.db.Query(query, since.UTC().Format(timestampLayout), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auth audit: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*AuthAuditRecord
+	for rows.Next() {
+		record := &AuthAuditRecord{}
+		var timestamp string
+		var identity, authType, readerID, peerAddr sql.NullString
+
+		if err := rows.Scan(&record.ID, &timestamp, &record.Method, &identity, &authType,
+			&readerID, &peerAddr, &record.Reason, &record.Allowed); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		record.Timestamp = parseTimestamp(timestamp)
+		record.Identity = identity.String
+		record.AuthType = authType.String
+		record.ReaderID = readerID.String
+		record.PeerAddr = peerAddr.String
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,338 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RegisteredLicense 登録済みの運転免許証（カードIDと運転者の対応）
+type RegisteredLicense struct {
+	CardID     string
+	DriverID   int32
+	Classes    []string // 免許の種類（普通、準中型、中型、大型など）
+	Conditions []string // 免許の条件（5t、8t、ATなど）
+	UpdatedAt  time.Time
+}
+
+// RegisteredVehicle 登録済みの車検証カード（カードIDと車両の対応）
+type RegisteredVehicle struct {
+	CardID           string
+	VehicleID        string
+	VehicleClass     string    // 車両区分（運転に必要な免許の種類）
+	GrossWeightKg    int       // 車両総重量（不明の場合は0）
+	MaxLoadKg        int       // 最大積載量（不明の場合は0）
+	Capacity         int       // 乗車定員（不明の場合は0）
+	Towing           bool      // 牽引免許が必要な被牽引車を牽引するか
+	Manual           bool      // MT車か
+	InspectionExpiry time.Time // 車検証の有効期間満了日
+	UpdatedAt        time.Time
+}
+
+// 配車ステータス
+const (
+	AssignmentStatusAssigned = "assigned"
+	AssignmentStatusRejected = "rejected"
+)
+
+// AssignmentRecord 運転者と車両の割り当てレコード
+type AssignmentRecord struct {
+	ID            int64
+	Timestamp     time.Time
+	ReaderID      string
+	LicenseCardID string
+	DriverID      int32
+	VehicleCardID string
+	VehicleID     string
+	Status        string // assigned/rejected
+	Reason        string // 却下理由
+	Synced        bool   // バックエンドに送信済みか
+}
+
+// UpsertRegisteredLicense 免許証を登録（既存の場合は更新）
+func (l *Logger) UpsertRegisteredLicense(license *RegisteredLicense) error {
+	cardID, cardIDHash, err := l.cardColumns("registered_licenses.card_id", license.CardID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO registered_licenses (card_id, card_id_hash, driver_id, license_classes, license_conditions, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(` + registeredCardConflict(cardIDHash) + `) DO UPDATE SET
+			driver_id = excluded.driver_id,
+			license_classes = excluded.license_classes,
+			license_conditions = excluded.license_conditions,
+			updated_at = CURRENT_TIMESTAMP`
+
+	return l.upsertRegisteredCard("registered_licenses", license.CardID, cardIDHash, query,
+		cardID,
+		cardIDHash,
+		license.DriverID,
+		strings.Join(license.Classes, ","),
+		strings.Join(license.Conditions, ","),
+	)
+}
+
+// GetRegisteredLicense 登録済みの免許証を取得（未登録の場合はnil）
+func (l *Logger) GetRegisteredLicense(cardID string) (*RegisteredLicense, error) {
+	match, args := l.cardMatch("card_id", "card_id_hash", cardID)
+	query := `SELECT card_id, driver_id, license_classes, license_conditions, updated_at
+		FROM registered_licenses WHERE ` + match
+
+	license := &RegisteredLicense{}
+	var classes, updatedAt string
+	var conditions sql.NullString
+	err := l.db.QueryRow(query, args...).Scan(&license.CardID, &license.DriverID, &classes, &conditions, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registered license: %w", err)
+	}
+	if license.CardID, err = l.cipher.Decrypt("registered_licenses.card_id", license.CardID); err != nil {
+		return nil, err
+	}
+
+	license.Classes = splitList(classes)
+	if conditions.Valid {
+		license.Conditions = splitList(conditions.String)
+	}
+	license.UpdatedAt = parseTimestamp(updatedAt)
+
+	return license, nil
+}
+
+// UpsertRegisteredVehicle 車検証カードを登録（既存の場合は更新）
+func (l *Logger) UpsertRegisteredVehicle(vehicle *RegisteredVehicle) error {
+	cardID, cardIDHash, err := l.cardColumns("registered_vehicles.card_id", vehicle.CardID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO registered_vehicles
+		(card_id, card_id_hash, vehicle_id, vehicle_class, gross_weight_kg, max_load_kg, capacity, towing, manual_transmission, inspection_expiry, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(` + registeredCardConflict(cardIDHash) + `) DO UPDATE SET
+			vehicle_id = excluded.vehicle_id,
+			vehicle_class = excluded.vehicle_class,
+			gross_weight_kg = excluded.gross_weight_kg,
+			max_load_kg = excluded.max_load_kg,
+			capacity = excluded.capacity,
+			towing = excluded.towing,
+			manual_transmission = excluded.manual_transmission,
+			inspection_expiry = excluded.inspection_expiry,
+			updated_at = CURRENT_TIMESTAMP`
+
+	var expiry sql.NullString
+	if !vehicle.InspectionExpiry.IsZero() {
+		expiry = sql.NullString{String: vehicle.InspectionExpiry.Format("2006-01-02"), Valid: true}
+	}
+
+	return l.upsertRegisteredCard("registered_vehicles", vehicle.CardID, cardIDHash, query,
+		cardID,
+		cardIDHash,
+		vehicle.VehicleID,
+		vehicle.VehicleClass,
+		nullInt(vehicle.GrossWeightKg),
+		nullInt(vehicle.MaxLoadKg),
+		nullInt(vehicle.Capacity),
+		vehicle.Towing,
+		vehicle.Manual,
+		expiry,
+	)
+}
+
+// registeredCardConflict 登録済みカードの重複を判定する列（暗号化したcard_idは毎回異なる値になるためハッシュで判定する）
+func registeredCardConflict(cardIDHash sql.NullString) string {
+	if cardIDHash.Valid {
+		return "card_id_hash"
+	}
+	return "card_id"
+}
+
+// upsertRegisteredCard 登録済みカードのテーブルにqueryで登録
+// 暗号化する場合は、PII_KEYを設定する前に平文で登録した同じカードの行を置き換える
+func (l *Logger) upsertRegisteredCard(table, cardID string, cardIDHash sql.NullString, query string, args ...any) error {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if cardIDHash.Valid {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE card_id_hash IS NULL AND card_id = ?`, cardID); err != nil {
+			return fmt.Errorf("failed to replace plaintext %s: %w", table, err)
+		}
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to upsert %s: %w", table, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetRegisteredVehicle 登録済みの車検証カードを取得（未登録の場合はnil）
+func (l *Logger) GetRegisteredVehicle(cardID string) (*RegisteredVehicle, error) {
+	match, args := l.cardMatch("card_id", "card_id_hash", cardID)
+	query := `SELECT card_id, vehicle_id, vehicle_class, gross_weight_kg, max_load_kg, capacity,
+		towing, manual_transmission, inspection_expiry, updated_at
+		FROM registered_vehicles WHERE ` + match
+
+	vehicle := &RegisteredVehicle{}
+	var grossWeight, maxLoad, capacity sql.NullInt64
+	var expiry sql.NullString
+	var updatedAt string
+	err := l.db.QueryRow(query, args...).Scan(
+		&vehicle.CardID,
+		&vehicle.VehicleID,
+		&vehicle.VehicleClass,
+		&grossWeight,
+		&maxLoad,
+		&capacity,
+		&vehicle.Towing,
+		&vehicle.Manual,
+		&expiry,
+		&updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registered vehicle: %w", err)
+	}
+	if vehicle.CardID, err = l.cipher.Decrypt("registered_vehicles.card_id", vehicle.CardID); err != nil {
+		return nil, err
+	}
+
+	vehicle.GrossWeightKg = int(grossWeight.Int64)
+	vehicle.MaxLoadKg = int(maxLoad.Int64)
+	vehicle.Capacity = int(capacity.Int64)
+	if expiry.Valid {
+		vehicle.InspectionExpiry, _ = time.ParseInLocation("2006-01-02", expiry.String, time.Local)
+	}
+	vehicle.UpdatedAt = parseTimestamp(updatedAt)
+
+	return vehicle, nil
+}
+
+// LogAssignment 割り当て結果を記録
+func (l *Logger) LogAssignment(record *AssignmentRecord) error {
+	query := `INSERT INTO dispatch_assignments
+		(reader_id, license_card_id, license_card_id_hash, driver_id, vehicle_card_id, vehicle_card_id_hash,
+		vehicle_id, status, reason, synced, process_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	licenseCardID, licenseCardIDHash, err := l.cardColumns("dispatch_assignments.license_card_id", record.LicenseCardID)
+	if err != nil {
+		return err
+	}
+	vehicleCardID, vehicleCardIDHash, err := l.cardColumns("dispatch_assignments.vehicle_card_id", record.VehicleCardID)
+	if err != nil {
+		return err
+	}
+
+	result, err := l.db.Exec(query,
+		record.ReaderID,
+		licenseCardID,
+		licenseCardIDHash,
+		record.DriverID,
+		vehicleCardID,
+		vehicleCardIDHash,
+		record.VehicleID,
+		record.Status,
+		record.Reason,
+		record.Synced,
+		l.processID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert assignment: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	record.ID = id
+
+	return nil
+}
+
+// MarkAssignmentSynced 割り当てをバックエンド送信済みにする
+func (l *Logger) MarkAssignmentSynced(id int64) error {
+	if _, err := l.db.Exec(`UPDATE dispatch_assignments SET synced = 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to mark assignment synced: %w", err)
+	}
+	return nil
+}
+
+// GetPendingAssignments バックエンドに未送信の割り当て（却下を除く）を古い順に取得
+func (l *Logger) GetPendingAssignments(limit int) ([]*AssignmentRecord, error) {
+	query := `SELECT id, timestamp, reader_id, license_card_id, driver_id, vehicle_card_id, vehicle_id, status, reason
+		FROM dispatch_assignments
+		WHERE status = ? AND synced = 0
+		ORDER BY timestamp, id
+		LIMIT ?`
+
+	rows, err := l.db.Query(query, AssignmentStatusAssigned, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*AssignmentRecord
+
+	for rows.Next() {
+		record := &AssignmentRecord{}
+		var timestamp string
+		var driverID sql.NullInt64
+		var vehicleID, reason sql.NullString
+
+		if err := rows.Scan(&record.ID, &timestamp, &record.ReaderID, &record.LicenseCardID, &driverID,
+			&record.VehicleCardID, &vehicleID, &record.Status, &reason); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if record.LicenseCardID, err = l.cipher.Decrypt("dispatch_assignments.license_card_id", record.LicenseCardID); err != nil {
+			return nil, err
+		}
+		if record.VehicleCardID, err = l.cipher.Decrypt("dispatch_assignments.vehicle_card_id", record.VehicleCardID); err != nil {
+			return nil, err
+		}
+
+		record.Timestamp = parseTimestamp(timestamp)
+		record.DriverID = int32(driverID.Int64)
+		record.VehicleID = vehicleID.String
+		record.Reason = reason.String
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// CountPendingAssignments バックエンドに未送信の割り当て（却下を除く）の件数
+func (l *Logger) CountPendingAssignments() (int, error) {
+	var count int
+	err := l.db.QueryRow(`SELECT COUNT(*) FROM dispatch_assignments WHERE status = ? AND synced = 0`,
+		AssignmentStatusAssigned).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count assignments: %w", err)
+	}
+	return count, nil
+}
+
+// nullInt 0をNULLとして扱う
+func nullInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+// splitList カンマ区切りの文字列を分割（空要素は除く）
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
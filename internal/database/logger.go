@@ -1,22 +1,72 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"menkyo_go/internal/pii"
+	"menkyo_go/internal/tracing"
+
 	_ "github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Logger SQLiteロガー
 type Logger struct {
 	db        *sql.DB
+	dbPath    string
 	processID int
+	minLevel  atomic.Int32                // これより低いレベルのログは記録しない
+	writer    atomic.Pointer[asyncWriter] // 非同期書き込み（StartWriterを呼ぶまではnil）
+	cipher    *pii.Cipher                 // 個人データの列の暗号化（nilの場合は平文で保存）
+}
+
+// logLevels ログレベルの順序（ここにないレベルは常に記録する）
+var logLevels = map[string]int32{
+	"DEBUG":   0,
+	"INFO":    1,
+	"WARNING": 2,
+	"ERROR":   3,
+}
+
+// SetLogLevel 記録するログの最低レベルを設定（DEBUG / INFO / WARNING / ERROR）
+func (l *Logger) SetLogLevel(level string) error {
+	n, ok := logLevels[strings.ToUpper(level)]
+	if !ok {
+		return fmt.Errorf("unknown log level: %s", level)
+	}
+	l.minLevel.Store(n)
+	return nil
 }
 
-// NewLogger 新しいLoggerを作成
+// NewLogger 新しいLoggerを作成（未適用のマイグレーションを適用する）
 func NewLogger(dbPath string) (*Logger, error) {
+	logger, err := OpenLogger(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// スキーマを最新にする
+	result, err := logger.Migrate()
+	if err != nil {
+		logger.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if result.BackupPath != "" {
+		logger.LogMessage("INFO", fmt.Sprintf("Migrated database schema from version %d to %d (backup: %s)",
+			result.FromVersion, result.ToVersion, result.BackupPath))
+	}
+
+	return logger, nil
+}
+
+// OpenLogger マイグレーションを適用せずにLoggerを作成（cmd/migrateでスキーマの状態を確認する場合に使う）
+func OpenLogger(dbPath string) (*Logger, error) {
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -24,68 +74,68 @@ func NewLogger(dbPath string) (*Logger, error) {
 
 	logger := &Logger{
 		db:        db,
+		dbPath:    dbPath,
 		processID: os.Getpid(),
 	}
 
-	// テーブルを初期化
-	if err := logger.initTables(); err != nil {
+	if err := logger.initSchemaVersion(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to initialize tables: %w", err)
+		return nil, err
+	}
+
+	// 書き込み中もダッシュボードやcmdからの読み取りを待たせないようにWALモードにする
+	// （新しいデータベースのauto_vacuumを有効にするため、initSchemaVersionの後に切り替える）
+	if _, err := db.Exec(`PRAGMA journal_mode = WAL;`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
 	}
 
 	return logger, nil
 }
 
-// Close データベースを閉じる
+// Close データベースを閉じる（非同期書き込みのキューに残っている行は書き込んでから閉じる）
 func (l *Logger) Close() error {
+	if w := l.writer.Load(); w != nil {
+		w.close()
+	}
 	if l.db != nil {
 		return l.db.Close()
 	}
 	return nil
 }
 
-// initTables テーブルを初期化
-func (l *Logger) initTables() error {
-	queries := []string{
-		// ログテーブル
-		`CREATE TABLE IF NOT EXISTS logs (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
-			level TEXT NOT NULL,
-			message TEXT NOT NULL,
-			reader_id TEXT,
-			card_id TEXT,
-			process_id INTEGER
-		)`,
-		// 読み取り履歴テーブル
-		`CREATE TABLE IF NOT EXISTS read_history (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
-			reader_id TEXT NOT NULL,
-			card_id TEXT NOT NULL,
-			card_type TEXT NOT NULL,
-			atr TEXT,
-			expiry_date TEXT,
-			remain_count TEXT,
-			felica_uid TEXT,
-			status TEXT NOT NULL,
-			error_message TEXT,
-			process_id INTEGER
-		)`,
-		// インデックス
-		`CREATE INDEX IF NOT EXISTS idx_logs_timestamp ON logs(timestamp)`,
-		`CREATE INDEX IF NOT EXISTS idx_logs_card_id ON logs(card_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_read_history_timestamp ON read_history(timestamp)`,
-		`CREATE INDEX IF NOT EXISTS idx_read_history_card_id ON read_history(card_id)`,
-	}
-
-	for _, query := range queries {
-		if _, err := l.db.Exec(query); err != nil {
-			return fmt.Errorf("failed to execute query: %w", err)
-		}
+// StartWriter ログ・読み取り履歴の書き込みをキューに溜めてまとめて書き込むようにする
+// カードの読み取りなどの呼び出し元はディスクへの書き込みを待たない（opts.QueueSizeが0以下の場合は何もしない）
+func (l *Logger) StartWriter(opts WriterOptions) {
+	if opts.QueueSize <= 0 {
+		return
+	}
+	w := newAsyncWriter(l.db, opts)
+	if !l.writer.CompareAndSwap(nil, w) {
+		// 既に開始している
+		w.close()
 	}
+}
 
-	return nil
+// Flush 非同期書き込みのキューに溜まっている行を全て書き込むまで待つ
+func (l *Logger) Flush() {
+	if w := l.writer.Load(); w != nil {
+		w.flush()
+	}
+}
+
+// WriterStats 非同期書き込みの状態（StartWriterを呼んでいない場合はゼロ値）
+func (l *Logger) WriterStats() WriterStats {
+	if w := l.writer.Load(); w != nil {
+		return w.stats()
+	}
+	return WriterStats{}
+}
+
+// enqueue 非同期書き込みのキューに追加（キューがない・閉じられている場合はfalseを返すので直接書き込む）
+func (l *Logger) enqueue(op *writeOp) bool {
+	w := l.writer.Load()
+	return w != nil && w.enqueue(op) == nil
 }
 
 // LogMessage メッセージをログに記録
@@ -95,9 +145,30 @@ func (l *Logger) LogMessage(level, message string) error {
 
 // LogMessageWithContext コンテキスト付きでメッセージをログに記録
 func (l *Logger) LogMessageWithContext(level, message, readerID, cardID string) error {
-	query := `INSERT INTO logs (level, message, reader_id, card_id, process_id) VALUES (?, ?, ?, ?, ?)`
+	return l.LogMessageWithAttrs(level, message, readerID, cardID, "")
+}
+
+// LogMessageWithAttrs 構造化ログの属性（JSON）付きでメッセージをログに記録
+func (l *Logger) LogMessageWithAttrs(level, message, readerID, cardID, attrs string) error {
+	if n, ok := logLevels[level]; ok && n < l.minLevel.Load() {
+		return nil
+	}
 
-	_, err := l.db.Exec(query, level, message, readerID, cardID, l.processID)
+	query := `INSERT INTO logs (timestamp, level, message, reader_id, card_id, card_ref, attrs, process_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var attrsValue sql.NullString
+	if attrs != "" {
+		attrsValue = sql.NullString{String: attrs, Valid: true}
+	}
+
+	// キューで待つ間に時刻がずれないように、記録した時刻を指定する
+	cardID, cardRef := l.logCardColumns(cardID)
+	args := []any{time.Now().UTC().Format(timestampLayout), level, message, readerID, cardID, cardRef, attrsValue, l.processID}
+	if l.enqueue(&writeOp{query: query, args: args}) {
+		return nil
+	}
+
+	_, err := l.db.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to insert log: %w", err)
 	}
@@ -111,6 +182,7 @@ type ReadHistoryRecord struct {
 	Timestamp    time.Time
 	ReaderID     string
 	CardID       string
+	CardRef      string // card_idの仮名（PII_KEYを設定していない場合は空）
 	CardType     string
 	ATR          string
 	ExpiryDate   string
@@ -120,33 +192,47 @@ type ReadHistoryRecord struct {
 	ErrorMessage string
 }
 
-// LogReadHistory 読み取り履歴を記録
-func (l *Logger) LogReadHistory(record *ReadHistoryRecord) error {
-	query := `INSERT INTO read_history
-		(reader_id, card_id, card_type, atr, expiry_date, remain_count, felica_uid, status, error_message, process_id)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	result, err := l.db.Exec(query,
-		record.ReaderID,
-		record.CardID,
-		record.CardType,
-		record.ATR,
-		record.ExpiryDate,
-		record.RemainCount,
-		record.FeliCaUID,
-		record.Status,
-		record.ErrorMessage,
-		l.processID,
+// LogReadHistory 読み取り履歴を非同期書き込みのキューに追加し、書き込み後にrecord.IDを設定してdoneを呼ぶ
+// record.Timestampは読み取り時刻（ゼロ値の場合は追加した時刻）。doneは別のゴルーチンで呼ばれる（nilの場合は呼ばない）
+// 読み取り履歴は書き込みキューが満杯でも待たずに退避先に追加する。StartWriterを呼んでいない場合は直接書き込んでからdoneを呼ぶ
+func (l *Logger) LogReadHistory(ctx context.Context, record *ReadHistoryRecord, done func(error)) {
+	_, span := tracing.Start(ctx, "database.LogReadHistory",
+		attribute.String("reader.id", record.ReaderID),
+		attribute.String("card.type", record.CardType),
+		attribute.String("read.status", record.Status),
+		attribute.Bool("db.async", l.writer.Load() != nil),
 	)
+	finish := func(err error) {
+		tracing.End(span, err)
+		if done != nil {
+			done(err)
+		}
+	}
 
+	args, err := l.readHistoryArgs(record)
 	if err != nil {
-		return fmt.Errorf("failed to insert read history: %w", err)
+		finish(fmt.Errorf("failed to insert read history: %w", err))
+		return
 	}
 
-	id, _ := result.LastInsertId()
-	record.ID = id
+	op := &writeOp{
+		query:    readHistoryInsert,
+		args:     args,
+		critical: true,
+		done: func(id int64, err error) {
+			if err != nil {
+				finish(fmt.Errorf("failed to insert read history: %w", err))
+				return
+			}
+			record.ID = id
+			finish(nil)
+		},
+	}
+	if l.enqueue(op) {
+		return
+	}
 
-	return nil
+	finish(l.insertReadHistory(l.db, record))
 }
 
 // LogEntry ログエントリ
@@ -156,58 +242,66 @@ type LogEntry struct {
 	Level     string
 	Message   string
 	ReaderID  string
+	CardID    string // 記録しない（PII_KEYを設定していない場合は[redacted]、設定している場合はCardRefのみ）
+	CardRef   string // card_idの仮名（PII_KEYを設定していない場合は空）
+	Attrs     string // 構造化ログの属性（JSON。ない場合は空）
 }
 
-// GetLogs ログを取得（フィルタ付き）
-func (l *Logger) GetLogs(readerID, level string, startTime, endTime int64, limit int32) ([]*LogEntry, int32, error) {
-	// クエリ構築
-	query := `SELECT id, timestamp, level, message, reader_id FROM logs WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM logs WHERE 1=1`
-	args := []interface{}{}
-
-	if readerID != "" {
-		query += ` AND reader_id = ?`
-		countQuery += ` AND reader_id = ?`
-		args = append(args, readerID)
-	}
+// Cursor ページングの位置（前のページで最後に返したレコード）
+type Cursor struct {
+	Timestamp time.Time
+	ID        int64
+}
 
-	if level != "" {
-		query += ` AND level = ?`
-		countQuery += ` AND level = ?`
-		args = append(args, level)
-	}
+// LogQuery ログの取得条件
+type LogQuery struct {
+	ReaderID  string
+	Level     string
+	CardID    string
+	CardRef   string
+	StartTime int64 // Unix時刻（0の場合は指定なし）
+	EndTime   int64 // Unix時刻（0の場合は指定なし）
+	Limit     int32 // 0の場合は100件
+	Ascending bool  // trueの場合は古い順
+	After     *Cursor
+}
 
-	if startTime > 0 {
-		query += ` AND strftime('%s', timestamp) >= ?`
-		countQuery += ` AND strftime('%s', timestamp) >= ?`
-		args = append(args, startTime)
-	}
+// GetLogs ログを取得（フィルタ付き）
+func (l *Logger) GetLogs(readerID, level string, startTime, endTime int64, limit int32) ([]*LogEntry, int32, error) {
+	logs, totalCount, _, err := l.QueryLogs(&LogQuery{
+		ReaderID:  readerID,
+		Level:     level,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Limit:     limit,
+	})
+	return logs, totalCount, err
+}
 
-	if endTime > 0 {
-		query += ` AND strftime('%s', timestamp) <= ?`
-		countQuery += ` AND strftime('%s', timestamp) <= ?`
-		args = append(args, endTime)
-	}
+// QueryLogs ログを取得（続きがある場合は次のページのCursorを返す）
+func (l *Logger) QueryLogs(q *LogQuery) ([]*LogEntry, int32, *Cursor, error) {
+	filter := newQueryFilter()
+	filter.equal("reader_id", q.ReaderID)
+	filter.equal("level", q.Level)
+	filter.equalIndexed("card_id", "card_ref", q.CardID, l.cipher.Ref(q.CardID))
+	filter.equal("card_ref", q.CardRef)
+	filter.timeRange(q.StartTime, q.EndTime)
 
-	// 総件数を取得
+	// 総件数を取得（ページ位置に関係なく条件に一致する件数）
 	var totalCount int32
-	err := l.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err := l.db.QueryRow(`SELECT COUNT(*) FROM logs WHERE 1=1`+filter.where, filter.args...).Scan(&totalCount)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count logs: %w", err)
+		return nil, 0, nil, fmt.Errorf("failed to count logs: %w", err)
 	}
 
 	// ログを取得
-	query += ` ORDER BY timestamp DESC`
-	if limit > 0 {
-		query += ` LIMIT ?`
-		args = append(args, limit)
-	} else {
-		args = append(args, 100) // デフォルト100件
-	}
+	filter.after(q.After, q.Ascending)
+	query := `SELECT id, timestamp, level, message, reader_id, card_id, card_ref, attrs FROM logs WHERE 1=1` + filter.where
+	query, args, limit := filter.page(query, q.Ascending, q.Limit)
 
 	rows, err := l.db.Query(query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query logs: %w", err)
+		return nil, 0, nil, fmt.Errorf("failed to query logs: %w", err)
 	}
 	defer rows.Close()
 
@@ -216,26 +310,42 @@ func (l *Logger) GetLogs(readerID, level string, startTime, endTime int64, limit
 	for rows.Next() {
 		entry := &LogEntry{}
 		var timestamp string
-		var readerID sql.NullString
+		var readerID, cardID, cardRef, attrs sql.NullString
 
-		if err := rows.Scan(&entry.ID, &timestamp, &entry.Level, &entry.Message, &readerID); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		if err := rows.Scan(&entry.ID, &timestamp, &entry.Level, &entry.Message, &readerID, &cardID, &cardRef, &attrs); err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		entry.Timestamp, _ = time.Parse("2006-01-02 15:04:05", timestamp)
+		entry.Timestamp = parseTimestamp(timestamp)
 		if readerID.Valid {
 			entry.ReaderID = readerID.String
 		}
+		if cardID.Valid {
+			entry.CardID = cardID.String
+		}
+		if cardRef.Valid {
+			entry.CardRef = cardRef.String
+		}
+		if attrs.Valid {
+			entry.Attrs = attrs.String
+		}
 
 		logs = append(logs, entry)
 	}
 
-	return logs, totalCount, nil
+	var next *Cursor
+	if len(logs) > limit {
+		logs = logs[:limit]
+		last := logs[limit-1]
+		next = &Cursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	return logs, totalCount, next, nil
 }
 
 // GetRecentLogs 最近のログを取得
 func (l *Logger) GetRecentLogs(limit int) ([]map[string]interface{}, error) {
-	query := `SELECT id, timestamp, level, message, reader_id, card_id
+	query := `SELECT id, timestamp, level, message, reader_id, card_id, card_ref, attrs
 		FROM logs
 		ORDER BY timestamp DESC
 		LIMIT ?`
@@ -252,9 +362,9 @@ func (l *Logger) GetRecentLogs(limit int) ([]map[string]interface{}, error) {
 		var id int64
 		var timestamp string
 		var level, message string
-		var readerID, cardID sql.NullString
+		var readerID, cardID, cardRef, attrs sql.NullString
 
-		if err := rows.Scan(&id, &timestamp, &level, &message, &readerID, &cardID); err != nil {
+		if err := rows.Scan(&id, &timestamp, &level, &message, &readerID, &cardID, &cardRef, &attrs); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
@@ -271,6 +381,12 @@ func (l *Logger) GetRecentLogs(limit int) ([]map[string]interface{}, error) {
 		if cardID.Valid {
 			log["card_id"] = cardID.String
 		}
+		if cardRef.Valid {
+			log["card_ref"] = cardRef.String
+		}
+		if attrs.Valid {
+			log["attrs"] = attrs.String
+		}
 
 		logs = append(logs, log)
 	}
@@ -278,58 +394,59 @@ func (l *Logger) GetRecentLogs(limit int) ([]map[string]interface{}, error) {
 	return logs, nil
 }
 
+// ReadHistoryQuery 読み取り履歴の取得条件
+type ReadHistoryQuery struct {
+	ReaderID  string
+	Status    string
+	CardID    string
+	CardRef   string
+	CardType  string
+	StartTime int64 // Unix時刻（0の場合は指定なし）
+	EndTime   int64 // Unix時刻（0の場合は指定なし）
+	Limit     int32 // 0の場合は100件
+	Ascending bool  // trueの場合は古い順
+	After     *Cursor
+}
+
 // GetReadHistory 読み取り履歴を取得（フィルタ付き）
 func (l *Logger) GetReadHistory(readerID, status string, startTime, endTime int64, limit int32) ([]*ReadHistoryRecord, int32, error) {
-	// クエリ構築
-	query := `SELECT id, timestamp, reader_id, card_id, card_type, atr,
-		expiry_date, remain_count, felica_uid, status, error_message
-		FROM read_history WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM read_history WHERE 1=1`
-	args := []interface{}{}
-
-	if readerID != "" {
-		query += ` AND reader_id = ?`
-		countQuery += ` AND reader_id = ?`
-		args = append(args, readerID)
-	}
-
-	if status != "" {
-		query += ` AND status = ?`
-		countQuery += ` AND status = ?`
-		args = append(args, status)
-	}
-
-	if startTime > 0 {
-		query += ` AND strftime('%s', timestamp) >= ?`
-		countQuery += ` AND strftime('%s', timestamp) >= ?`
-		args = append(args, startTime)
-	}
-
-	if endTime > 0 {
-		query += ` AND strftime('%s', timestamp) <= ?`
-		countQuery += ` AND strftime('%s', timestamp) <= ?`
-		args = append(args, endTime)
-	}
+	records, totalCount, _, err := l.QueryReadHistory(&ReadHistoryQuery{
+		ReaderID:  readerID,
+		Status:    status,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Limit:     limit,
+	})
+	return records, totalCount, err
+}
 
-	// 総件数を取得
+// QueryReadHistory 読み取り履歴を取得（続きがある場合は次のページのCursorを返す）
+func (l *Logger) QueryReadHistory(q *ReadHistoryQuery) ([]*ReadHistoryRecord, int32, *Cursor, error) {
+	filter := newQueryFilter()
+	filter.equal("reader_id", q.ReaderID)
+	filter.equal("status", q.Status)
+	filter.equalIndexed("card_id", "card_id_hash", q.CardID, l.cipher.Index(q.CardID))
+	filter.equal("card_ref", q.CardRef)
+	filter.equal("card_type", q.CardType)
+	filter.timeRange(q.StartTime, q.EndTime)
+
+	// 総件数を取得（ページ位置に関係なく条件に一致する件数）
 	var totalCount int32
-	err := l.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err := l.db.QueryRow(`SELECT COUNT(*) FROM read_history WHERE 1=1`+filter.where, filter.args...).Scan(&totalCount)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count read history: %w", err)
+		return nil, 0, nil, fmt.Errorf("failed to count read history: %w", err)
 	}
 
 	// 履歴を取得
-	query += ` ORDER BY timestamp DESC`
-	if limit > 0 {
-		query += ` LIMIT ?`
-		args = append(args, limit)
-	} else {
-		args = append(args, 100) // デフォルト100件
-	}
+	filter.after(q.After, q.Ascending)
+	query := `SELECT id, timestamp, reader_id, card_id, card_ref, card_type, atr,
+		expiry_date, remain_count, felica_uid, status, error_message
+		FROM read_history WHERE 1=1` + filter.where
+	query, args, limit := filter.page(query, q.Ascending, q.Limit)
 
 	rows, err := l.db.Query(query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query read history: %w", err)
+		return nil, 0, nil, fmt.Errorf("failed to query read history: %w", err)
 	}
 	defer rows.Close()
 
@@ -338,13 +455,14 @@ func (l *Logger) GetReadHistory(readerID, status string, startTime, endTime int6
 	for rows.Next() {
 		record := &ReadHistoryRecord{}
 		var timestamp string
-		var expiryDate, remainCount, felicaUID, errorMessage sql.NullString
+		var cardRef, expiryDate, remainCount, felicaUID, errorMessage sql.NullString
 
 		if err := rows.Scan(
 			&record.ID,
 			&timestamp,
 			&record.ReaderID,
 			&record.CardID,
+			&cardRef,
 			&record.CardType,
 			&record.ATR,
 			&expiryDate,
@@ -353,11 +471,14 @@ func (l *Logger) GetReadHistory(readerID, status string, startTime, endTime int6
 			&record.Status,
 			&errorMessage,
 		); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+			return nil, 0, nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		record.Timestamp, _ = time.Parse("2006-01-02 15:04:05", timestamp)
+		record.Timestamp = parseTimestamp(timestamp)
 
+		if cardRef.Valid {
+			record.CardRef = cardRef.String
+		}
 		if expiryDate.Valid {
 			record.ExpiryDate = expiryDate.String
 		}
@@ -370,9 +491,53 @@ func (l *Logger) GetReadHistory(readerID, status string, startTime, endTime int6
 		if errorMessage.Valid {
 			record.ErrorMessage = errorMessage.String
 		}
+		if err := l.decryptReadHistory(record); err != nil {
+			return nil, 0, nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	var next *Cursor
+	if len(records) > limit {
+		records = records[:limit]
+		last := records[limit-1]
+		next = &Cursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	return records, totalCount, next, nil
+}
+
+// ListReadHistoryBetween start〜endの読み取り成功履歴を古い順に全件取得
+func (l *Logger) ListReadHistoryBetween(start, end time.Time) ([]*ReadHistoryRecord, error) {
+	query := `SELECT id, timestamp, reader_id, card_id, card_type
+		FROM read_history
+		WHERE status = 'success'
+		AND timestamp >= ? AND timestamp < ?
+		ORDER BY timestamp, id`
+
+	rows, err := l.db.Query(query, start.UTC().Format(timestampLayout), end.UTC().Format(timestampLayout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query read history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ReadHistoryRecord
+
+	for rows.Next() {
+		record := &ReadHistoryRecord{Status: "success"}
+		var timestamp string
+
+		if err := rows.Scan(&record.ID, &timestamp, &record.ReaderID, &record.CardID, &record.CardType); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := l.decryptReadHistory(record); err != nil {
+			return nil, err
+		}
 
+		record.Timestamp = parseTimestamp(timestamp)
 		records = append(records, record)
 	}
 
-	return records, totalCount, nil
+	return records, nil
 }
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles 番号付きのマイグレーション（migrations/NNNN_name.sql、番号順に適用する）
+// 適用済みのファイルは変更せず、スキーマの変更は新しい番号のファイルとして追加する
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration スキーマのマイグレーション
+type Migration struct {
+	Version   int
+	Name      string
+	SQL       string
+	AppliedAt time.Time // 未適用の場合はゼロ値
+}
+
+// MigrationResult Migrateの結果
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Applied     []Migration
+	BackupPath  string // バックアップを作成しなかった場合は空
+}
+
+// Migrations バイナリに含まれるマイグレーションを番号順に返す
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, rest, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+
+		data, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: rest, SQL: string(data)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must be sequential from 1: found %04d at position %d", m.Version, i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// LatestSchemaVersion バイナリに含まれる最新のスキーマバージョン
+func LatestSchemaVersion() (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	return len(migrations), nil
+}
+
+// initSchemaVersion スキーマバージョンのテーブルを作成
+// 新規作成のデータベースは最初のテーブルの作成前にincremental_vacuumを有効にする（既存のデータベースでは効果なし）
+func (l *Logger) initSchemaVersion() error {
+	_, err := l.db.Exec(`PRAGMA auto_vacuum = INCREMENTAL;
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion 適用済みのスキーマバージョン（未適用の場合は0）
+func (l *Logger) SchemaVersion() (int, error) {
+	var version int
+	if err := l.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to query schema version: %w", err)
+	}
+	return version, nil
+}
+
+// MigrationStatus 全てのマイグレーションと適用日時（未適用の場合はAppliedAtがゼロ値）
+func (l *Logger) MigrationStatus() ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := l.db.Query(`SELECT version, applied_at FROM schema_version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema version: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt string
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema version: %w", err)
+		}
+		applied[version] = parseTimestamp(appliedAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query schema version: %w", err)
+	}
+
+	for i := range migrations {
+		migrations[i].AppliedAt = applied[migrations[i].Version]
+	}
+	return migrations, nil
+}
+
+// Migrate 未適用のマイグレーションを番号順に適用する
+// 既存のデータベースは適用前に<dbPath>.v<version>-<日時>.bakへバックアップし、マイグレーションごとにトランザクションで適用する
+func (l *Logger) Migrate() (*MigrationResult, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := l.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	result := &MigrationResult{FromVersion: current, ToVersion: current}
+
+	if current > len(migrations) {
+		return nil, fmt.Errorf("database schema version %d is newer than this binary supports (%d)", current, len(migrations))
+	}
+	if current == len(migrations) {
+		return result, nil
+	}
+
+	// 既存のデータベースのみバックアップする（新規作成時は不要）
+	hasData, err := l.hasTables()
+	if err != nil {
+		return nil, err
+	}
+	if hasData {
+		backupPath, err := l.Backup(current)
+		if err != nil {
+			return nil, err
+		}
+		result.BackupPath = backupPath
+	}
+
+	for _, m := range migrations[current:] {
+		applied, err := l.applyMigration(m)
+		if err != nil {
+			return result, err
+		}
+		if applied {
+			result.Applied = append(result.Applied, m)
+		}
+		result.ToVersion = m.Version
+	}
+
+	return result, nil
+}
+
+// applyMigration マイグレーションを1つ適用する（他のプロセスが適用済みの場合はfalse）
+func (l *Logger) applyMigration(m Migration) (bool, error) {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM schema_version WHERE version = ?`, m.Version).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to query schema version: %w", err)
+	}
+	if exists > 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(m.SQL); err != nil {
+		return false, fmt.Errorf("failed to apply migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.Version, m.Name, time.Now().UTC().Format(timestampLayout)); err != nil {
+		return false, fmt.Errorf("failed to record migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return true, nil
+}
+
+// hasTables schema_version以外のテーブルがあるか（既存のデータベースか）
+func (l *Logger) hasTables() (bool, error) {
+	var count int
+	err := l.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master
+		WHERE type = 'table' AND name NOT IN ('schema_version', 'sqlite_sequence')`).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to query tables: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Backup データベースを<dbPath>.v<version>-<日時>.bakへコピーしてパスを返す
+// VACUUM INTOで書き込み中でも一貫した状態をコピーする（メモリ上のデータベースはバックアップしない）
+func (l *Logger) Backup(version int) (string, error) {
+	if l.dbPath == "" || l.dbPath == ":memory:" || strings.Contains(l.dbPath, "mode=memory") {
+		return "", nil
+	}
+
+	dbFile, _, _ := strings.Cut(strings.TrimPrefix(l.dbPath, "file:"), "?")
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", dbFile, version, time.Now().Format("20060102-150405"))
+	if _, err := l.db.Exec(`VACUUM INTO ?`, backupPath); err != nil {
+		return "", fmt.Errorf("failed to back up database to %s: %w", backupPath, err)
+	}
+	return backupPath, nil
+}
@@ -0,0 +1,240 @@
+-- 初期スキーマ（schema_versionの導入前にinitTablesで作成していたテーブル）
+-- 既存のデータベースにも適用できるようにIF NOT EXISTSで作成する
+
+-- ログテーブル
+CREATE TABLE IF NOT EXISTS logs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	level TEXT NOT NULL,
+	message TEXT NOT NULL,
+	reader_id TEXT,
+	card_id TEXT,
+	process_id INTEGER
+);
+
+-- 読み取り履歴テーブル
+CREATE TABLE IF NOT EXISTS read_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	reader_id TEXT NOT NULL,
+	card_id TEXT NOT NULL,
+	card_type TEXT NOT NULL,
+	atr TEXT,
+	expiry_date TEXT,
+	remain_count TEXT,
+	felica_uid TEXT,
+	status TEXT NOT NULL,
+	error_message TEXT,
+	process_id INTEGER
+);
+
+-- アルコール測定テーブル
+CREATE TABLE IF NOT EXISTS alcohol_measurements (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	reader_id TEXT NOT NULL,
+	read_history_id INTEGER REFERENCES read_history(id),
+	card_id TEXT,
+	value REAL NOT NULL,
+	result TEXT NOT NULL,
+	device_time DATETIME,
+	raw TEXT,
+	process_id INTEGER
+);
+
+-- 登録済み免許証テーブル
+CREATE TABLE IF NOT EXISTS registered_licenses (
+	card_id TEXT PRIMARY KEY,
+	driver_id INTEGER NOT NULL,
+	license_classes TEXT NOT NULL,
+	license_conditions TEXT,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 登録済み車検証カードテーブル
+CREATE TABLE IF NOT EXISTS registered_vehicles (
+	card_id TEXT PRIMARY KEY,
+	vehicle_id TEXT NOT NULL,
+	vehicle_class TEXT NOT NULL,
+	gross_weight_kg INTEGER,
+	max_load_kg INTEGER,
+	capacity INTEGER,
+	towing INTEGER NOT NULL DEFAULT 0,
+	manual_transmission INTEGER NOT NULL DEFAULT 0,
+	inspection_expiry TEXT,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 配車（運転者と車両の割り当て）テーブル
+CREATE TABLE IF NOT EXISTS dispatch_assignments (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	reader_id TEXT NOT NULL,
+	license_card_id TEXT NOT NULL,
+	driver_id INTEGER,
+	vehicle_card_id TEXT NOT NULL,
+	vehicle_id TEXT,
+	status TEXT NOT NULL,
+	reason TEXT,
+	synced INTEGER NOT NULL DEFAULT 0,
+	process_id INTEGER
+);
+
+-- 出退勤打刻の送信待ちテーブル（woff-svへの送信に失敗しても打刻を失わないため）
+CREATE TABLE IF NOT EXISTS punch_outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME NOT NULL,
+	reader_id TEXT NOT NULL,
+	card_id TEXT NOT NULL,
+	driver_id INTEGER,
+	state TEXT NOT NULL,
+	sent INTEGER NOT NULL DEFAULT 0,
+	sent_at DATETIME,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT
+);
+
+-- 打刻の異常テーブル
+CREATE TABLE IF NOT EXISTS punch_anomalies (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	work_date TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	driver_id INTEGER,
+	card_id TEXT NOT NULL,
+	punch_time DATETIME NOT NULL,
+	detail TEXT,
+	resolved INTEGER NOT NULL DEFAULT 0,
+	resolved_at DATETIME,
+	resolved_by TEXT,
+	resolution_note TEXT,
+	UNIQUE(kind, card_id, punch_time)
+);
+
+-- APIキーテーブル（キーはハッシュのみ保存）
+CREATE TABLE IF NOT EXISTS api_keys (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	key_hash TEXT NOT NULL UNIQUE,
+	name TEXT NOT NULL,
+	role TEXT NOT NULL,
+	reader_id TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	last_used_at DATETIME,
+	revoked_at DATETIME
+);
+
+-- 認証・認可の監査ログテーブル
+CREATE TABLE IF NOT EXISTS auth_audit (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	method TEXT NOT NULL,
+	identity TEXT,
+	auth_type TEXT,
+	reader_id TEXT,
+	peer_addr TEXT,
+	reason TEXT NOT NULL,
+	allowed INTEGER NOT NULL DEFAULT 0
+);
+
+-- リーダー（端末）の登録テーブル
+CREATE TABLE IF NOT EXISTS readers (
+	reader_id TEXT PRIMARY KEY,
+	site TEXT,
+	location TEXT,
+	version TEXT,
+	build_time TEXT,
+	nfc_devices TEXT,
+	hostname TEXT,
+	peer_addr TEXT,
+	registered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	last_seen DATETIME NOT NULL,
+	stale_since DATETIME
+);
+
+-- リーダーの設定（変更ごとに1行追加。settingsがNULLの行は削除）
+CREATE TABLE IF NOT EXISTS reader_configs (
+	version INTEGER PRIMARY KEY AUTOINCREMENT,
+	scope TEXT NOT NULL,
+	target TEXT NOT NULL,
+	settings TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	created_by TEXT,
+	note TEXT
+);
+
+-- リーダーの設定の適用結果
+CREATE TABLE IF NOT EXISTS reader_config_status (
+	reader_id TEXT PRIMARY KEY,
+	version INTEGER NOT NULL,
+	error TEXT,
+	reported_at DATETIME NOT NULL
+);
+
+-- サーバーへの送信待ち（リーダー側。idを連番として送る）
+CREATE TABLE IF NOT EXISTS upload_outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	kind TEXT NOT NULL,
+	payload BLOB NOT NULL,
+	sent INTEGER NOT NULL DEFAULT 0,
+	sent_at DATETIME
+);
+
+-- 送信元の識別子（リーダー側。DBごとに1つ）
+CREATE TABLE IF NOT EXISTS upload_source (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	source TEXT NOT NULL
+);
+
+-- 一括送信の確定済みの連番（サーバー側）
+CREATE TABLE IF NOT EXISTS upload_progress (
+	reader_id TEXT NOT NULL,
+	source TEXT NOT NULL,
+	last_seq INTEGER NOT NULL,
+	updated_at DATETIME NOT NULL,
+	PRIMARY KEY (reader_id, source)
+);
+
+-- Webhookの送信先
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	url TEXT NOT NULL,
+	secret TEXT NOT NULL,
+	format TEXT NOT NULL DEFAULT 'json',
+	events TEXT,
+	reader_ids TEXT,
+	card_types TEXT,
+	enabled INTEGER NOT NULL DEFAULT 1,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Webhookの送信（送信先ごとに1行。失敗した場合はnext_attempt_atに再送）
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	endpoint_id INTEGER NOT NULL REFERENCES webhook_endpoints(id),
+	event_id TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	status TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at DATETIME NOT NULL,
+	last_status INTEGER,
+	last_error TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	delivered_at DATETIME
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_logs_timestamp ON logs(timestamp);
+CREATE INDEX IF NOT EXISTS idx_logs_card_id ON logs(card_id);
+CREATE INDEX IF NOT EXISTS idx_read_history_timestamp ON read_history(timestamp);
+CREATE INDEX IF NOT EXISTS idx_read_history_card_id ON read_history(card_id);
+CREATE INDEX IF NOT EXISTS idx_alcohol_measurements_read_history_id ON alcohol_measurements(read_history_id);
+CREATE INDEX IF NOT EXISTS idx_dispatch_assignments_timestamp ON dispatch_assignments(timestamp);
+CREATE INDEX IF NOT EXISTS idx_punch_outbox_timestamp ON punch_outbox(timestamp);
+CREATE INDEX IF NOT EXISTS idx_punch_anomalies_work_date ON punch_anomalies(work_date);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_timestamp ON auth_audit(timestamp);
+CREATE INDEX IF NOT EXISTS idx_reader_configs_scope_target ON reader_configs(scope, target, version);
+CREATE INDEX IF NOT EXISTS idx_upload_outbox_sent ON upload_outbox(sent, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries(status, next_attempt_at);
@@ -0,0 +1,2 @@
+-- 構造化ログの属性（reader_id・card_id以外の属性をJSONで保存。request_idで検索できるようにする）
+ALTER TABLE logs ADD COLUMN attrs TEXT;
@@ -0,0 +1,4 @@
+-- 暗号化したcard_idの代わりに検索するキー付きハッシュ（PII_KEYを設定していない場合はNULL）
+ALTER TABLE read_history ADD COLUMN card_id_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_read_history_card_id_hash ON read_history(card_id_hash);
@@ -0,0 +1,7 @@
+-- カードの識別子の仮名（PII_KEYの鍵から作るHMAC。識別子を記録せずにログと読み取り履歴を紐付ける）
+-- PII_KEYを設定している場合、logsのcard_idには記録せずcard_refのみ記録する
+ALTER TABLE logs ADD COLUMN card_ref TEXT;
+ALTER TABLE read_history ADD COLUMN card_ref TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_logs_card_ref ON logs(card_ref);
+CREATE INDEX IF NOT EXISTS idx_read_history_card_ref ON read_history(card_ref);
@@ -0,0 +1,17 @@
+-- 読み取り履歴以外のテーブルのカードの識別子も暗号化し、キー付きハッシュで検索・照合する（PII_KEYを設定していない場合はNULL）
+ALTER TABLE alcohol_measurements ADD COLUMN card_id_hash TEXT;
+ALTER TABLE registered_licenses ADD COLUMN card_id_hash TEXT;
+ALTER TABLE registered_vehicles ADD COLUMN card_id_hash TEXT;
+ALTER TABLE dispatch_assignments ADD COLUMN license_card_id_hash TEXT;
+ALTER TABLE dispatch_assignments ADD COLUMN vehicle_card_id_hash TEXT;
+ALTER TABLE punch_outbox ADD COLUMN card_id_hash TEXT;
+ALTER TABLE punch_anomalies ADD COLUMN card_id_hash TEXT;
+
+-- 暗号化したcard_idは毎回異なる値になるため、登録・異常の重複はハッシュで判定する
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registered_licenses_card_id_hash ON registered_licenses(card_id_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registered_vehicles_card_id_hash ON registered_vehicles(card_id_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_punch_anomalies_card_id_hash ON punch_anomalies(kind, card_id_hash, punch_time);
+
+CREATE INDEX IF NOT EXISTS idx_punch_outbox_card_id_hash ON punch_outbox(card_id_hash);
+CREATE INDEX IF NOT EXISTS idx_dispatch_assignments_license_card_id_hash ON dispatch_assignments(license_card_id_hash);
+CREATE INDEX IF NOT EXISTS idx_dispatch_assignments_vehicle_card_id_hash ON dispatch_assignments(vehicle_card_id_hash);
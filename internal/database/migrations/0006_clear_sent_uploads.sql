@@ -0,0 +1,2 @@
+-- 送信済みの送信待ちにはカードの識別子・免許証の共通データを残さない（以降はMarkUploadsSentで消す）
+UPDATE upload_outbox SET payload = X'' WHERE sent = 1;
@@ -0,0 +1,457 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"menkyo_go/internal/pii"
+)
+
+// piiTable 個人データを暗号化して保存するテーブル
+type piiTable struct {
+	key     string          // 行を特定する列（空の場合はid）
+	columns []string        // AES-GCMで暗号化する列
+	derived []derivedColumn // 暗号化する列の平文から作る列（キー付きハッシュ・仮名）
+}
+
+// derivedColumn 暗号化する列の平文から作る列
+type derivedColumn struct {
+	source string                           // 元の列（columnsのいずれか）
+	column string                           // 保存する列
+	derive func(*pii.Cipher, string) string // 平文から値を作る
+}
+
+// piiTables 個人データを暗号化して保存するテーブル（個人データを保存するテーブルを追加した場合はここにも追加する）
+var piiTables = map[string]piiTable{
+	"read_history": {
+		columns: []string{"card_id", "expiry_date", "felica_uid"},
+		derived: []derivedColumn{
+			{source: "card_id", column: "card_id_hash", derive: (*pii.Cipher).Index},
+			{source: "card_id", column: "card_ref", derive: (*pii.Cipher).Ref},
+		},
+	},
+	"alcohol_measurements": {
+		columns: []string{"card_id"},
+		derived: []derivedColumn{{source: "card_id", column: "card_id_hash", derive: (*pii.Cipher).Index}},
+	},
+	"registered_licenses": {
+		key:     "rowid", // card_idが主キーのため
+		columns: []string{"card_id"},
+		derived: []derivedColumn{{source: "card_id", column: "card_id_hash", derive: (*pii.Cipher).Index}},
+	},
+	"registered_vehicles": {
+		key:     "rowid",
+		columns: []string{"card_id"},
+		derived: []derivedColumn{{source: "card_id", column: "card_id_hash", derive: (*pii.Cipher).Index}},
+	},
+	"dispatch_assignments": {
+		columns: []string{"license_card_id", "vehicle_card_id"},
+		derived: []derivedColumn{
+			{source: "license_card_id", column: "license_card_id_hash", derive: (*pii.Cipher).Index},
+			{source: "vehicle_card_id", column: "vehicle_card_id_hash", derive: (*pii.Cipher).Index},
+		},
+	},
+	"punch_outbox": {
+		columns: []string{"card_id"},
+		derived: []derivedColumn{{source: "card_id", column: "card_id_hash", derive: (*pii.Cipher).Index}},
+	},
+	"punch_anomalies": {
+		columns: []string{"card_id"},
+		derived: []derivedColumn{{source: "card_id", column: "card_id_hash", derive: (*pii.Cipher).Index}},
+	},
+	"upload_outbox": {
+		columns: []string{"payload"}, // シリアライズしたLicenseData/ReadLog（送信済みの行は空）
+	},
+}
+
+// keyColumn 行を特定する列
+func (t piiTable) keyColumn() string {
+	if t.key == "" {
+		return "id"
+	}
+	return t.key
+}
+
+// PIITables 個人データを暗号化して保存するテーブル名
+func PIITables() []string {
+	tables := make([]string, 0, len(piiTables))
+	for table := range piiTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// SetCipher 個人データの列の暗号化に使うCipherを設定（nilの場合は暗号化しない）
+// NewLoggerの直後、ログ・読み取り履歴を記録する前に呼ぶ
+func (l *Logger) SetCipher(c *pii.Cipher) {
+	l.cipher = c
+}
+
+// CardRef カードの識別子の仮名（PII_KEYを設定していない場合は空）
+// ログに識別子を出力する代わりに使い、logs・read_historyのcard_refで紐付ける
+func (l *Logger) CardRef(cardID string) string {
+	return l.cipher.Ref(cardID)
+}
+
+// cardColumns カードの識別子の列に保存する値と検索用のハッシュ（fieldは"テーブル.列"）
+func (l *Logger) cardColumns(field, cardID string) (string, sql.NullString, error) {
+	encrypted, err := l.cipher.Encrypt(field, cardID)
+	if err != nil {
+		return "", sql.NullString{}, err
+	}
+	return encrypted, nullString(l.cipher.Index(cardID)), nil
+}
+
+// cardMatch カードの識別子の列の検索条件（equalIndexedと同じく、ハッシュを付ける前の平文の行はcolumnと比較する）
+func (l *Logger) cardMatch(column, hashColumn, cardID string) (string, []any) {
+	hash := l.cipher.Index(cardID)
+	if hash == "" {
+		return column + ` = ?`, []any{cardID}
+	}
+	return `(` + hashColumn + ` = ? OR (` + hashColumn + ` IS NULL AND ` + column + ` = ?))`, []any{hash, cardID}
+}
+
+// logCardColumns logsに記録するcard_idとcard_ref（card_idは記録せず、PII_KEYを設定している場合は仮名のみ記録する）
+// PII_KEYを設定していない場合は仮名を作れないため、識別子の代わりにpii.Redactedを記録する
+func (l *Logger) logCardColumns(cardID string) (string, sql.NullString) {
+	if cardID == "" || cardID == pii.Redacted {
+		return cardID, sql.NullString{}
+	}
+	if ref := l.cipher.Ref(cardID); ref != "" {
+		return "", nullString(ref)
+	}
+	return pii.Redacted, sql.NullString{}
+}
+
+// readHistoryInsert 読み取り履歴のINSERT（readHistoryArgsの順）
+const readHistoryInsert = `INSERT INTO read_history
+	(timestamp, reader_id, card_id, card_id_hash, card_ref, card_type, atr, expiry_date, remain_count, felica_uid, status, error_message, process_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// readHistoryArgs readHistoryInsertの値（個人データの列は暗号化し、card_idのハッシュと仮名を付ける）
+// record.Timestampがゼロ値の場合は現在時刻を設定する
+func (l *Logger) readHistoryArgs(record *ReadHistoryRecord) ([]any, error) {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+
+	cardID, err := l.cipher.Encrypt("read_history.card_id", record.CardID)
+	if err != nil {
+		return nil, err
+	}
+	expiryDate, err := l.cipher.Encrypt("read_history.expiry_date", record.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+	felicaUID, err := l.cipher.Encrypt("read_history.felica_uid", record.FeliCaUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{
+		record.Timestamp.UTC().Format(timestampLayout),
+		record.ReaderID,
+		cardID,
+		nullString(l.cipher.Index(record.CardID)),
+		nullString(l.cipher.Ref(record.CardID)),
+		record.CardType,
+		record.ATR,
+		expiryDate,
+		record.RemainCount,
+		felicaUID,
+		record.Status,
+		record.ErrorMessage,
+		l.processID,
+	}, nil
+}
+
+// decryptReadHistory 読み出した読み取り履歴の個人データの列を復号
+func (l *Logger) decryptReadHistory(record *ReadHistoryRecord) error {
+	var err error
+	if record.CardID, err = l.cipher.Decrypt("read_history.card_id", record.CardID); err != nil {
+		return err
+	}
+	if record.ExpiryDate, err = l.cipher.Decrypt("read_history.expiry_date", record.ExpiryDate); err != nil {
+		return err
+	}
+	if record.FeliCaUID, err = l.cipher.Decrypt("read_history.felica_uid", record.FeliCaUID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReencryptResult 再暗号化の結果
+type ReencryptResult struct {
+	Table   string
+	Scanned int64 // 確認した行数
+	Updated int64 // 暗号化し直した行数
+}
+
+// ReencryptPII 個人データの列を暗号化に使う鍵で暗号化し直し、検索用のハッシュと仮名を付け直す
+// 平文のまま保存された行（PII_KEYを設定する前の行）も暗号化する。batchSize件ずつのトランザクションで更新する
+func (l *Logger) ReencryptPII(table string, batchSize int) (*ReencryptResult, error) {
+	spec, ok := piiTables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %s has no personal data columns", table)
+	}
+	if l.cipher == nil {
+		return nil, fmt.Errorf("PII key is not configured")
+	}
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	result := &ReencryptResult{Table: table}
+	var lastID int64
+	for {
+		scanned, updated, maxID, err := l.reencryptBatch(table, spec, lastID, batchSize)
+		result.Scanned += scanned
+		result.Updated += updated
+		if err != nil {
+			return result, err
+		}
+		if scanned < int64(batchSize) {
+			return result, nil
+		}
+		lastID = maxID
+	}
+}
+
+// reencryptBatch 行を特定する列（keyColumn）がafterIDより大きい行をlimit件確認し、必要な行を更新
+func (l *Logger) reencryptBatch(table string, spec piiTable, afterID int64, limit int) (int64, int64, int64, error) {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	key := spec.keyColumn()
+	selectColumns := append([]string{key}, spec.columns...)
+	for _, d := range spec.derived {
+		selectColumns = append(selectColumns, d.column)
+	}
+	rows, err := tx.Query(`SELECT `+strings.Join(selectColumns, ", ")+` FROM `+table+` WHERE `+key+` > ? ORDER BY `+key+` LIMIT ?`, afterID, limit)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+
+	type row struct {
+		id     int64
+		values []sql.NullString // spec.columns、spec.derivedの順
+	}
+	var batch []row
+	for rows.Next() {
+		r := row{values: make([]sql.NullString, len(selectColumns)-1)}
+		dest := []any{&r.id}
+		for i := range r.values {
+			dest = append(dest, &r.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, 0, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+
+	setColumns := make([]string, 0, len(selectColumns)-1)
+	for _, column := range selectColumns[1:] {
+		setColumns = append(setColumns, column+" = ?")
+	}
+	update := `UPDATE ` + table + ` SET ` + strings.Join(setColumns, ", ") + ` WHERE ` + key + ` = ?`
+
+	var updated, maxID int64
+	for _, r := range batch {
+		maxID = r.id
+
+		changed := false
+		args := make([]any, 0, len(r.values)+1)
+		plaintexts := make(map[string]string, len(spec.columns))
+		for i, column := range spec.columns {
+			value := r.values[i]
+			field := table + "." + column
+			plaintext, err := l.cipher.Decrypt(field, value.String)
+			if err != nil {
+				return 0, 0, 0, fmt.Errorf("row %d: %w", r.id, err)
+			}
+			plaintexts[column] = plaintext
+
+			if !value.Valid || !l.cipher.NeedsReencrypt(value.String) {
+				args = append(args, value)
+				continue
+			}
+			encrypted, err := l.cipher.Encrypt(field, plaintext)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			args = append(args, encrypted)
+			changed = true
+		}
+		for i, d := range spec.derived {
+			value := d.derive(l.cipher, plaintexts[d.source])
+			if r.values[len(spec.columns)+i].String != value {
+				changed = true
+			}
+			args = append(args, nullString(value))
+		}
+
+		if !changed {
+			continue
+		}
+		if _, err := tx.Exec(update, append(args, r.id)...); err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to update %s: %w", table, err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int64(len(batch)), updated, maxID, nil
+}
+
+// PseudonymizeLogs 以前に記録したログのcard_idを仮名（card_ref）に置き換え、attrsの有効期限などを秘匿化する
+// PII_KEYを設定していない場合はcard_idを仮名の代わりにpii.Redactedに置き換える。batchSize件ずつのトランザクションで更新する
+func (l *Logger) PseudonymizeLogs(batchSize int) (*ReencryptResult, error) {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	result := &ReencryptResult{Table: "logs"}
+	var lastID int64
+	for {
+		scanned, updated, maxID, err := l.pseudonymizeLogBatch(lastID, batchSize)
+		result.Scanned += scanned
+		result.Updated += updated
+		if err != nil {
+			return result, err
+		}
+		if scanned < int64(batchSize) {
+			return result, nil
+		}
+		lastID = maxID
+	}
+}
+
+// pseudonymizeLogBatch idがafterIDより大きいログをlimit件確認し、必要な行を更新
+func (l *Logger) pseudonymizeLogBatch(afterID int64, limit int) (int64, int64, int64, error) {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, card_id, card_ref, attrs FROM logs WHERE id > ? ORDER BY id LIMIT ?`, afterID, limit)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to query logs: %w", err)
+	}
+
+	type row struct {
+		id                     int64
+		cardID, cardRef, attrs sql.NullString
+	}
+	var batch []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.cardID, &r.cardRef, &r.attrs); err != nil {
+			rows.Close()
+			return 0, 0, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to query logs: %w", err)
+	}
+
+	var updated, maxID int64
+	for _, r := range batch {
+		maxID = r.id
+
+		cardID, cardRef := r.cardID.String, r.cardRef
+		if cardID != "" {
+			cardID, cardRef = l.logCardColumns(cardID)
+		}
+		attrs, attrsChanged := l.redactAttrs(r.attrs.String)
+		if cardID == r.cardID.String && !attrsChanged {
+			continue
+		}
+
+		if _, err := tx.Exec(`UPDATE logs SET card_id = ?, card_ref = ?, attrs = ? WHERE id = ?`,
+			cardID, cardRef, nullString(attrs), r.id); err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to update logs: %w", err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int64(len(batch)), updated, maxID, nil
+}
+
+// redactAttrs ログのattrs（JSON）のカードの識別子・有効期限などを秘匿化（変更がない場合はfalse）
+// DBHandlerはグループの属性を"group.key"で保存するため、グループ名を除いたキーで判定する
+func (l *Logger) redactAttrs(attrs string) (string, bool) {
+	if attrs == "" {
+		return attrs, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(attrs), &fields); err != nil {
+		return attrs, false
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	changed := false
+	for _, key := range keys {
+		group, leaf := "", key
+		if i := strings.LastIndexByte(key, '.'); i >= 0 {
+			group, leaf = key[:i+1], key[i+1:]
+		}
+		value := fmt.Sprint(fields[key])
+		newKey, newValue, ok := l.cipher.RedactAttr(leaf, value)
+		if !ok || (group+newKey == key && newValue == value) {
+			continue
+		}
+		delete(fields, key)
+		fields[group+newKey] = newValue
+		changed = true
+	}
+	if !changed {
+		return attrs, false
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return attrs, false
+	}
+	return string(data), true
+}
+
+// nullString 空の場合はNULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// ScrubFreePages 暗号化する前の値が空きページやWALに残らないように、データベースを作り直してWALを空にする
+func (l *Logger) ScrubFreePages() error {
+	if _, err := l.db.Exec(`VACUUM`); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	if _, err := l.db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	return nil
+}
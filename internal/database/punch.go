@@ -0,0 +1,136 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PunchRecord 出退勤打刻の送信待ちレコード
+type PunchRecord struct {
+	ID        int64
+	Timestamp time.Time
+	ReaderID  string
+	CardID    string
+	DriverID  int32
+	State     string
+	Sent      bool
+	Attempts  int
+	LastError string
+}
+
+// EnqueuePunch 打刻を送信待ちに追加
+func (l *Logger) EnqueuePunch(record *PunchRecord) error {
+	query := `INSERT INTO punch_outbox (timestamp, reader_id, card_id, card_id_hash, driver_id, state)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	cardID, cardIDHash, err := l.cardColumns("punch_outbox.card_id", record.CardID)
+	if err != nil {
+		return err
+	}
+
+	result, err := l.db.Exec(query,
+		record.Timestamp.UTC().Format(timestampLayout),
+		record.ReaderID,
+		cardID,
+		cardIDHash,
+		record.DriverID,
+		record.State,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert punch: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	record.ID = id
+
+	return nil
+}
+
+// MarkPunchSent 打刻を送信済みにする
+func (l *Logger) MarkPunchSent(id int64) error {
+	query := `UPDATE punch_outbox SET sent = 1, sent_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = ?`
+
+	if _, err := l.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to mark punch sent: %w", err)
+	}
+	return nil
+}
+
+// MarkPunchFailed 打刻の送信失敗を記録
+func (l *Logger) MarkPunchFailed(id int64, sendErr error) error {
+	query := `UPDATE punch_outbox SET attempts = attempts + 1, last_error = ? WHERE id = ?`
+
+	if _, err := l.db.Exec(query, sendErr.Error(), id); err != nil {
+		return fmt.Errorf("failed to mark punch failed: %w", err)
+	}
+	return nil
+}
+
+// LastPunch カードのsince以降で最後の打刻を取得（ない場合はnil）
+func (l *Logger) LastPunch(cardID string, since time.Time) (*PunchRecord, error) {
+	match, args := l.cardMatch("card_id", "card_id_hash", cardID)
+	records, err := l.queryPunches(`WHERE `+match+` AND timestamp >= ? ORDER BY timestamp DESC, id DESC LIMIT 1`,
+		append(args, since.UTC().Format(timestampLayout))...)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// GetPendingPunches 未送信の打刻を古い順に取得
+func (l *Logger) GetPendingPunches(limit int) ([]*PunchRecord, error) {
+	return l.queryPunches(`WHERE sent = 0 ORDER BY timestamp, id LIMIT ?`, limit)
+}
+
+// CountPendingPunches 未送信の打刻の件数
+func (l *Logger) CountPendingPunches() (int, error) {
+	var count int
+	if err := l.db.QueryRow(`SELECT COUNT(*) FROM punch_outbox WHERE sent = 0`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count punches: %w", err)
+	}
+	return count, nil
+}
+
+// ListPunchesBetween start〜endの打刻を古い順に全件取得（送信済み・未送信とも）
+func (l *Logger) ListPunchesBetween(start, end time.Time) ([]*PunchRecord, error) {
+	return l.queryPunches(`WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp, id`,
+		start.UTC().Format(timestampLayout), end.UTC().Format(timestampLayout))
+}
+
+func (l *Logger) queryPunches(where string, args ...interface{}) ([]*PunchRecord, error) {
+	query := `SELECT id, timestamp, reader_id, card_id, driver_id, state, sent, attempts, last_error
+		FROM punch_outbox ` + where
+
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query punches: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*PunchRecord
+
+	for rows.Next() {
+		record := &PunchRecord{}
+		var timestamp string
+		var driverID sql.NullInt64
+		var lastError sql.NullString
+
+		if err := rows.Scan(&record.ID, &timestamp, &record.ReaderID, &record.CardID, &driverID,
+			&record.State, &record.Sent, &record.Attempts, &lastError); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if record.CardID, err = l.cipher.Decrypt("punch_outbox.card_id", record.CardID); err != nil {
+			return nil, err
+		}
+
+		record.Timestamp = parseTimestamp(timestamp)
+		record.DriverID = int32(driverID.Int64)
+		record.LastError = lastError.String
+		records = append(records, record)
+	}
+
+	return records, nil
+}
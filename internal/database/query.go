@@ -0,0 +1,93 @@
+package database
+
+import "time"
+
+// デフォルト・最大の取得件数
+const (
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+)
+
+// queryFilter WHERE句の組み立て
+// timestampとidの組でページングする（同時刻のレコードはidで順序付け）
+// timestampは"YYYY-MM-DD HH:MM:SS"（UTC）で保存されているため文字列のまま比較できる
+type queryFilter struct {
+	where string
+	args  []interface{}
+}
+
+func newQueryFilter() *queryFilter {
+	return &queryFilter{}
+}
+
+// equal 値が空でない場合のみ条件に追加
+func (f *queryFilter) equal(column, value string) {
+	if value == "" {
+		return
+	}
+	f.where += ` AND ` + column + ` = ?`
+	f.args = append(f.args, value)
+}
+
+// equalIndexed 暗号化した列をキー付きハッシュの列で検索（hashが空の場合は暗号化していないためcolumnと比較する）
+// ハッシュを付ける前の行（PII_KEYを設定する前の平文の行）はcolumnと比較する
+func (f *queryFilter) equalIndexed(column, hashColumn, value, hash string) {
+	if value == "" {
+		return
+	}
+	if hash == "" {
+		f.equal(column, value)
+		return
+	}
+	f.where += ` AND (` + hashColumn + ` = ? OR (` + hashColumn + ` IS NULL AND ` + column + ` = ?))`
+	f.args = append(f.args, hash, value)
+}
+
+// timeRange Unix時刻の範囲（0の場合は指定なし、両端を含む）
+func (f *queryFilter) timeRange(startTime, endTime int64) {
+	if startTime > 0 {
+		f.where += ` AND timestamp >= ?`
+		f.args = append(f.args, time.Unix(startTime, 0).UTC().Format(timestampLayout))
+	}
+	if endTime > 0 {
+		f.where += ` AND timestamp <= ?`
+		f.args = append(f.args, time.Unix(endTime, 0).UTC().Format(timestampLayout))
+	}
+}
+
+// after 前のページの続きから取得
+func (f *queryFilter) after(cursor *Cursor, ascending bool) {
+	if cursor == nil {
+		return
+	}
+
+	op := "<"
+	if ascending {
+		op = ">"
+	}
+
+	ts := cursor.Timestamp.UTC().Format(timestampLayout)
+	f.where += ` AND (timestamp ` + op + ` ? OR (timestamp = ? AND id ` + op + ` ?))`
+	f.args = append(f.args, ts, ts, cursor.ID)
+}
+
+// page 並び順と件数を追加
+// 続きの有無を判定するため1件多く取得する。返り値のlimitは実際に返す件数
+func (f *queryFilter) page(query string, ascending bool, limit int32) (string, []interface{}, int) {
+	n := int(limit)
+	if n <= 0 {
+		n = defaultQueryLimit
+	}
+	if n > maxQueryLimit {
+		n = maxQueryLimit
+	}
+
+	if ascending {
+		query += ` ORDER BY timestamp ASC, id ASC`
+	} else {
+		query += ` ORDER BY timestamp DESC, id DESC`
+	}
+	query += ` LIMIT ?`
+
+	return query, append(f.args, n+1), n
+}
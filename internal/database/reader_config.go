@@ -0,0 +1,129 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// 設定の適用範囲（全体→拠点→リーダーの順に重ねる）
+const (
+	ConfigScopeGlobal = "global"
+	ConfigScopeSite   = "site"
+	ConfigScopeReader = "reader"
+)
+
+// ReaderConfigRecord リーダーの設定（1バージョン分）
+type ReaderConfigRecord struct {
+	Version   int64
+	Scope     string
+	Target    string // 拠点名またはリーダーID（globalの場合は空）
+	Settings  string // JSON（空の場合は削除）
+	CreatedAt time.Time
+	CreatedBy string
+	Note      string
+}
+
+// Deleted 削除を表すレコードか
+func (r *ReaderConfigRecord) Deleted() bool {
+	return r.Settings == ""
+}
+
+// SaveReaderConfig 設定を保存し、新しいバージョンを返す（settingsが空の場合は削除）
+func (l *Logger) SaveReaderConfig(scope, target, settings, createdBy, note string) (int64, error) {
+	var value interface{}
+	if settings != "" {
+		value = settings
+	}
+
+	result, err := l.db.Exec(`INSERT INTO reader_configs (scope, target, settings, created_by, note) VALUES (?, ?, ?, ?, ?)`,
+		scope, target, value, createdBy, note)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert reader config: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetReaderConfigVersion 指定したバージョンの設定を取得
+func (l *Logger) GetReaderConfigVersion(version int64) (*ReaderConfigRecord, error) {
+	records, err := l.queryReaderConfigs(`SELECT version, scope, target, settings, created_at, created_by, note
+		FROM reader_configs WHERE version = ?`, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("reader config version not found: %d", version)
+	}
+	return records[0], nil
+}
+
+// ApplicableReaderConfigs リーダーに適用される最新の設定を全体・拠点・リーダーの順に取得
+// 削除を表すレコードも含む（バージョンの比較に使うため）
+func (l *Logger) ApplicableReaderConfigs(readerID, site string) ([]*ReaderConfigRecord, error) {
+	query := `SELECT version, scope, target, settings, created_at, created_by, note
+		FROM reader_configs c
+		WHERE version = (SELECT MAX(version) FROM reader_configs WHERE scope = c.scope AND target = c.target)
+		AND ((scope = ? AND target = '') OR (scope = ? AND target = ?) OR (scope = ? AND target = ?))
+		ORDER BY CASE scope WHEN ? THEN 0 WHEN ? THEN 1 ELSE 2 END`
+
+	return l.queryReaderConfigs(query,
+		ConfigScopeGlobal, ConfigScopeSite, site, ConfigScopeReader, readerID,
+		ConfigScopeGlobal, ConfigScopeSite)
+}
+
+// ListReaderConfigs 設定の一覧を取得（historyがfalseの場合は各適用範囲の最新のみ、削除済みは除く）
+func (l *Logger) ListReaderConfigs(history bool) ([]*ReaderConfigRecord, error) {
+	query := `SELECT version, scope, target, settings, created_at, created_by, note FROM reader_configs c`
+	if !history {
+		query += ` WHERE version = (SELECT MAX(version) FROM reader_configs WHERE scope = c.scope AND target = c.target)
+			AND settings IS NOT NULL`
+	}
+	query += " ORDER BY scope, target, version"
+
+	return l.queryReaderConfigs(query)
+}
+
+func (l *Logger) queryReaderConfigs(query string, args ...interface{}) ([]*ReaderConfigRecord, error) {
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reader configs: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ReaderConfigRecord
+	for rows.Next() {
+		record := &ReaderConfigRecord{}
+		var createdAt string
+		var settings, createdBy, note sql.NullString
+
+		if err := rows.Scan(&record.Version, &record.Scope, &record.Target, &settings,
+			&createdAt, &createdBy, &note); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		record.Settings = settings.String
+		record.CreatedAt = parseTimestamp(createdAt)
+		record.CreatedBy = createdBy.String
+		record.Note = note.String
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// ReportReaderConfigStatus リーダーの設定の適用結果を記録（errMsgが空の場合は適用済み）
+func (l *Logger) ReportReaderConfigStatus(readerID string, version int64, errMsg string) error {
+	query := `INSERT INTO reader_config_status (reader_id, version, error, reported_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(reader_id) DO UPDATE SET
+			version = excluded.version,
+			error = excluded.error,
+			reported_at = excluded.reported_at`
+
+	_, err := l.db.Exec(query, readerID, version, errMsg, time.Now().UTC().Format(timestampLayout))
+	if err != nil {
+		return fmt.Errorf("failed to record reader config status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReaderRecord 登録済みのリーダー（端末）
+type ReaderRecord struct {
+	ReaderID     string
+	Site         string
+	Location     string
+	Version      string
+	BuildTime    string
+	NFCDevices   []string
+	Hostname     string
+	PeerAddr     string
+	RegisteredAt time.Time
+	LastSeen     time.Time
+	StaleSince   time.Time // 応答なしと判定した時刻（ゼロ値の場合は未判定）
+
+	ConfigVersion int64  // 適用済みの設定のバージョン
+	ConfigError   string // 設定の適用に失敗した場合のエラー
+}
+
+// RegisterReader リーダーを登録（登録済みの場合は情報を更新し、応答なしの判定を解除）
+func (l *Logger) RegisterReader(record *ReaderRecord) error {
+	devices, err := json.Marshal(record.NFCDevices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal nfc devices: %w", err)
+	}
+
+	query := `INSERT INTO readers (reader_id, site, location, version, build_time, nfc_devices, hostname, peer_addr, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(reader_id) DO UPDATE SET
+			site = excluded.site,
+			location = excluded.location,
+			version = excluded.version,
+			build_time = excluded.build_time,
+			nfc_devices = excluded.nfc_devices,
+			hostname = excluded.hostname,
+			peer_addr = excluded.peer_addr,
+			last_seen = excluded.last_seen,
+			stale_since = NULL`
+
+	_, err = l.db.Exec(query,
+		record.ReaderID,
+		record.Site,
+		record.Location,
+		record.Version,
+		record.BuildTime,
+		string(devices),
+		record.Hostname,
+		record.PeerAddr,
+		time.Now().UTC().Format(timestampLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register reader: %w", err)
+	}
+
+	return nil
+}
+
+// TouchReader リーダーの最終応答時刻を更新（nfcDevicesがnilの場合は更新しない）
+// 登録されていない場合はfound=false、応答なしから復帰した場合はwasStale=trueを返す
+func (l *Logger) TouchReader(readerID string, nfcDevices []string, peerAddr string) (found bool, wasStale bool, err error) {
+	var staleSince sql.NullString
+	err = l.db.QueryRow(`SELECT stale_since FROM readers WHERE reader_id = ?`, readerID).Scan(&staleSince)
+	if err == sql.ErrNoRows {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to query reader: %w", err)
+	}
+
+	query := `UPDATE readers SET last_seen = ?, peer_addr = ?, stale_since = NULL`
+	args := []interface{}{time.Now().UTC().Format(timestampLayout), peerAddr}
+
+	if nfcDevices != nil {
+		devices, err := json.Marshal(nfcDevices)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to marshal nfc devices: %w", err)
+		}
+		query += ", nfc_devices = ?"
+		args = append(args, string(devices))
+	}
+
+	query += " WHERE reader_id = ?"
+	args = append(args, readerID)
+
+	if _, err := l.db.Exec(query, args...); err != nil {
+		return false, false, fmt.Errorf("failed to update reader: %w", err)
+	}
+
+	return true, staleSince.Valid, nil
+}
+
+// ListReaders 登録済みのリーダー一覧を取得（siteが空の場合は全拠点）
+func (l *Logger) ListReaders(site string) ([]*ReaderRecord, error) {
+	query := `SELECT r.reader_id, r.site, r.location, r.version, r.build_time, r.nfc_devices, r.hostname, r.peer_addr,
+		r.registered_at, r.last_seen, r.stale_since, s.version, s.error
+		FROM readers r LEFT JOIN reader_config_status s ON s.reader_id = r.reader_id WHERE 1=1`
+	args := []interface{}{}
+
+	if site != "" {
+		query += " AND r.site = ?"
+		args = append(args, site)
+	}
+
+	query += " ORDER BY r.site, r.reader_id"
+
+	return l.queryReaders(query, args...)
+}
+
+// MarkStaleReaders cutoffより前から応答のないリーダーを応答なしと判定
+// 新たに応答なしと判定したリーダーを返す
+func (l *Logger) MarkStaleReaders(cutoff time.Time) ([]*ReaderRecord, error) {
+	records, err := l.queryReaders(`SELECT reader_id, site, location, version, build_time, nfc_devices, hostname, peer_addr,
+		registered_at, last_seen, stale_since, NULL, NULL
+		FROM readers WHERE last_seen < ? AND stale_since IS NULL ORDER BY reader_id`,
+		cutoff.UTC().Format(timestampLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for _, record := range records {
+		// 判定の間に応答があった場合は更新しない
+		if _, err := l.db.Exec(`UPDATE readers SET stale_since = ? WHERE reader_id = ? AND last_seen < ? AND stale_since IS NULL`,
+			now.Format(timestampLayout), record.ReaderID, cutoff.UTC().Format(timestampLayout)); err != nil {
+			return nil, fmt.Errorf("failed to mark reader stale: %w", err)
+		}
+		record.StaleSince = now
+	}
+
+	return records, nil
+}
+
+func (l *Logger) queryReaders(query string, args ...interface{}) ([]*ReaderRecord, error) {
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query readers: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ReaderRecord
+	for rows.Next() {
+		record := &ReaderRecord{}
+		var site, location, version, buildTime, devices, hostname, peerAddr, staleSince, configError sql.NullString
+		var registeredAt, lastSeen string
+		var configVersion sql.NullInt64
+
+		if err := rows.Scan(&record.ReaderID, &site, &location, &version, &buildTime, &devices,
+			&hostname, &peerAddr, &registeredAt, &lastSeen, &staleSince, &configVersion, &configError); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		record.Site = site.String
+		record.Location = location.String
+		record.Version = version.String
+		record.BuildTime = buildTime.String
+		record.Hostname = hostname.String
+		record.PeerAddr = peerAddr.String
+		record.RegisteredAt = parseTimestamp(registeredAt)
+		record.LastSeen = parseTimestamp(lastSeen)
+		if staleSince.Valid {
+			record.StaleSince = parseTimestamp(staleSince.String)
+		}
+		record.ConfigVersion = configVersion.Int64
+		record.ConfigError = configError.String
+		if devices.String != "" {
+			json.Unmarshal([]byte(devices.String), &record.NFCDevices)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,245 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// prunableTable 保持期間を過ぎた行を削除できるテーブル
+type prunableTable struct {
+	timeColumn string // 保持期間を判定する時刻の列
+	condition  string // 削除してよい行の条件（未送信・未解決の行は残す）
+}
+
+// prunableTables 保持期間を設定できるテーブル
+var prunableTables = map[string]prunableTable{
+	"logs":                 {timeColumn: "timestamp"},
+	"read_history":         {timeColumn: "timestamp"},
+	"alcohol_measurements": {timeColumn: "timestamp"},
+	"auth_audit":           {timeColumn: "timestamp"},
+	"dispatch_assignments": {timeColumn: "timestamp", condition: "(synced = 1 OR status = 'rejected')"},
+	"punch_outbox":         {timeColumn: "timestamp", condition: "sent = 1"},
+	"punch_anomalies":      {timeColumn: "detected_at", condition: "resolved = 1"},
+	"upload_outbox":        {timeColumn: "created_at", condition: "sent = 1"},
+	"webhook_deliveries":   {timeColumn: "created_at", condition: "status != 'pending'"},
+}
+
+// PrunableTables 保持期間を設定できるテーブル名
+func PrunableTables() []string {
+	tables := make([]string, 0, len(prunableTables))
+	for table := range prunableTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// IsLogLevel ログレベルとして有効か（DEBUG / INFO / WARNING / ERROR）
+func IsLogLevel(level string) bool {
+	_, ok := logLevels[strings.ToUpper(level)]
+	return ok
+}
+
+// PruneSpec 削除する行の条件
+type PruneSpec struct {
+	Table         string
+	Before        time.Time // この時刻より前の行を削除
+	Level         string    // logsのみ: このレベルの行のみ削除（空の場合は全てのレベル）
+	ExcludeLevels []string  // logsのみ: 削除しないレベル（別の保持期間を設定したレベル）
+	BatchSize     int       // 1回のトランザクションで削除する件数（0の場合は1000）
+}
+
+// PruneArchiver 削除する行を削除の前に受け取る（エラーの場合は削除しない）
+type PruneArchiver func(rows []map[string]any) error
+
+// Prune 保持期間を過ぎた行をBatchSize件ずつ削除し、削除した件数を返す
+// archiveがnilでない場合は、各バッチを削除の前に渡す
+func (l *Logger) Prune(spec PruneSpec, archive PruneArchiver) (int64, error) {
+	table, ok := prunableTables[spec.Table]
+	if !ok {
+		return 0, fmt.Errorf("table %s does not support retention", spec.Table)
+	}
+	if (spec.Level != "" || len(spec.ExcludeLevels) > 0) && spec.Table != "logs" {
+		return 0, fmt.Errorf("log levels can only be set for the logs table")
+	}
+
+	batchSize := spec.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	where := []string{table.timeColumn + " < ?"}
+	args := []any{spec.Before.UTC().Format(timestampLayout)}
+	if table.condition != "" {
+		where = append(where, table.condition)
+	}
+	if spec.Level != "" {
+		where = append(where, "level = ?")
+		args = append(args, strings.ToUpper(spec.Level))
+	}
+	if len(spec.ExcludeLevels) > 0 {
+		where = append(where, "level NOT IN (?"+strings.Repeat(", ?", len(spec.ExcludeLevels)-1)+")")
+		for _, level := range spec.ExcludeLevels {
+			args = append(args, strings.ToUpper(level))
+		}
+	}
+	condition := strings.Join(where, " AND ")
+
+	var total int64
+	for {
+		n, err := l.pruneBatch(spec.Table, condition, args, batchSize, archive)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// pruneBatch 条件に一致する行をid順にbatchSize件まで削除
+// 一致する先頭のbatchSize件を読み出し、同じ条件でその最大のid以下の行を削除する（同じトランザクション内なので読み出した行と一致する）
+func (l *Logger) pruneBatch(table, condition string, args []any, batchSize int, archive PruneArchiver) (int64, error) {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var maxID int64
+	var rows []map[string]any
+	if archive != nil {
+		if rows, maxID, err = selectRows(tx, table, condition, args, batchSize); err != nil {
+			return 0, err
+		}
+	} else {
+		query := fmt.Sprintf(`SELECT COALESCE(MAX(id), 0) FROM (SELECT id FROM %s WHERE %s ORDER BY id LIMIT ?)`, table, condition)
+		if err := tx.QueryRow(query, append(args, batchSize)...).Scan(&maxID); err != nil {
+			return 0, fmt.Errorf("failed to query %s: %w", table, err)
+		}
+	}
+	if maxID == 0 {
+		return 0, nil
+	}
+
+	if archive != nil {
+		if err := archive(rows); err != nil {
+			return 0, fmt.Errorf("failed to archive %s: %w", table, err)
+		}
+	}
+
+	result, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE %s AND id <= ?`, table, condition), append(args, maxID)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune %s: %w", table, err)
+	}
+	n, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit prune of %s: %w", table, err)
+	}
+	return n, nil
+}
+
+// selectRows 削除する行を列名をキーにしたmapで読み出し、最大のidを返す
+func selectRows(tx *sql.Tx, table, condition string, args []any, limit int) ([]map[string]any, int64, error) {
+	rows, err := tx.Query(fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY id LIMIT ?`, table, condition), append(args, limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get columns of %s: %w", table, err)
+	}
+
+	var records []map[string]any
+	var maxID int64
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+
+		record := make(map[string]any, len(columns))
+		for i, column := range columns {
+			record[column] = values[i]
+		}
+		if id, ok := record["id"].(int64); ok {
+			maxID = id
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+
+	return records, maxID, nil
+}
+
+// 自動VACUUMのモード（PRAGMA auto_vacuum）
+const autoVacuumIncremental = 2
+
+// EnableIncrementalVacuum 削除した領域をincremental_vacuumで解放できるようにする
+// 既存のデータベースは一度だけVACUUMで作り直す（データベースと同程度の空き容量が必要）。変換した場合はtrue
+func (l *Logger) EnableIncrementalVacuum() (bool, error) {
+	var mode int
+	if err := l.db.QueryRow(`PRAGMA auto_vacuum`).Scan(&mode); err != nil {
+		return false, fmt.Errorf("failed to query auto_vacuum: %w", err)
+	}
+	if mode == autoVacuumIncremental {
+		return false, nil
+	}
+
+	// 設定はVACUUMで反映されるため同じ接続で実行する
+	conn, err := l.db.Conn(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(context.Background(), `PRAGMA auto_vacuum = INCREMENTAL`); err != nil {
+		return false, fmt.Errorf("failed to set auto_vacuum: %w", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), `VACUUM`); err != nil {
+		return false, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return true, nil
+}
+
+// IncrementalVacuum 削除で空いたページを解放し、解放したページ数を返す
+func (l *Logger) IncrementalVacuum() (int64, error) {
+	var before, after int64
+	if err := l.db.QueryRow(`PRAGMA freelist_count`).Scan(&before); err != nil {
+		return 0, fmt.Errorf("failed to query freelist_count: %w", err)
+	}
+	if before == 0 {
+		return 0, nil
+	}
+
+	// incremental_vacuumは結果の行を読み切るまで実行が続くためQueryで読み切る
+	rows, err := l.db.Query(`PRAGMA incremental_vacuum`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to run incremental_vacuum: %w", err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to run incremental_vacuum: %w", err)
+	}
+
+	if err := l.db.QueryRow(`PRAGMA freelist_count`).Scan(&after); err != nil {
+		return 0, fmt.Errorf("failed to query freelist_count: %w", err)
+	}
+	return before - after, nil
+}
@@ -0,0 +1,16 @@
+package database
+
+import "time"
+
+// timestampLayout SQLiteに保存する時刻の形式（UTC）
+const timestampLayout = "2006-01-02 15:04:05"
+
+// parseTimestamp SQLiteから読み出した時刻を変換
+// go-sqlite3はDATETIME型の列をRFC3339形式で返すため、両方の形式を受け付ける
+func parseTimestamp(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	t, _ := time.Parse(timestampLayout, s)
+	return t
+}
@@ -0,0 +1,242 @@
+package database
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 送信待ちの種類
+const (
+	UploadKindLicenseData = "license_data"
+	UploadKindReadLog     = "read_log"
+)
+
+// PendingUpload サーバーへの送信待ち（リーダー側）
+type PendingUpload struct {
+	Seq       int64
+	CreatedAt time.Time
+	Kind      string
+	Payload   []byte // protoでシリアライズしたLicenseData/ReadLog
+}
+
+// EnqueueUpload サーバーへの送信待ちに追加（payloadはカードの識別子・免許証の共通データを含むため暗号化して保存する）
+func (l *Logger) EnqueueUpload(kind string, payload []byte) error {
+	encrypted, err := l.cipher.Encrypt("upload_outbox.payload", string(payload))
+	if err != nil {
+		return err
+	}
+
+	_, err = l.db.Exec(`INSERT INTO upload_outbox (kind, payload) VALUES (?, ?)`, kind, []byte(encrypted))
+	if err != nil {
+		return fmt.Errorf("failed to insert upload: %w", err)
+	}
+	return nil
+}
+
+// GetPendingUploads 未送信のデータを古い順に取得
+func (l *Logger) GetPendingUploads(limit int) ([]*PendingUpload, error) {
+	rows, err := l.db.Query(`SELECT id, created_at, kind, payload FROM upload_outbox
+		WHERE sent = 0 ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query uploads: %w", err)
+	}
+	defer rows.Close()
+
+	var uploads []*PendingUpload
+	for rows.Next() {
+		upload := &PendingUpload{}
+		var createdAt string
+
+		if err := rows.Scan(&upload.Seq, &createdAt, &upload.Kind, &upload.Payload); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		payload, err := l.cipher.Decrypt("upload_outbox.payload", string(upload.Payload))
+		if err != nil {
+			return nil, err
+		}
+		upload.Payload = []byte(payload)
+
+		upload.CreatedAt = parseTimestamp(createdAt)
+		uploads = append(uploads, upload)
+	}
+
+	return uploads, nil
+}
+
+// MarkUploadsSent サーバーで確定した連番以下を送信済みにする（送信済みの行には個人データを残さないため内容を消す）
+func (l *Logger) MarkUploadsSent(ackedSeq int64) error {
+	_, err := l.db.Exec(`UPDATE upload_outbox SET sent = 1, sent_at = CURRENT_TIMESTAMP, payload = X''
+		WHERE sent = 0 AND id <= ?`, ackedSeq)
+	if err != nil {
+		return fmt.Errorf("failed to mark uploads sent: %w", err)
+	}
+	return nil
+}
+
+// CountPendingUploads 未送信の件数
+func (l *Logger) CountPendingUploads() (int, error) {
+	var count int
+	if err := l.db.QueryRow(`SELECT COUNT(*) FROM upload_outbox WHERE sent = 0`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count uploads: %w", err)
+	}
+	return count, nil
+}
+
+// UploadSource このDBの送信元の識別子（初回に生成）
+// DBを作り直すと連番が1から振り直されるため、サーバーは送信元ごとに確定済みの連番を管理する
+func (l *Logger) UploadSource() (string, error) {
+	var source string
+	err := l.db.QueryRow(`SELECT source FROM upload_source WHERE id = 1`).Scan(&source)
+	if err == nil {
+		return source, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to query upload source: %w", err)
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate upload source: %w", err)
+	}
+
+	// 同時に生成された場合は先に保存された方を使う
+	if _, err := l.db.Exec(`INSERT OR IGNORE INTO upload_source (id, source) VALUES (1, ?)`, hex.EncodeToString(b)); err != nil {
+		return "", fmt.Errorf("failed to insert upload source: %w", err)
+	}
+	if err := l.db.QueryRow(`SELECT source FROM upload_source WHERE id = 1`).Scan(&source); err != nil {
+		return "", fmt.Errorf("failed to query upload source: %w", err)
+	}
+
+	return source, nil
+}
+
+// UploadEntry 一括送信の1件（ReadまたはLogのどちらか）
+type UploadEntry struct {
+	Seq  int64
+	Read *ReadHistoryRecord
+	Log  *LogEntry
+}
+
+// UploadProgress 送信元の確定済みの連番（サーバー側）
+func (l *Logger) UploadProgress(readerID, source string) (int64, error) {
+	var lastSeq int64
+	err := l.db.QueryRow(`SELECT last_seq FROM upload_progress WHERE reader_id = ? AND source = ?`,
+		readerID, source).Scan(&lastSeq)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to query upload progress: %w", err)
+	}
+	return lastSeq, nil
+}
+
+// CommitUploads 一括送信を1つのトランザクションで記録し、確定した最大の連番を返す（サーバー側）
+// 確定済みの連番以下のエントリは重複として記録せず、新たに記録したエントリのみcommittedで返す
+func (l *Logger) CommitUploads(readerID, source string, entries []*UploadEntry) (int64, []*UploadEntry, error) {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var lastSeq int64
+	err = tx.QueryRow(`SELECT last_seq FROM upload_progress WHERE reader_id = ? AND source = ?`,
+		readerID, source).Scan(&lastSeq)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, fmt.Errorf("failed to query upload progress: %w", err)
+	}
+
+	var committed []*UploadEntry
+	for _, entry := range entries {
+		if entry.Seq <= lastSeq {
+			continue
+		}
+
+		if entry.Read != nil {
+			if err := l.insertReadHistory(tx, entry.Read); err != nil {
+				return 0, nil, err
+			}
+		}
+		if entry.Log != nil {
+			if err := l.insertLog(tx, entry.Log); err != nil {
+				return 0, nil, err
+			}
+		}
+
+		lastSeq = entry.Seq
+		committed = append(committed, entry)
+	}
+
+	if len(committed) > 0 {
+		// 確定済みの連番は小さくしない（同じ送信元の別のストリームが先に大きい連番を確定した場合）
+		query := `INSERT INTO upload_progress (reader_id, source, last_seq, updated_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT(reader_id, source) DO UPDATE SET
+				last_seq = MAX(upload_progress.last_seq, excluded.last_seq),
+				updated_at = excluded.updated_at
+			RETURNING last_seq`
+
+		if err := tx.QueryRow(query, readerID, source, lastSeq, time.Now().UTC().Format(timestampLayout)).Scan(&lastSeq); err != nil {
+			return 0, nil, fmt.Errorf("failed to update upload progress: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit uploads: %w", err)
+	}
+
+	return lastSeq, committed, nil
+}
+
+// execer *sql.DBと*sql.Txの共通のExec
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertReadHistory 読み取り時刻を指定して読み取り履歴を記録
+func (l *Logger) insertReadHistory(tx execer, record *ReadHistoryRecord) error {
+	args, err := l.readHistoryArgs(record)
+	if err != nil {
+		return fmt.Errorf("failed to insert read history: %w", err)
+	}
+
+	result, err := tx.Exec(readHistoryInsert, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert read history: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	record.ID = id
+
+	return nil
+}
+
+// insertLog 時刻を指定してログを記録（記録するレベル未満の場合は記録しない）
+func (l *Logger) insertLog(tx *sql.Tx, entry *LogEntry) error {
+	if n, ok := logLevels[entry.Level]; ok && n < l.minLevel.Load() {
+		return nil
+	}
+
+	query := `INSERT INTO logs (timestamp, level, message, reader_id, card_id, card_ref, process_id) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	cardID, cardRef := l.logCardColumns(entry.CardID)
+	result, err := tx.Exec(query,
+		entry.Timestamp.UTC().Format(timestampLayout),
+		entry.Level,
+		entry.Message,
+		entry.ReaderID,
+		cardID,
+		cardRef,
+		l.processID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert log: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	entry.ID = id
+
+	return nil
+}
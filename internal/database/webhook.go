@@ -0,0 +1,329 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Webhookの送信状態
+const (
+	WebhookStatusPending   = "pending"   // 送信待ち（失敗した場合は再送待ち）
+	WebhookStatusDelivered = "delivered" // 送信済み
+	WebhookStatusDead      = "dead"      // 再送の上限に達した（手動で再送できる）
+)
+
+// WebhookEndpoint Webhookの送信先
+type WebhookEndpoint struct {
+	ID        int64
+	Name      string
+	URL       string
+	Secret    string   // 署名の鍵
+	Format    string   // ペイロードの形式（json / slack）
+	Events    []string // 送信するイベント（空の場合は全て）
+	ReaderIDs []string // 送信するリーダー（空の場合は全て）
+	CardTypes []string // 送信するカード種別（空の場合は全て）
+	Enabled   bool
+	CreatedAt time.Time
+}
+
+// WebhookDelivery Webhookの送信
+type WebhookDelivery struct {
+	ID            int64
+	EndpointID    int64
+	EventID       string
+	EventType     string
+	Payload       string // イベントのJSON
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastStatus    int // 最後の送信のHTTPステータス（接続できなかった場合は0）
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   time.Time
+}
+
+// CreateWebhookEndpoint Webhookの送信先を登録
+func (l *Logger) CreateWebhookEndpoint(endpoint *WebhookEndpoint) error {
+	query := `INSERT INTO webhook_endpoints (name, url, secret, format, events, reader_ids, card_types, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := l.db.Exec(query,
+		endpoint.Name,
+		endpoint.URL,
+		endpoint.Secret,
+		endpoint.Format,
+		strings.Join(endpoint.Events, ","),
+		strings.Join(endpoint.ReaderIDs, ","),
+		strings.Join(endpoint.CardTypes, ","),
+		endpoint.Enabled,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook endpoint: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	endpoint.ID = id
+
+	return nil
+}
+
+// ListWebhookEndpoints Webhookの送信先の一覧を取得（enabledOnlyの場合は有効なもののみ）
+func (l *Logger) ListWebhookEndpoints(enabledOnly bool) ([]*WebhookEndpoint, error) {
+	query := `SELECT id, name, url, secret, format, events, reader_ids, card_types, enabled, created_at
+		FROM webhook_endpoints`
+	if enabledOnly {
+		query += " WHERE enabled = 1"
+	}
+	query += " ORDER BY id"
+
+	rows, err := l.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var endpoints []*WebhookEndpoint
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+// GetWebhookEndpoint Webhookの送信先を取得
+func (l *Logger) GetWebhookEndpoint(id int64) (*WebhookEndpoint, error) {
+	query := `SELECT id, name, url, secret, format, events, reader_ids, card_types, enabled, created_at
+		FROM webhook_endpoints WHERE id = ?`
+
+	endpoint, err := scanWebhookEndpoint(l.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook endpoint not found: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+// SetWebhookEndpointEnabled Webhookの送信先を有効・無効にする
+func (l *Logger) SetWebhookEndpointEnabled(id int64, enabled bool) error {
+	result, err := l.db.Exec(`UPDATE webhook_endpoints SET enabled = ? WHERE id = ?`, enabled, id)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("webhook endpoint not found: %d", id)
+	}
+
+	return nil
+}
+
+// DeleteWebhookEndpoint Webhookの送信先と送信履歴を削除
+func (l *Logger) DeleteWebhookEndpoint(id int64) error {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE endpoint_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM webhook_endpoints WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("webhook endpoint not found: %d", id)
+	}
+
+	return tx.Commit()
+}
+
+func scanWebhookEndpoint(row rowScanner) (*WebhookEndpoint, error) {
+	endpoint := &WebhookEndpoint{}
+	var createdAt string
+	var events, readerIDs, cardTypes sql.NullString
+
+	if err := row.Scan(&endpoint.ID, &endpoint.Name, &endpoint.URL, &endpoint.Secret, &endpoint.Format,
+		&events, &readerIDs, &cardTypes, &endpoint.Enabled, &createdAt); err != nil {
+		return nil, err
+	}
+
+	endpoint.Events = splitList(events.String)
+	endpoint.ReaderIDs = splitList(readerIDs.String)
+	endpoint.CardTypes = splitList(cardTypes.String)
+	endpoint.CreatedAt = parseTimestamp(createdAt)
+
+	return endpoint, nil
+}
+
+// EnqueueWebhookDeliveries Webhookの送信を追加（すぐに送信対象になる）
+func (l *Logger) EnqueueWebhookDeliveries(deliveries []*WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	tx, err := l.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(timestampLayout)
+	for _, d := range deliveries {
+		result, err := tx.Exec(`INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, status, next_attempt_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			d.EndpointID, d.EventID, d.EventType, d.Payload, WebhookStatusPending, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert webhook delivery: %w", err)
+		}
+
+		id, _ := result.LastInsertId()
+		d.ID = id
+		d.Status = WebhookStatusPending
+	}
+
+	return tx.Commit()
+}
+
+// DueWebhookDeliveries 送信時刻になった送信待ちを古い順に取得
+func (l *Logger) DueWebhookDeliveries(now time.Time, limit int) ([]*WebhookDelivery, error) {
+	return l.queryWebhookDeliveries(`SELECT id, endpoint_id, event_id, event_type, payload, status, attempts,
+		next_attempt_at, last_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`,
+		WebhookStatusPending, now.UTC().Format(timestampLayout), limit)
+}
+
+// ListWebhookDeliveries Webhookの送信を新しい順に取得（statusとendpointIDは空・0の場合は全て）
+func (l *Logger) ListWebhookDeliveries(status string, endpointID int64, limit int) ([]*WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT id, endpoint_id, event_id, event_type, payload, status, attempts,
+		next_attempt_at, last_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries WHERE 1=1`
+	var args []interface{}
+
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	if endpointID != 0 {
+		query += " AND endpoint_id = ?"
+		args = append(args, endpointID)
+	}
+
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	return l.queryWebhookDeliveries(query, args...)
+}
+
+func (l *Logger) queryWebhookDeliveries(query string, args ...interface{}) ([]*WebhookDelivery, error) {
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*WebhookDelivery
+	for rows.Next() {
+		d := &WebhookDelivery{}
+		var nextAttemptAt, createdAt string
+		var lastStatus sql.NullInt64
+		var lastError, deliveredAt sql.NullString
+
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&nextAttemptAt, &lastStatus, &lastError, &createdAt, &deliveredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		d.NextAttemptAt = parseTimestamp(nextAttemptAt)
+		d.LastStatus = int(lastStatus.Int64)
+		d.LastError = lastError.String
+		d.CreatedAt = parseTimestamp(createdAt)
+		if deliveredAt.Valid {
+			d.DeliveredAt = parseTimestamp(deliveredAt.String)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+// MarkWebhookDelivered Webhookを送信済みにする
+func (l *Logger) MarkWebhookDelivered(id int64, statusCode int) error {
+	_, err := l.db.Exec(`UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, last_status = ?,
+		last_error = NULL, delivered_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		WebhookStatusDelivered, statusCode, id)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// MarkWebhookFailed Webhookの送信の失敗を記録（nextがゼロの場合は再送しない）
+func (l *Logger) MarkWebhookFailed(id int64, statusCode int, errMsg string, next time.Time) error {
+	status := WebhookStatusPending
+	nextAttempt := next.UTC().Format(timestampLayout)
+	if next.IsZero() {
+		status = WebhookStatusDead
+		nextAttempt = time.Now().UTC().Format(timestampLayout)
+	}
+
+	_, err := l.db.Exec(`UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, last_status = ?,
+		last_error = ?, next_attempt_at = ? WHERE id = ?`,
+		status, statusCode, errMsg, nextAttempt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// RequeueWebhookDelivery 再送の上限に達したWebhookを再送する（回数は0から数え直す）
+func (l *Logger) RequeueWebhookDelivery(id int64) error {
+	result, err := l.db.Exec(`UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?
+		WHERE id = ? AND status = ?`,
+		WebhookStatusPending, time.Now().UTC().Format(timestampLayout), id, WebhookStatusDead)
+	if err != nil {
+		return fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("dead webhook delivery not found: %d", id)
+	}
+
+	return nil
+}
+
+// CountWebhookDeliveries 状態ごとのWebhookの送信の件数
+func (l *Logger) CountWebhookDeliveries() (map[string]int, error) {
+	rows, err := l.db.Query(`SELECT status, COUNT(*) FROM webhook_deliveries GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts[status] = count
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,323 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 書き込みキューが満杯の場合の動作
+const (
+	OverflowDrop  = "drop"  // ログは破棄する
+	OverflowBlock = "block" // ログは空くまで待つ
+)
+
+// errWriterClosed 書き込みキューが閉じられている（呼び出し元で直接書き込む）
+var errWriterClosed = errors.New("write queue closed")
+
+// errWriteQueueFull キューと退避先の両方が満杯で読み取り履歴を書き込めなかった
+var errWriteQueueFull = errors.New("write queue full")
+
+// WriterOptions 非同期書き込みの設定
+type WriterOptions struct {
+	QueueSize     int           // キューの上限（件数。0以下の場合は非同期にしない）
+	BatchSize     int           // 1トランザクションで書き込む最大件数
+	FlushInterval time.Duration // キューに溜まった行を書き込む間隔
+	Overflow      string        // キューが満杯の場合の動作（drop / block）
+	Logf          func(string)  // 書き込みの失敗・破棄した件数の通知
+}
+
+// WriterStats 非同期書き込みの状態
+type WriterStats struct {
+	Queued  int    // キューで待っている件数
+	Written uint64 // 書き込んだ件数
+	Dropped uint64 // キュー（読み取り履歴は退避先も）が満杯で破棄した件数
+	Failed  uint64 // 書き込みに失敗した件数
+}
+
+// writeOp キューに入れる1行のINSERT
+type writeOp struct {
+	query    string
+	args     []any
+	critical bool                      // キューが満杯の場合は退避先に追加する（退避先も満杯の場合のみ破棄する）
+	done     func(id int64, err error) // 書き込み後に別のゴルーチンで呼ぶ（nilの場合は呼ばない）
+}
+
+// asyncWriter INSERTをキューに溜め、まとめて1つのトランザクションで書き込む
+type asyncWriter struct {
+	db   *sql.DB
+	opts WriterOptions
+
+	mu     sync.RWMutex // closeとキューへの追加の排他（書き込みのゴルーチンでは使わない）
+	closed atomic.Bool
+
+	queue   chan *writeOp
+	spillMu sync.Mutex
+	spill   []*writeOp // キューが満杯の間に追加された読み取り履歴（QueueSize件まで。書き込みのゴルーチンが書き込む）
+	flushCh chan chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+
+	written atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+
+	reportedDrops uint64 // 最後に通知した破棄件数（書き込みのゴルーチンのみ使う）
+}
+
+// newAsyncWriter 書き込みのゴルーチンを開始
+func newAsyncWriter(db *sql.DB, opts WriterOptions) *asyncWriter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 256
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 200 * time.Millisecond
+	}
+	if opts.Overflow != OverflowBlock {
+		opts.Overflow = OverflowDrop
+	}
+	if opts.Logf == nil {
+		opts.Logf = func(string) {}
+	}
+
+	w := &asyncWriter{
+		db:      db,
+		opts:    opts,
+		queue:   make(chan *writeOp, opts.QueueSize),
+		flushCh: make(chan chan struct{}),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue キューに追加（閉じられている場合はerrWriterClosed）
+// キューが満杯の場合、criticalな行は待たずに退避先に追加する（カードの読み取りをディスクの書き込みで止めないため）
+// criticalでない行はOverflowBlockなら空くまで待ち、それ以外は破棄する
+func (w *asyncWriter) enqueue(op *writeOp) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed.Load() {
+		return errWriterClosed
+	}
+
+	if !op.critical && w.opts.Overflow == OverflowBlock {
+		w.queue <- op
+		return nil
+	}
+
+	select {
+	case w.queue <- op:
+		return nil
+	default:
+	}
+
+	if op.critical && w.spillOp(op) {
+		return nil
+	}
+	w.dropped.Add(1)
+	if op.done != nil {
+		go op.done(0, errWriteQueueFull)
+	}
+	return nil
+}
+
+// spillOp 退避先に追加（退避先も満杯の場合はfalse）
+func (w *asyncWriter) spillOp(op *writeOp) bool {
+	w.spillMu.Lock()
+	defer w.spillMu.Unlock()
+
+	if len(w.spill) >= cap(w.queue) {
+		return false
+	}
+	w.spill = append(w.spill, op)
+	return true
+}
+
+// takeSpill 退避先の行を全て取り出す
+func (w *asyncWriter) takeSpill() []*writeOp {
+	w.spillMu.Lock()
+	defer w.spillMu.Unlock()
+
+	ops := w.spill
+	w.spill = nil
+	return ops
+}
+
+// flush キューに溜まっている行を全て書き込むまで待つ
+func (w *asyncWriter) flush() {
+	done := make(chan struct{})
+	select {
+	case w.flushCh <- done:
+		<-done
+	case <-w.stopped:
+	}
+}
+
+// close 新しい行を受け付けないようにし、残りを全て書き込んでからゴルーチンを終了
+func (w *asyncWriter) close() {
+	w.mu.Lock()
+	if w.closed.Load() {
+		w.mu.Unlock()
+		<-w.stopped
+		return
+	}
+	w.closed.Store(true)
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.stopped
+}
+
+// stats 現在の状態
+func (w *asyncWriter) stats() WriterStats {
+	w.spillMu.Lock()
+	spilled := len(w.spill)
+	w.spillMu.Unlock()
+
+	return WriterStats{
+		Queued:  len(w.queue) + spilled,
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+	}
+}
+
+// run キューから取り出した行をBatchSize件ごと、またはFlushIntervalごとに書き込む
+func (w *asyncWriter) run() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*writeOp, 0, w.opts.BatchSize)
+	for {
+		select {
+		case op := <-w.queue:
+			batch = append(batch, op)
+			if len(batch) >= w.opts.BatchSize {
+				batch = w.write(batch)
+			}
+
+		case <-ticker.C:
+			batch = w.write(append(batch, w.takeSpill()...))
+			w.reportDrops()
+
+		case done := <-w.flushCh:
+			batch = w.drain(batch)
+			close(done)
+
+		case <-w.stop:
+			// closedにした後はキューに追加されないため、残りを書き込めば終了できる
+			w.drain(batch)
+			w.reportDrops()
+			return
+		}
+	}
+}
+
+// drain キューが空になるまで書き込み、退避先の行も書き込む
+func (w *asyncWriter) drain(batch []*writeOp) []*writeOp {
+	for {
+		select {
+		case op := <-w.queue:
+			batch = append(batch, op)
+			if len(batch) >= w.opts.BatchSize {
+				batch = w.write(batch)
+			}
+		default:
+			return w.write(append(batch, w.takeSpill()...))
+		}
+	}
+}
+
+// write batchを1つのトランザクションで書き込んでdoneを呼ぶ（空にしたbatchを返す）
+func (w *asyncWriter) write(batch []*writeOp) []*writeOp {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ids := make([]int64, len(batch))
+	errs := make([]error, len(batch))
+	if err := w.exec(batch, ids, errs); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		w.logf(fmt.Sprintf("Failed to write %d queued rows: %v", len(batch), err))
+	}
+
+	var failed uint64
+	for i, op := range batch {
+		if errs[i] != nil {
+			failed++
+		}
+		if op.done != nil {
+			// doneからログを出力してもキューへの追加で書き込みのゴルーチンが止まらないようにする
+			go op.done(ids[i], errs[i])
+		}
+	}
+	w.failed.Add(failed)
+	w.written.Add(uint64(len(batch)) - failed)
+
+	clear(batch)
+	return batch[:0]
+}
+
+// exec batchを実行してコミット（1行の失敗は他の行に影響させず、errsに記録する）
+func (w *asyncWriter) exec(batch []*writeOp, ids []int64, errs []error) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// 同じINSERTはトランザクション内で1回だけ準備する（コミット時に閉じられる）
+	stmts := make(map[string]*sql.Stmt)
+	for i, op := range batch {
+		stmt, ok := stmts[op.query]
+		if !ok {
+			stmt, err = tx.Prepare(op.query)
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to prepare statement: %w", err)
+			}
+			stmts[op.query] = stmt
+		}
+
+		result, err := stmt.Exec(op.args...)
+		if err != nil {
+			errs[i] = err
+			w.logf(fmt.Sprintf("Failed to write queued row: %v", err))
+			continue
+		}
+		ids[i], _ = result.LastInsertId()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// reportDrops 前回の通知から破棄した件数があれば通知
+func (w *asyncWriter) reportDrops() {
+	dropped := w.dropped.Load()
+	if dropped == w.reportedDrops {
+		return
+	}
+	w.logf(fmt.Sprintf("Write queue full, dropped %d rows (total %d)", dropped-w.reportedDrops, dropped))
+	w.reportedDrops = dropped
+}
+
+// logf 通知を出力（通知のログがキューに追加されても書き込みのゴルーチンが止まらないように、閉じるまでは別のゴルーチンで呼ぶ）
+func (w *asyncWriter) logf(msg string) {
+	if w.closed.Load() {
+		w.opts.Logf(msg)
+		return
+	}
+	go w.opts.Logf(msg)
+}
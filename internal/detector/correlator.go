@@ -0,0 +1,138 @@
+package detector
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"menkyo_go/internal/database"
+)
+
+// cardRead 端末ごとの直近のカード読み取り
+type cardRead struct {
+	historyID int64
+	cardID    string
+	at        time.Time
+}
+
+// pendingMeasurement カード読み取りより先に届いた測定結果
+type pendingMeasurement struct {
+	id int64
+	at time.Time
+}
+
+// Correlator 測定結果を同じ端末のカード読み取りと紐付けて記録する
+// 測定の前後window以内に読み取りがあれば、そのread_historyの行に紐付ける
+type Correlator struct {
+	logger *database.Logger
+	window time.Duration
+
+	mu       sync.Mutex
+	lastRead map[string]cardRead
+	pending  map[string][]pendingMeasurement
+}
+
+// NewCorrelator 新しいCorrelatorを作成
+func NewCorrelator(logger *database.Logger, window time.Duration) *Correlator {
+	return &Correlator{
+		logger:   logger,
+		window:   window,
+		lastRead: make(map[string]cardRead),
+		pending:  make(map[string][]pendingMeasurement),
+	}
+}
+
+// OnCardRead 記録済みの読み取り履歴を通知する
+// 未紐付けの測定結果がwindow以内にあれば紐付ける
+func (c *Correlator) OnCardRead(record *database.ReadHistoryRecord) error {
+	if record.ID == 0 || record.Status != "success" {
+		return nil
+	}
+
+	at := record.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastRead[record.ReaderID] = cardRead{
+		historyID: record.ID,
+		cardID:    record.CardID,
+		at:        at,
+	}
+
+	var remaining []pendingMeasurement
+	var linkErr error
+	for _, p := range c.pending[record.ReaderID] {
+		if !c.within(p.at, at) {
+			// 期限切れは未紐付けのまま破棄
+			if p.at.After(at) {
+				remaining = append(remaining, p)
+			}
+			continue
+		}
+		if err := c.logger.LinkAlcoholMeasurement(p.id, record.ID, record.CardID); err != nil {
+			linkErr = err
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(c.pending, record.ReaderID)
+	} else {
+		c.pending[record.ReaderID] = remaining
+	}
+
+	return linkErr
+}
+
+// OnMeasurement 測定結果を記録し、直近の読み取りがあれば紐付ける
+func (c *Correlator) OnMeasurement(readerID string, m *Measurement) (*database.AlcoholMeasurementRecord, error) {
+	record := &database.AlcoholMeasurementRecord{
+		ReaderID:   readerID,
+		Value:      m.Value,
+		Result:     m.Result,
+		DeviceTime: m.DeviceTime,
+		Raw:        m.Raw,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	read, ok := c.lastRead[readerID]
+	linked := ok && c.within(read.at, m.ReceivedAt)
+	if linked {
+		record.ReadHistoryID = read.historyID
+		record.CardID = read.cardID
+	}
+
+	if err := c.logger.LogAlcoholMeasurement(record); err != nil {
+		return nil, fmt.Errorf("failed to record measurement: %w", err)
+	}
+
+	if !linked {
+		// 期限切れの未紐付け測定を捨ててから追加
+		var pending []pendingMeasurement
+		for _, p := range c.pending[readerID] {
+			if c.within(p.at, m.ReceivedAt) {
+				pending = append(pending, p)
+			}
+		}
+		c.pending[readerID] = append(pending, pendingMeasurement{
+			id: record.ID,
+			at: m.ReceivedAt,
+		})
+	}
+
+	return record, nil
+}
+
+// within 2つの時刻の差がwindow以内か
+func (c *Correlator) within(a, b time.Time) bool {
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	return d <= c.window
+}
@@ -0,0 +1,119 @@
+package detector
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// 判定結果
+const (
+	ResultPass = "pass" // 不検出
+	ResultFail = "fail" // 検出
+)
+
+// ErrSkipLine 測定結果ではない行（起動メッセージ・空行など）
+var ErrSkipLine = errors.New("not a measurement line")
+
+// Measurement アルコール検知器の測定結果
+type Measurement struct {
+	Value      float64   // 呼気アルコール濃度 (mg/L)
+	Result     string    // 判定結果 (pass/fail)
+	DeviceTime time.Time // 検知器が出力した測定時刻（出力しない機種はゼロ値）
+	ReceivedAt time.Time // 行を受信した時刻
+	Raw        string    // 受信した行
+}
+
+// Detected アルコールが検出されたか
+func (m *Measurement) Detected() bool {
+	return m.Result == ResultFail
+}
+
+// Parser 検知器の出力行を解析する
+type Parser interface {
+	// Name フォーマット名
+	Name() string
+	// Parse 1行を解析する（測定結果でない行はErrSkipLineを返す）
+	Parse(line string) (*Measurement, error)
+}
+
+// Reader io.Readerから測定結果を読み取る
+// シリアルポート・pty・ファイルのいずれでも同じように扱える
+type Reader struct {
+	src    io.Reader
+	parser Parser
+	logger func(string)
+}
+
+// NewReader 新しいReaderを作成
+func NewReader(src io.Reader, parser Parser, logger func(string)) *Reader {
+	return &Reader{
+		src:    src,
+		parser: parser,
+		logger: logger,
+	}
+}
+
+// log ログを出力
+func (r *Reader) log(msg string) {
+	if r.logger != nil {
+		r.logger(msg)
+	}
+}
+
+// Run 入力を1行ずつ読み取り、測定結果ごとにコールバックを呼び出す
+// 入力が終了するまで戻らない（EOFの場合はnilを返す）
+func (r *Reader) Run(callback func(*Measurement, error)) error {
+	scanner := bufio.NewScanner(r.src)
+	scanner.Split(scanLines)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		m, err := r.parser.Parse(line)
+		if errors.Is(err, ErrSkipLine) {
+			r.log(fmt.Sprintf("Skipped line: %q", line))
+			continue
+		}
+		if err != nil {
+			callback(nil, fmt.Errorf("failed to parse line %q: %w", line, err))
+			continue
+		}
+
+		m.ReceivedAt = time.Now()
+		m.Raw = line
+		callback(m, nil)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read detector output: %w", err)
+	}
+
+	return nil
+}
+
+// scanLines CR・LF・CRLFのいずれの改行でも行を区切る
+// （検知器によってはCRのみを出力するため、CRで即座に区切る）
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		// CRLFの場合はCRとLFの間が空行になるが、Run側で読み飛ばす
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
@@ -0,0 +1,27 @@
+package detector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Open 検知器の出力元を開く
+// Windowsの"COM3"のようなポート名はデバイスパスに変換する。
+// ボーレート等のポート設定は事前にデバイスマネージャー（またはmodeコマンド）で行うこと。
+// テスト時はptyや記録済みファイルのパスをそのまま指定できる。
+func Open(path string) (io.ReadCloser, error) {
+	devicePath := path
+	if runtime.GOOS == "windows" && strings.HasPrefix(strings.ToUpper(path), "COM") {
+		devicePath = `\\.\` + path
+	}
+
+	f, err := os.OpenFile(devicePath, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open detector %s: %w", path, err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,239 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// フォーマット名
+const (
+	FormatAuto     = "auto"  // 以下のフォーマットを順に試す
+	FormatKeyValue = "kv"    // 例: ALC=0.00 UNIT=mg/L RESULT=OK
+	FormatCSV      = "csv"   // 例: 2025/10/26,08:15:30,0.000,OK
+	FormatValue    = "value" // 例: 0.00mg/L
+)
+
+// ParserByName フォーマット名からParserを取得
+func ParserByName(name string) (Parser, error) {
+	switch strings.ToLower(name) {
+	case "", FormatAuto:
+		return autoParser{parsers: []Parser{keyValueParser{}, csvParser{}, valueParser{}}}, nil
+	case FormatKeyValue:
+		return keyValueParser{}, nil
+	case FormatCSV:
+		return csvParser{}, nil
+	case FormatValue:
+		return valueParser{}, nil
+	default:
+		return nil, fmt.Errorf("unknown detector format: %s", name)
+	}
+}
+
+// autoParser 複数のフォーマットを順に試す
+type autoParser struct {
+	parsers []Parser
+}
+
+func (p autoParser) Name() string { return FormatAuto }
+
+// 形式は一致したが内容が不正な場合はそのエラーを返し、どの形式にも一致しない場合のみErrSkipLineを返す
+func (p autoParser) Parse(line string) (*Measurement, error) {
+	var parseErr error
+	for _, parser := range p.parsers {
+		m, err := parser.Parse(line)
+		if err == nil {
+			return m, nil
+		}
+		if !errors.Is(err, ErrSkipLine) && parseErr == nil {
+			parseErr = fmt.Errorf("%s: %w", parser.Name(), err)
+		}
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return nil, ErrSkipLine
+}
+
+// keyValueParser KEY=VALUE形式（区切りは空白・カンマ・セミコロン、KEY:VALUEも可）
+type keyValueParser struct{}
+
+func (keyValueParser) Name() string { return FormatKeyValue }
+
+func (keyValueParser) Parse(line string) (*Measurement, error) {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ',' || r == ';'
+	})
+
+	values := make(map[string]string)
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			key, value, ok = strings.Cut(field, ":")
+		}
+		if !ok {
+			continue
+		}
+		values[strings.ToUpper(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	raw := firstOf(values, "ALC", "BRAC", "VALUE", "CONC")
+	if raw == "" {
+		return nil, ErrSkipLine
+	}
+
+	if unit := firstOf(values, "UNIT"); unit != "" && !isMgPerL(unit) {
+		return nil, fmt.Errorf("unsupported unit: %s", unit)
+	}
+
+	value, err := parseValue(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Measurement{Value: value}
+	m.Result, err = parseResult(firstOf(values, "RESULT", "JUDGE"), value)
+	if err != nil {
+		return nil, err
+	}
+
+	if date := firstOf(values, "DATE"); date != "" {
+		m.DeviceTime = parseDeviceTime(date, firstOf(values, "TIME"))
+	}
+
+	return m, nil
+}
+
+// csvParser 日付,時刻,濃度[,判定] 形式
+type csvParser struct{}
+
+func (csvParser) Name() string { return FormatCSV }
+
+func (csvParser) Parse(line string) (*Measurement, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) < 3 {
+		return nil, ErrSkipLine
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	deviceTime := parseDeviceTime(fields[0], fields[1])
+	if deviceTime.IsZero() {
+		return nil, ErrSkipLine
+	}
+
+	value, err := parseValue(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	result := ""
+	if len(fields) >= 4 {
+		result = fields[3]
+	}
+
+	m := &Measurement{Value: value, DeviceTime: deviceTime}
+	m.Result, err = parseResult(result, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// valueParser 濃度のみの形式
+type valueParser struct{}
+
+var valueLinePattern = regexp.MustCompile(`(?i)^([0-9]+\.[0-9]+)\s*(mg/l)?\s*(ok|ng|pass|fail)?$`)
+
+func (valueParser) Name() string { return FormatValue }
+
+func (valueParser) Parse(line string) (*Measurement, error) {
+	match := valueLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return nil, ErrSkipLine
+	}
+
+	value, err := parseValue(match[1])
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Measurement{Value: value}
+	m.Result, err = parseResult(match[3], value)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// firstOf 最初に見つかったキーの値を返す
+func firstOf(values map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := values[key]; ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// isMgPerL 単位がmg/Lか
+func isMgPerL(unit string) bool {
+	return strings.EqualFold(strings.ReplaceAll(unit, " ", ""), "mg/l")
+}
+
+// parseValue 濃度を解析（末尾のmg/Lは無視）
+func parseValue(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) > 4 && isMgPerL(raw[len(raw)-4:]) {
+		raw = strings.TrimSpace(raw[:len(raw)-4])
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid concentration %q: %w", raw, err)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("negative concentration: %v", value)
+	}
+
+	return value, nil
+}
+
+// parseResult 判定結果を解析（機器が出力しない場合は濃度0以外を検出とする）
+func parseResult(raw string, value float64) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(raw)) {
+	case "":
+		if value > 0 {
+			return ResultFail, nil
+		}
+		return ResultPass, nil
+	case "OK", "PASS":
+		return ResultPass, nil
+	case "NG", "FAIL":
+		return ResultFail, nil
+	default:
+		return "", fmt.Errorf("unknown result: %s", raw)
+	}
+}
+
+// parseDeviceTime 検知器の日付・時刻を解析（解析できない場合はゼロ値）
+func parseDeviceTime(date, clock string) time.Time {
+	date = strings.ReplaceAll(date, "-", "/")
+	if clock == "" {
+		clock = "00:00:00"
+	}
+
+	for _, layout := range []string{"2006/01/02 15:04:05", "2006/01/02 15:04"} {
+		if t, err := time.ParseInLocation(layout, date+" "+clock, time.Local); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,83 @@
+package detector
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsers(t *testing.T) {
+	deviceTime := time.Date(2025, 10, 26, 8, 15, 30, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		format     string
+		line       string
+		value      float64
+		result     string
+		deviceTime time.Time
+		skip       bool
+		wantErr    bool
+	}{
+		{name: "kv ok", format: FormatKeyValue, line: "ALC=0.00 UNIT=mg/L RESULT=OK", value: 0, result: ResultPass},
+		{name: "kv colon ng", format: FormatKeyValue, line: "BRAC:0.15,JUDGE:NG", value: 0.15, result: ResultFail},
+		{name: "kv without result", format: FormatKeyValue, line: "ALC=0.10", value: 0.10, result: ResultFail},
+		{name: "kv device time", format: FormatKeyValue, line: "ALC=0.00 DATE=2025-10-26 TIME=08:15:30", value: 0, result: ResultPass, deviceTime: deviceTime},
+		{name: "kv unsupported unit", format: FormatKeyValue, line: "ALC=0.10 UNIT=%", wantErr: true},
+		{name: "kv unknown result", format: FormatKeyValue, line: "ALC=0.00 RESULT=MAYBE", wantErr: true},
+		{name: "kv negative", format: FormatKeyValue, line: "ALC=-0.01", wantErr: true},
+		{name: "kv status line", format: FormatKeyValue, line: "STATUS=READY", skip: true},
+		{name: "csv ok", format: FormatCSV, line: "2025/10/26,08:15:30,0.000,OK", value: 0, result: ResultPass, deviceTime: deviceTime},
+		{name: "csv without result", format: FormatCSV, line: "2025/10/26, 08:15, 0.120", value: 0.12, result: ResultFail, deviceTime: time.Date(2025, 10, 26, 8, 15, 0, 0, time.Local)},
+		{name: "csv bad value", format: FormatCSV, line: "2025/10/26,08:15:30,abc", wantErr: true},
+		{name: "csv not a date", format: FormatCSV, line: "a,b,c", skip: true},
+		{name: "csv too few fields", format: FormatCSV, line: "2025/10/26,08:15:30", skip: true},
+		{name: "value ok", format: FormatValue, line: "0.00mg/L", value: 0, result: ResultPass},
+		{name: "value ng", format: FormatValue, line: "0.25 mg/L NG", value: 0.25, result: ResultFail},
+		{name: "value explicit pass", format: FormatValue, line: "0.10 OK", value: 0.10, result: ResultPass},
+		{name: "value text", format: FormatValue, line: "READY", skip: true},
+		{name: "auto kv", format: FormatAuto, line: "ALC=0.00 RESULT=OK", value: 0, result: ResultPass},
+		{name: "auto csv", format: FormatAuto, line: "2025/10/26,08:15:30,0.05", value: 0.05, result: ResultFail, deviceTime: deviceTime},
+		{name: "auto value", format: "", line: "0.00mg/L", value: 0, result: ResultPass},
+		{name: "auto invalid kv", format: FormatAuto, line: "ALC=abc", wantErr: true},
+		{name: "auto no match", format: FormatAuto, line: "hello", skip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := ParserByName(tt.format)
+			if err != nil {
+				t.Fatalf("ParserByName(%q): %v", tt.format, err)
+			}
+
+			m, err := parser.Parse(tt.line)
+			switch {
+			case tt.skip:
+				if !errors.Is(err, ErrSkipLine) {
+					t.Fatalf("Parse(%q) error = %v, want ErrSkipLine", tt.line, err)
+				}
+				return
+			case tt.wantErr:
+				if err == nil || errors.Is(err, ErrSkipLine) {
+					t.Fatalf("Parse(%q) error = %v, want parse error", tt.line, err)
+				}
+				return
+			case err != nil:
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+
+			if m.Value != tt.value || m.Result != tt.result {
+				t.Errorf("Parse(%q) = %v/%s, want %v/%s", tt.line, m.Value, m.Result, tt.value, tt.result)
+			}
+			if !m.DeviceTime.Equal(tt.deviceTime) {
+				t.Errorf("Parse(%q) DeviceTime = %v, want %v", tt.line, m.DeviceTime, tt.deviceTime)
+			}
+		})
+	}
+}
+
+func TestParserByNameUnknown(t *testing.T) {
+	if _, err := ParserByName("xml"); err == nil {
+		t.Fatal("ParserByName(\"xml\") succeeded, want error")
+	}
+}
@@ -0,0 +1,229 @@
+package dispatch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/nfc"
+)
+
+const (
+	resendInterval = time.Minute      // 未送信の割り当てを再送する間隔
+	resendMinAge   = 30 * time.Second // 記録直後の割り当ては読み取り処理側で送信中のため再送しない
+	resendBatch    = 50               // 1回に再送する件数
+)
+
+// licenseTap 車検証待ちの免許証タッチ
+type licenseTap struct {
+	record *database.ReadHistoryRecord
+	at     time.Time
+}
+
+// Check 登録済みの免許証と車両で割り当てできるか確認する（不可の場合はエラー）
+type Check func(*database.RegisteredLicense, *database.RegisteredVehicle) error
+
+// Pairer 免許証タッチに続く車検証タッチを運転者と車両の割り当てにする
+// 同じ端末でwindow以内に続いたタッチだけを組にする
+type Pairer struct {
+	logger *database.Logger
+	window time.Duration
+	check  Check
+	sink   func(*database.AssignmentRecord) error
+	logf   func(string)
+
+	mu      sync.Mutex
+	pending map[string]licenseTap
+}
+
+// NewPairer 新しいPairerを作成
+// checkは有効期限以外の確認（免許の種類など）を行う（nilの場合は確認しない）。
+// sinkは成立した割り当てをバックエンドに送信する（nilの場合はローカル記録のみ）。
+func NewPairer(logger *database.Logger, window time.Duration, check Check, sink func(*database.AssignmentRecord) error, logf func(string)) *Pairer {
+	return &Pairer{
+		logger:  logger,
+		window:  window,
+		check:   check,
+		sink:    sink,
+		logf:    logf,
+		pending: make(map[string]licenseTap),
+	}
+}
+
+// log ログを出力
+func (p *Pairer) log(msg string) {
+	if p.logf != nil {
+		p.logf(msg)
+	}
+}
+
+// OnCardRead 記録済みの読み取り履歴を通知する
+// 割り当てが成立または却下された場合はその記録を返し、それ以外はnilを返す
+func (p *Pairer) OnCardRead(record *database.ReadHistoryRecord) (*database.AssignmentRecord, error) {
+	if record.Status != "success" {
+		return nil, nil
+	}
+
+	at := record.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch record.CardType {
+	case nfc.CardTypeDriverLicense:
+		p.pending[record.ReaderID] = licenseTap{record: record, at: at}
+		p.log(fmt.Sprintf("License tapped, waiting for vehicle card (%v)", p.window))
+		return nil, nil
+
+	case nfc.CardTypeCarInspection:
+		tap, ok := p.pending[record.ReaderID]
+		delete(p.pending, record.ReaderID)
+		if !ok || at.Sub(tap.at) > p.window {
+			p.log("Vehicle card tapped without a preceding license tap, ignored")
+			return nil, nil
+		}
+		return p.assign(tap.record, record, at)
+
+	default:
+		return nil, nil
+	}
+}
+
+// assign 免許証と車検証を検証して割り当てを記録する
+func (p *Pairer) assign(licenseRecord, vehicleRecord *database.ReadHistoryRecord, at time.Time) (*database.AssignmentRecord, error) {
+	assignment := &database.AssignmentRecord{
+		Timestamp:     at,
+		ReaderID:      vehicleRecord.ReaderID,
+		LicenseCardID: licenseRecord.CardID,
+		VehicleCardID: vehicleRecord.CardID,
+		Status:        database.AssignmentStatusAssigned,
+	}
+
+	if err := p.validate(licenseRecord, vehicleRecord, assignment, at); err != nil {
+		assignment.Status = database.AssignmentStatusRejected
+		assignment.Reason = err.Error()
+		p.log(fmt.Sprintf("Assignment rejected: %s", assignment.Reason))
+	}
+
+	if err := p.logger.LogAssignment(assignment); err != nil {
+		return nil, fmt.Errorf("failed to record assignment: %w", err)
+	}
+
+	if assignment.Status != database.AssignmentStatusAssigned || p.sink == nil {
+		return assignment, nil
+	}
+
+	if err := p.send(assignment); err != nil {
+		return assignment, err
+	}
+
+	return assignment, nil
+}
+
+// send 割り当てをバックエンドに送信して送信済みにする
+func (p *Pairer) send(assignment *database.AssignmentRecord) error {
+	if err := p.sink(assignment); err != nil {
+		return fmt.Errorf("failed to send assignment: %w", err)
+	}
+
+	assignment.Synced = true
+	return p.logger.MarkAssignmentSynced(assignment.ID)
+}
+
+// Run 送信に失敗した割り当てを定期的に再送する（stopが閉じられるまで）
+func (p *Pairer) Run(stop <-chan struct{}) {
+	if p.sink == nil {
+		return
+	}
+
+	ticker := time.NewTicker(resendInterval)
+	defer ticker.Stop()
+
+	var lastErr string
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		sent, err := p.ResendPending()
+		if err != nil {
+			// 同じエラーが続く場合は記録しない
+			if msg := err.Error(); msg != lastErr {
+				p.log(msg)
+				lastErr = msg
+			}
+		} else if lastErr != "" || sent > 0 {
+			p.log(fmt.Sprintf("Resent %d pending assignments", sent))
+			lastErr = ""
+		}
+	}
+}
+
+// ResendPending 未送信の割り当てを古い順に再送し、送信した件数を返す
+func (p *Pairer) ResendPending() (int, error) {
+	pending, err := p.logger.GetPendingAssignments(resendBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, assignment := range pending {
+		if time.Since(assignment.Timestamp) < resendMinAge {
+			continue
+		}
+		if err := p.send(assignment); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// validate 有効期限と免許の種類を確認する（割り当てに運転者・車両のIDを設定する）
+func (p *Pairer) validate(licenseRecord, vehicleRecord *database.ReadHistoryRecord, assignment *database.AssignmentRecord, at time.Time) error {
+	commonData, err := nfc.ParseCommonData(licenseRecord.ExpiryDate)
+	if err != nil {
+		return fmt.Errorf("license expiry unreadable: %w", err)
+	}
+	if commonData.Expired(at) {
+		return fmt.Errorf("license expired on %s", commonData.ExpiryDate.Format("2006-01-02"))
+	}
+
+	license, err := p.logger.GetRegisteredLicense(licenseRecord.CardID)
+	if err != nil {
+		return err
+	}
+	if license == nil {
+		return fmt.Errorf("license not registered")
+	}
+	assignment.DriverID = license.DriverID
+
+	vehicle, err := p.logger.GetRegisteredVehicle(vehicleRecord.CardID)
+	if err != nil {
+		return err
+	}
+	if vehicle == nil {
+		return fmt.Errorf("vehicle not registered")
+	}
+	assignment.VehicleID = vehicle.VehicleID
+
+	if vehicle.InspectionExpiry.IsZero() {
+		return fmt.Errorf("vehicle inspection expiry unknown")
+	}
+	if at.After(vehicle.InspectionExpiry.AddDate(0, 0, 1)) {
+		return fmt.Errorf("vehicle inspection expired on %s", vehicle.InspectionExpiry.Format("2006-01-02"))
+	}
+
+	if p.check != nil {
+		return p.check(license, vehicle)
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package eligibility
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 免許の種類
+const (
+	ClassOrdinary     = "普通"
+	ClassSemiMedium   = "準中型"
+	ClassMedium       = "中型"
+	ClassLarge        = "大型"
+	ClassLargeSpecial = "大型特殊"
+	ClassSmallSpecial = "小型特殊"
+	ClassTowing       = "牽引"
+)
+
+// 免許の条件
+const (
+	ConditionSemiMedium5t = "5t" // 準中型で運転できる準中型車は準中型車(5t)に限る（2007/6/2〜2017/3/11に普通免許を取得）
+	ConditionMedium8t     = "8t" // 中型で運転できる中型車は中型車(8t)に限る（2007/6/1以前に普通免許を取得）
+	ConditionATOnly       = "AT" // AT車に限る
+)
+
+// limits 免許で運転できる車両の上限（いずれも未満、定員は以下）
+type limits struct {
+	grossWeightKg int
+	maxLoadKg     int
+	capacity      int
+}
+
+// 上限なしを表す値
+const unlimited = 1 << 30
+
+// classLimits 免許の種類ごとの上限（2017年3月12日以降の区分）
+var classLimits = map[string]limits{
+	ClassOrdinary:   {3500, 2000, 10},
+	ClassSemiMedium: {7500, 4500, 10},
+	ClassMedium:     {11000, 6500, 29},
+	ClassLarge:      {unlimited, unlimited, unlimited},
+}
+
+// conditionLimits 条件付き免許の上限（法改正前に取得した免許からの移行分）
+var conditionLimits = map[string]map[string]limits{
+	ClassSemiMedium: {ConditionSemiMedium5t: {5000, 3000, 10}},
+	ClassMedium:     {ConditionMedium8t: {8000, 5000, 10}},
+}
+
+// classOrder 車両区分の小さい順
+var classOrder = []string{ClassOrdinary, ClassSemiMedium, ClassMedium, ClassLarge}
+
+// License 運転者の免許
+type License struct {
+	Classes    []string // 免許の種類（「大型二種」などの第二種免許も可）
+	Conditions []string // 免許の条件（5t/8t/AT）
+}
+
+// Vehicle 車両
+// 車検証の車両総重量・最大積載量・乗車定員がわかる場合はそれで判定し、
+// わからない場合はClassで判定する
+type Vehicle struct {
+	ID            string
+	Class         string // 車両区分（普通/準中型/中型/大型/大型特殊/小型特殊）
+	GrossWeightKg int    // 車両総重量
+	MaxLoadKg     int    // 最大積載量
+	Capacity      int    // 乗車定員
+	Towing        bool   // 牽引免許が必要な被牽引車を牽引するか
+	Manual        bool   // MT車か
+}
+
+// Decision 判定結果
+type Decision struct {
+	Allowed  bool
+	Required string // 必要な免許の種類
+	Reason   string // 不可の理由
+}
+
+// Check 運転者が車両を運転できるか判定する
+func Check(license License, vehicle Vehicle) Decision {
+	classes := normalizeClasses(license.Classes)
+	if len(classes) == 0 {
+		return deny("", "license classes unknown")
+	}
+
+	if vehicle.Manual && hasCondition(license.Conditions, ConditionATOnly) {
+		return deny("", "license is limited to automatic transmission")
+	}
+
+	if vehicle.Towing && !contains(classes, ClassTowing) {
+		return deny(ClassTowing, "towing requires 牽引 license")
+	}
+
+	// 特殊自動車は重量区分とは別に判定
+	switch vehicle.Class {
+	case ClassLargeSpecial:
+		if contains(classes, ClassLargeSpecial) {
+			return allow(ClassLargeSpecial)
+		}
+		return deny(ClassLargeSpecial, "大型特殊 license required")
+	case ClassSmallSpecial:
+		return allow(ClassSmallSpecial)
+	}
+
+	required, err := requiredClass(vehicle)
+	if err != nil {
+		return deny("", err.Error())
+	}
+
+	hasSpec := vehicle.GrossWeightKg > 0 || vehicle.MaxLoadKg > 0 || vehicle.Capacity > 0
+	var reasons []string
+	for _, class := range classes {
+		l, condition, ok := licenseLimits(class, license.Conditions)
+		if !ok {
+			continue
+		}
+
+		if reason := exceeds(l, vehicle); hasSpec && reason != "" {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", class, reason))
+			continue
+		}
+
+		// 車両総重量が不明な場合は区分でも確認（条件付き免許は上限を確認できないため不可）
+		if vehicle.GrossWeightKg == 0 && !covers(l, classLimits[required]) {
+			if condition != "" && covers(classLimits[class], classLimits[required]) {
+				reasons = append(reasons, fmt.Sprintf("%s license is limited to %s and vehicle gross weight is unknown", class, condition))
+			}
+			continue
+		}
+
+		return allow(required)
+	}
+
+	if len(reasons) == 0 {
+		return deny(required, fmt.Sprintf("license %v does not cover %s", classes, required))
+	}
+	return deny(required, strings.Join(reasons, "; "))
+}
+
+// requiredClass 車両の運転に必要な免許の種類
+// 車両総重量が不明な場合は、わかっている項目による区分と車両区分の大きい方とする
+func requiredClass(vehicle Vehicle) (string, error) {
+	_, hasClass := classLimits[vehicle.Class]
+	if vehicle.GrossWeightKg == 0 && vehicle.MaxLoadKg == 0 && vehicle.Capacity == 0 {
+		if hasClass {
+			return vehicle.Class, nil
+		}
+		return "", fmt.Errorf("vehicle class and specification unknown")
+	}
+
+	required := ClassLarge
+	for _, class := range classOrder {
+		if exceeds(classLimits[class], vehicle) == "" {
+			required = class
+			break
+		}
+	}
+
+	if vehicle.GrossWeightKg == 0 && hasClass && classRank(vehicle.Class) > classRank(required) {
+		required = vehicle.Class
+	}
+
+	return required, nil
+}
+
+// classRank 車両区分の順位
+func classRank(class string) int {
+	for i, c := range classOrder {
+		if c == class {
+			return i
+		}
+	}
+	return -1
+}
+
+// licenseLimits 免許の種類と条件から上限を取得（条件が付いている場合はその条件も返す）
+func licenseLimits(class string, conditions []string) (limits, string, bool) {
+	for _, condition := range conditions {
+		if l, ok := conditionLimits[class][condition]; ok {
+			return l, condition, true
+		}
+	}
+	l, ok := classLimits[class]
+	return l, "", ok
+}
+
+// exceeds 車両が上限を超えている場合にその理由を返す
+func exceeds(l limits, vehicle Vehicle) string {
+	switch {
+	case vehicle.GrossWeightKg >= l.grossWeightKg:
+		return fmt.Sprintf("gross weight %dkg exceeds limit", vehicle.GrossWeightKg)
+	case vehicle.MaxLoadKg >= l.maxLoadKg:
+		return fmt.Sprintf("max load %dkg exceeds limit", vehicle.MaxLoadKg)
+	case vehicle.Capacity > l.capacity:
+		return fmt.Sprintf("capacity %d exceeds limit", vehicle.Capacity)
+	}
+	return ""
+}
+
+// covers 上限aが上限bを包含するか
+func covers(a, b limits) bool {
+	return a.grossWeightKg >= b.grossWeightKg && a.maxLoadKg >= b.maxLoadKg && a.capacity >= b.capacity
+}
+
+// normalizeClasses 第二種免許を対応する第一種の区分として扱う
+func normalizeClasses(classes []string) []string {
+	var normalized []string
+	for _, class := range classes {
+		class = strings.TrimSpace(class)
+		class = strings.TrimSuffix(class, "二種")
+		class = strings.TrimSuffix(class, "第一種")
+		if class != "" {
+			normalized = append(normalized, class)
+		}
+	}
+	return normalized
+}
+
+func hasCondition(conditions []string, condition string) bool {
+	return contains(conditions, condition)
+}
+
+func contains(items []string, item string) bool {
+	for _, v := range items {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+func allow(required string) Decision {
+	return Decision{Allowed: true, Required: required}
+}
+
+func deny(required, reason string) Decision {
+	return Decision{Allowed: false, Required: required, Reason: reason}
+}
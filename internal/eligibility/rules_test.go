@@ -0,0 +1,131 @@
+package eligibility
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		license  License
+		vehicle  Vehicle
+		allowed  bool
+		required string
+	}{
+		{
+			name:     "ordinary within limits",
+			license:  License{Classes: []string{ClassOrdinary}},
+			vehicle:  Vehicle{GrossWeightKg: 3000, MaxLoadKg: 1500, Capacity: 2},
+			allowed:  true,
+			required: ClassOrdinary,
+		},
+		{
+			name:     "ordinary at gross weight limit",
+			license:  License{Classes: []string{ClassOrdinary}},
+			vehicle:  Vehicle{GrossWeightKg: 3500, MaxLoadKg: 1500, Capacity: 2},
+			required: ClassSemiMedium,
+		},
+		{
+			name:     "ordinary over capacity",
+			license:  License{Classes: []string{ClassOrdinary}},
+			vehicle:  Vehicle{GrossWeightKg: 3000, MaxLoadKg: 500, Capacity: 11},
+			required: ClassMedium,
+		},
+		{
+			name:     "semi-medium 5t within condition",
+			license:  License{Classes: []string{ClassSemiMedium}, Conditions: []string{ConditionSemiMedium5t}},
+			vehicle:  Vehicle{GrossWeightKg: 4500, MaxLoadKg: 2500, Capacity: 3},
+			allowed:  true,
+			required: ClassSemiMedium,
+		},
+		{
+			name:     "semi-medium 5t over condition",
+			license:  License{Classes: []string{ClassSemiMedium}, Conditions: []string{ConditionSemiMedium5t}},
+			vehicle:  Vehicle{GrossWeightKg: 6000, MaxLoadKg: 3500, Capacity: 3},
+			required: ClassSemiMedium,
+		},
+		{
+			name:     "semi-medium 5t with unknown gross weight",
+			license:  License{Classes: []string{ClassSemiMedium}, Conditions: []string{ConditionSemiMedium5t}},
+			vehicle:  Vehicle{Class: ClassOrdinary, MaxLoadKg: 2500},
+			required: ClassSemiMedium,
+		},
+		{
+			name:     "medium 8t over condition",
+			license:  License{Classes: []string{ClassMedium}, Conditions: []string{ConditionMedium8t}},
+			vehicle:  Vehicle{GrossWeightKg: 9000, MaxLoadKg: 4000, Capacity: 3},
+			required: ClassMedium,
+		},
+		{
+			name:     "medium 8t with class only",
+			license:  License{Classes: []string{ClassMedium}, Conditions: []string{ConditionMedium8t}},
+			vehicle:  Vehicle{Class: ClassMedium},
+			required: ClassMedium,
+		},
+		{
+			name:     "medium without condition",
+			license:  License{Classes: []string{ClassMedium}},
+			vehicle:  Vehicle{GrossWeightKg: 10000, MaxLoadKg: 6000, Capacity: 3},
+			allowed:  true,
+			required: ClassMedium,
+		},
+		{
+			name:     "medium covers semi-medium class",
+			license:  License{Classes: []string{ClassMedium}},
+			vehicle:  Vehicle{Class: ClassSemiMedium},
+			allowed:  true,
+			required: ClassSemiMedium,
+		},
+		{
+			name:     "second-class large",
+			license:  License{Classes: []string{"大型二種"}},
+			vehicle:  Vehicle{GrossWeightKg: 20000, MaxLoadKg: 10000, Capacity: 3},
+			allowed:  true,
+			required: ClassLarge,
+		},
+		{
+			name:    "manual with AT condition",
+			license: License{Classes: []string{ClassLarge}, Conditions: []string{ConditionATOnly}},
+			vehicle: Vehicle{Class: ClassOrdinary, Manual: true},
+		},
+		{
+			name:     "towing without license",
+			license:  License{Classes: []string{ClassLarge}},
+			vehicle:  Vehicle{Class: ClassLarge, Towing: true},
+			required: ClassTowing,
+		},
+		{
+			name:     "large special without license",
+			license:  License{Classes: []string{ClassLarge}},
+			vehicle:  Vehicle{Class: ClassLargeSpecial},
+			required: ClassLargeSpecial,
+		},
+		{
+			name:     "small special",
+			license:  License{Classes: []string{ClassOrdinary}},
+			vehicle:  Vehicle{Class: ClassSmallSpecial},
+			allowed:  true,
+			required: ClassSmallSpecial,
+		},
+		{
+			name:    "no license classes",
+			license: License{},
+			vehicle: Vehicle{Class: ClassOrdinary},
+		},
+		{
+			name:    "vehicle unknown",
+			license: License{Classes: []string{ClassLarge}},
+			vehicle: Vehicle{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Check(tt.license, tt.vehicle)
+			if got.Allowed != tt.allowed || got.Required != tt.required {
+				t.Errorf("Check() = %+v, want allowed=%v required=%q", got, tt.allowed, tt.required)
+			}
+			if !got.Allowed && got.Reason == "" {
+				t.Error("Check() denied without reason")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package eligibility
+
+import (
+	"fmt"
+
+	"menkyo_go/internal/database"
+
+	dbpb "github.com/yhonda-ohishi/db_service/src/proto"
+)
+
+// CarLookup db_serviceの車両情報を取得する（license.Clientが実装）
+type CarLookup interface {
+	GetCar(id string) (*dbpb.Db_Cars, error)
+}
+
+// NewDispatchStep 配車時に免許の種類を確認するリーダーのパイプラインステップを作成
+// 運転できない場合は理由をログに出力してエラーを返す（配車は却下される）。
+// carsがnilでない場合、車検証の記載事項が不足していればdb_serviceの車両情報で補う。
+func NewDispatchStep(cars CarLookup, logf func(string)) func(*database.RegisteredLicense, *database.RegisteredVehicle) error {
+	log := func(msg string) {
+		if logf != nil {
+			logf(msg)
+		}
+	}
+
+	return func(license *database.RegisteredLicense, registered *database.RegisteredVehicle) error {
+		vehicle := VehicleFromRegistered(registered)
+
+		if cars != nil && vehicle.GrossWeightKg == 0 && vehicle.MaxLoadKg == 0 && vehicle.Capacity == 0 {
+			car, err := cars.GetCar(registered.VehicleID)
+			if err != nil {
+				log(fmt.Sprintf("Failed to get car %s from db_service: %v", registered.VehicleID, err))
+			} else if car != nil {
+				vehicle = vehicle.merge(VehicleFromCar(car))
+			}
+		}
+
+		decision := Check(LicenseFromRegistered(license), vehicle)
+		if !decision.Allowed {
+			log(fmt.Sprintf("Driver %d may not operate vehicle %s: %s", license.DriverID, vehicle.ID, decision.Reason))
+			return fmt.Errorf("not eligible: %s", decision.Reason)
+		}
+
+		log(fmt.Sprintf("Driver %d may operate vehicle %s (requires %s)", license.DriverID, vehicle.ID, decision.Required))
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package eligibility
+
+import (
+	"strings"
+
+	"menkyo_go/internal/database"
+
+	dbpb "github.com/yhonda-ohishi/db_service/src/proto"
+)
+
+// LicenseFromRegistered 登録済み免許証から判定用の免許を作成
+func LicenseFromRegistered(license *database.RegisteredLicense) License {
+	return License{
+		Classes:    license.Classes,
+		Conditions: license.Conditions,
+	}
+}
+
+// VehicleFromRegistered 登録済み車検証カード（車検証の記載事項）から判定用の車両を作成
+func VehicleFromRegistered(vehicle *database.RegisteredVehicle) Vehicle {
+	return Vehicle{
+		ID:            vehicle.VehicleID,
+		Class:         vehicle.VehicleClass,
+		GrossWeightKg: vehicle.GrossWeightKg,
+		MaxLoadKg:     vehicle.MaxLoadKg,
+		Capacity:      vehicle.Capacity,
+		Towing:        vehicle.Towing,
+		Manual:        vehicle.Manual,
+	}
+}
+
+// VehicleFromCar db_serviceの車両情報から判定用の車両を作成
+// sekisaiは最大積載量（kg）、shashuが免許の区分名と一致する場合は車両区分として扱う
+func VehicleFromCar(car *dbpb.Db_Cars) Vehicle {
+	vehicle := Vehicle{ID: car.Id}
+
+	if car.Sekisai != nil {
+		vehicle.MaxLoadKg = int(car.GetSekisai())
+	}
+
+	shashu := strings.TrimSpace(car.GetShashu())
+	if _, ok := classLimits[shashu]; ok || shashu == ClassLargeSpecial || shashu == ClassSmallSpecial {
+		vehicle.Class = shashu
+	}
+
+	return vehicle
+}
+
+// merge 不明な項目をotherで補う
+func (v Vehicle) merge(other Vehicle) Vehicle {
+	if v.Class == "" {
+		v.Class = other.Class
+	}
+	if v.GrossWeightKg == 0 {
+		v.GrossWeightKg = other.GrossWeightKg
+	}
+	if v.MaxLoadKg == 0 {
+		v.MaxLoadKg = other.MaxLoadKg
+	}
+	if v.Capacity == 0 {
+		v.Capacity = other.Capacity
+	}
+	return v
+}
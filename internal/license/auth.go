@@ -0,0 +1,431 @@
+package license
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"menkyo_go/internal/certs"
+	"menkyo_go/internal/config"
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/logging"
+	pb "menkyo_go/proto/license"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// 呼び出し元の権限
+const (
+	RoleReader = "reader" // 自分のリーダーIDでのデータ送信のみ
+	RoleAdmin  = "admin"  // 全てのメソッド
+)
+
+// 認証方式
+const (
+	AuthTypeMTLS   = "mtls"
+	AuthTypeAPIKey = "api_key"
+)
+
+// apiKeyPrefix APIキーの接頭辞（設定ファイル内で見分けやすくするため）
+const apiKeyPrefix = "mk_"
+
+// readerMethods リーダー権限で呼び出せるメソッド（リクエストのreader_idは自分のIDに限る）
+var readerMethods = map[string]bool{
+	pb.LicenseReader_PushLicenseData_FullMethodName:    true,
+	pb.LicenseReader_PushReadLog_FullMethodName:        true,
+	pb.LicenseReader_PushAssignment_FullMethodName:     true,
+	pb.LicenseReader_RegisterReader_FullMethodName:     true,
+	pb.LicenseReader_ReaderHeartbeat_FullMethodName:    true,
+	pb.LicenseReader_WatchConfig_FullMethodName:        true,
+	pb.LicenseReader_ReportConfigStatus_FullMethodName: true,
+	pb.LicenseReader_UploadBatch_FullMethodName:        true,
+}
+
+// publicMethods 認証なしで呼び出せるメソッド（ロードバランサーや監視からのヘルスチェック）
+var publicMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_Watch_FullMethodName: true,
+}
+
+// Identity 認証された呼び出し元
+type Identity struct {
+	Name     string // 証明書のCNまたはAPIキー名
+	Role     string
+	ReaderID string // roleがreaderの場合のリーダーID
+	AuthType string
+}
+
+type identityKey struct{}
+
+// IdentityFromContext 認証された呼び出し元を取得（認証が無効の場合はfalse）
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok
+}
+
+// caller 認証に使う呼び出し元の情報
+type caller struct {
+	certs    []*x509.Certificate // 検証済みのクライアント証明書
+	apiKey   string
+	peerAddr string
+}
+
+// readerIDGetter reader_idを持つリクエスト
+type readerIDGetter interface {
+	GetReaderId() string
+}
+
+// authError 拒否の理由
+type authError struct {
+	code   codes.Code
+	reason string
+}
+
+// Authorizer gRPC・Connectの呼び出しを認証・認可する
+type Authorizer struct {
+	mode   string
+	logger *database.Logger
+}
+
+// NewAuthorizer 新しいAuthorizerを作成（modeはconfig.AuthMode*）
+func NewAuthorizer(mode string, logger *database.Logger) (*Authorizer, error) {
+	switch mode {
+	case config.AuthModeOff, config.AuthModeAudit, config.AuthModeEnforce:
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %s", mode)
+	}
+
+	return &Authorizer{mode: mode, logger: logger}, nil
+}
+
+// GenerateAPIKey 新しいAPIキーを生成（キーと保存用のハッシュを返す）
+func GenerateAPIKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey APIキーのハッシュ（DBにはこの値のみ保存する）
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// authenticate 呼び出し元を認証（クライアント証明書を優先）
+func (a *Authorizer) authenticate(c caller) (*Identity, *authError) {
+	if len(c.certs) > 0 {
+		leaf := c.certs[0]
+		id := &Identity{
+			Name:     leaf.Subject.CommonName,
+			Role:     RoleReader,
+			ReaderID: leaf.Subject.CommonName,
+			AuthType: AuthTypeMTLS,
+		}
+		if slices.Contains(leaf.Subject.OrganizationalUnit, certs.UnitAdmin) {
+			id.Role = RoleAdmin
+			id.ReaderID = ""
+		}
+		return id, nil
+	}
+
+	if c.apiKey != "" {
+		key, err := a.logger.LookupAPIKey(HashAPIKey(c.apiKey))
+		if err != nil {
+			slog.Error("Failed to look up api key", "component", "auth", logging.Err(err))
+			return nil, &authError{codes.Internal, "failed to look up api key"}
+		}
+		if key == nil {
+			return nil, &authError{codes.Unauthenticated, "invalid or revoked api key"}
+		}
+		return &Identity{
+			Name:     key.Name,
+			Role:     key.Role,
+			ReaderID: key.ReaderID,
+			AuthType: AuthTypeAPIKey,
+		}, nil
+	}
+
+	return nil, &authError{codes.Unauthenticated, "no client certificate or api key"}
+}
+
+// check 呼び出し元がメソッドを呼び出せるか（reqがnilの場合はメソッドのみ確認）
+func (a *Authorizer) check(method string, id *Identity, req any) *authError {
+	switch id.Role {
+	case RoleAdmin:
+		return nil
+	case RoleReader:
+		if !readerMethods[method] {
+			return &authError{codes.PermissionDenied, "admin role required"}
+		}
+		if r, ok := req.(readerIDGetter); ok && r.GetReaderId() != id.ReaderID {
+			return &authError{codes.PermissionDenied,
+				fmt.Sprintf("reader %q cannot send as reader %q", id.ReaderID, r.GetReaderId())}
+		}
+		return nil
+	default:
+		return &authError{codes.PermissionDenied, fmt.Sprintf("unknown role: %s", id.Role)}
+	}
+}
+
+// authorize 呼び出しを認証・認可し、呼び出し元をコンテキストに設定
+func (a *Authorizer) authorize(ctx context.Context, method string, c caller, req any) (context.Context, error) {
+	if a.mode == config.AuthModeOff || publicMethods[method] {
+		return ctx, nil
+	}
+
+	id, denied := a.authenticate(c)
+	if denied == nil {
+		denied = a.check(method, id, req)
+	}
+	if id != nil {
+		ctx = context.WithValue(ctx, identityKey{}, id)
+	}
+
+	if denied != nil {
+		return ctx, a.deny(method, id, c, req, denied)
+	}
+	return ctx, nil
+}
+
+// authorizeMessage ストリームで受信したメッセージを認可
+func (a *Authorizer) authorizeMessage(ctx context.Context, method string, c caller, msg any) error {
+	if a.mode == config.AuthModeOff {
+		return nil
+	}
+
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if denied := a.check(method, id, msg); denied != nil {
+		return a.deny(method, id, c, msg, denied)
+	}
+	return nil
+}
+
+// deny 拒否を監査ログに記録（監査モードの場合は通す）
+func (a *Authorizer) deny(method string, id *Identity, c caller, req any, denied *authError) error {
+	allowed := a.mode == config.AuthModeAudit
+
+	record := &database.AuthAuditRecord{
+		Method:   method,
+		PeerAddr: c.peerAddr,
+		Reason:   denied.reason,
+		Allowed:  allowed,
+	}
+	if id != nil {
+		record.Identity = id.Name
+		record.AuthType = id.AuthType
+	}
+	if r, ok := req.(readerIDGetter); ok {
+		record.ReaderID = r.GetReaderId()
+	}
+
+	action := "denied"
+	if allowed {
+		action = "would be denied (audit mode)"
+	}
+	slog.Warn("Call "+action, "component", "auth", "method", method, "identity", record.Identity,
+		"peer", c.peerAddr, "reason", denied.reason, logging.KeyReaderID, record.ReaderID)
+	if err := a.logger.LogAuthAudit(record); err != nil {
+		slog.Error("Failed to record auth audit", "component", "auth", logging.Err(err))
+	}
+
+	if allowed {
+		return nil
+	}
+	return status.Error(denied.code, denied.reason)
+}
+
+// ---- gRPC ----
+
+// UnaryServerInterceptor gRPCの単項RPC用インターセプター
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod, grpcCaller(ctx), req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor gRPCのストリーミングRPC用インターセプター
+// 受信したメッセージもreader_idを確認する
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c := grpcCaller(ss.Context())
+		ctx, err := a.authorize(ss.Context(), info.FullMethod, c, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx, auth: a, method: info.FullMethod, caller: c})
+	}
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	auth   *Authorizer
+	method string
+	caller caller
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.auth.authorizeMessage(s.ctx, s.method, s.caller, m)
+}
+
+// grpcCaller gRPCのコンテキストから呼び出し元の情報を取得
+func grpcCaller(ctx context.Context) caller {
+	var c caller
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			c.peerAddr = p.Addr.String()
+		}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			c.certs = info.State.PeerCertificates
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			c.apiKey = bearerToken(values[0])
+		}
+	}
+
+	return c
+}
+
+// bearerToken "Bearer <key>"形式のヘッダーからキーを取り出す
+func bearerToken(value string) string {
+	if len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+		return strings.TrimSpace(value[7:])
+	}
+	return ""
+}
+
+// ---- Connect ----
+
+// httpPeer HTTPの接続元（Connectのハンドラーはgrpc/peerを使えないため）
+type httpPeer struct {
+	addr  string
+	certs []*x509.Certificate
+}
+
+type httpPeerKey struct{}
+
+// withHTTPPeer 接続元のアドレスとHTTPSのクライアント証明書をコンテキストに設定
+func withHTTPPeer(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := &httpPeer{addr: r.RemoteAddr}
+		if r.TLS != nil {
+			p.certs = r.TLS.PeerCertificates
+		}
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), httpPeerKey{}, p)))
+	})
+}
+
+// peerAddr 呼び出し元のアドレスを取得（gRPC・Connectの両方）
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	if p, ok := ctx.Value(httpPeerKey{}).(*httpPeer); ok {
+		return p.addr
+	}
+	return ""
+}
+
+// connectCaller Connectのリクエストから呼び出し元の情報を取得
+func connectCaller(ctx context.Context, header http.Header, p connect.Peer) caller {
+	c := caller{
+		apiKey:   bearerToken(header.Get("Authorization")),
+		peerAddr: p.Addr,
+	}
+	if hp, ok := ctx.Value(httpPeerKey{}).(*httpPeer); ok {
+		c.certs = hp.certs
+	}
+	return c
+}
+
+// ConnectInterceptor Connect（HTTP）用のインターセプター
+func (a *Authorizer) ConnectInterceptor() connect.Interceptor {
+	return &connectAuthInterceptor{auth: a}
+}
+
+type connectAuthInterceptor struct {
+	auth *Authorizer
+}
+
+func (i *connectAuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		c := connectCaller(ctx, req.Header(), req.Peer())
+		ctx, err := i.auth.authorize(ctx, req.Spec().Procedure, c, req.Any())
+		if err != nil {
+			return nil, toConnectError(err)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *connectAuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *connectAuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		c := connectCaller(ctx, conn.RequestHeader(), conn.Peer())
+		method := conn.Spec().Procedure
+		ctx, err := i.auth.authorize(ctx, method, c, nil)
+		if err != nil {
+			return toConnectError(err)
+		}
+		return next(ctx, &authorizedConn{StreamingHandlerConn: conn, ctx: ctx, auth: i.auth, method: method, caller: c})
+	}
+}
+
+type authorizedConn struct {
+	connect.StreamingHandlerConn
+	ctx    context.Context
+	auth   *Authorizer
+	method string
+	caller caller
+}
+
+func (c *authorizedConn) Receive(m any) error {
+	if err := c.StreamingHandlerConn.Receive(m); err != nil {
+		return err
+	}
+	if err := c.auth.authorizeMessage(c.ctx, c.method, c.caller, m); err != nil {
+		return toConnectError(err)
+	}
+	return nil
+}
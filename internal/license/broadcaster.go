@@ -0,0 +1,214 @@
+package license
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "menkyo_go/proto/license"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// 配信のバッファサイズ
+const (
+	DefaultHistorySize    = 1024 // 再接続時に再送できるイベント数
+	DefaultSubscriberSize = 256  // 購読者ごとの未送信イベントの上限
+)
+
+// ErrSubscriberLagged 購読者の受信が追いつかず切断した
+var ErrSubscriberLagged = fmt.Errorf("subscriber too slow")
+
+// ErrResumeOutOfRange 再開位置の直後のイベントが既に保持されておらず、続きから再開できない
+var ErrResumeOutOfRange = fmt.Errorf("resume point no longer retained")
+
+// ErrEpochMismatch 再開位置が別の起動（サーバー再起動前）の連番で、続きから再開できない
+var ErrEpochMismatch = fmt.Errorf("resume point belongs to another server boot")
+
+// Broadcaster 読み取りイベントを購読者に配信する
+type Broadcaster struct {
+	mu             sync.Mutex
+	epoch          string // 起動ID（seqは起動ごとに1から振り直すため、再開位置の起動を確認する）
+	seq            uint64
+	history        []*pb.ReadEvent // 直近のイベント（リングバッファ）
+	historyStart   int
+	subscribers    map[*Subscription]struct{}
+	subscriberSize int
+}
+
+// Subscription 購読
+type Subscription struct {
+	events   chan *pb.ReadEvent
+	readerID string
+	cardType string
+	lagged   bool
+	done     chan struct{}
+}
+
+// Events 受信チャネル（購読者が遅れて切断された場合は閉じられる）
+func (s *Subscription) Events() <-chan *pb.ReadEvent {
+	return s.events
+}
+
+// Lagged 受信が追いつかずに切断されたか
+func (s *Subscription) Lagged() bool {
+	select {
+	case <-s.done:
+		return s.lagged
+	default:
+		return false
+	}
+}
+
+func (s *Subscription) match(event *pb.ReadEvent) bool {
+	if s.readerID != "" && event.ReaderId != s.readerID {
+		return false
+	}
+	if s.cardType != "" && event.CardType != s.cardType {
+		return false
+	}
+	return true
+}
+
+// NewBroadcaster 新しいBroadcasterを作成
+func NewBroadcaster(historySize, subscriberSize int) *Broadcaster {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	if subscriberSize <= 0 {
+		subscriberSize = DefaultSubscriberSize
+	}
+	return &Broadcaster{
+		epoch:          newEpoch(),
+		history:        make([]*pb.ReadEvent, 0, historySize),
+		subscribers:    make(map[*Subscription]struct{}),
+		subscriberSize: subscriberSize,
+	}
+}
+
+// newEpoch 起動IDを生成
+func newEpoch() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Epoch 起動ID
+func (b *Broadcaster) Epoch() string {
+	return b.epoch
+}
+
+// PublishLicenseData 免許証データを配信（顔写真は除く）
+func (b *Broadcaster) PublishLicenseData(data *pb.LicenseData) {
+	data = proto.Clone(data).(*pb.LicenseData)
+	data.Photo = nil
+
+	b.publish(&pb.ReadEvent{
+		ReaderId: data.ReaderId,
+		CardType: "driver_license",
+		Payload:  &pb.ReadEvent_LicenseData{LicenseData: data},
+	})
+}
+
+// PublishReadLog 読み取りログを配信
+func (b *Broadcaster) PublishReadLog(logData *pb.ReadLog) {
+	b.publish(&pb.ReadEvent{
+		ReaderId: logData.ReaderId,
+		CardType: logData.CardType,
+		Payload:  &pb.ReadEvent_ReadLog{ReadLog: proto.Clone(logData).(*pb.ReadLog)},
+	})
+}
+
+func (b *Broadcaster) publish(event *pb.ReadEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.Seq = b.seq
+	event.Epoch = b.epoch
+	event.Timestamp = time.Now().Unix()
+
+	if len(b.history) < cap(b.history) {
+		b.history = append(b.history, event)
+	} else {
+		b.history[b.historyStart] = event
+		b.historyStart = (b.historyStart + 1) % len(b.history)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// バッファが一杯の購読者は切断（再接続してseqから再開できる）
+			sub.lagged = true
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe 購読を開始
+// resumeAfterが0でない場合は、それより後のイベントのうち保持しているものを先に返す。
+// resumeEpochがこの起動のIDと異なる場合（サーバー再起動前の連番、起動IDを送らないクライアント）はErrEpochMismatch、
+// resumeAfterより後のイベントが既に古いものから捨てられている場合はErrResumeOutOfRangeを返す
+func (b *Broadcaster) Subscribe(readerID, cardType, resumeEpoch string, resumeAfter uint64) (*Subscription, []*pb.ReadEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if resumeAfter != 0 {
+		if resumeEpoch != b.epoch || resumeAfter > b.seq {
+			return nil, nil, fmt.Errorf("%w: current epoch is %s", ErrEpochMismatch, b.epoch)
+		}
+		if len(b.history) > 0 {
+			if oldest := b.history[b.historyStart].Seq; oldest > resumeAfter+1 {
+				return nil, nil, fmt.Errorf("%w: oldest retained seq is %d", ErrResumeOutOfRange, oldest)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		events:   make(chan *pb.ReadEvent, b.subscriberSize),
+		readerID: readerID,
+		cardType: cardType,
+		done:     make(chan struct{}),
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if resumeAfter == 0 {
+		return sub, nil, nil
+	}
+
+	var backlog []*pb.ReadEvent
+	for i := 0; i < len(b.history); i++ {
+		event := b.history[(b.historyStart+i)%len(b.history)]
+		if event.Seq > resumeAfter && sub.match(event) {
+			backlog = append(backlog, event)
+		}
+	}
+
+	return sub, backlog, nil
+}
+
+// Unsubscribe 購読を終了
+func (b *Broadcaster) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// remove 購読者を削除してチャネルを閉じる（ロック取得済みで呼ぶ）
+func (b *Broadcaster) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.done)
+	close(sub.events)
+}
@@ -0,0 +1,127 @@
+package license
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	_ "google.golang.org/grpc/health" // クライアント側のヘルスチェック（healthCheckConfig）を有効にする
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+)
+
+// defaultCallTimeout 1回の呼び出しのタイムアウト（再試行を含む）
+const defaultCallTimeout = 5 * time.Second
+
+// licenseServiceConfig ライセンスサーバーへの接続のサービス設定
+// 再試行するのは何度呼んでも結果が変わらないメソッドのみ（Push*は重複して記録され、
+// ResolveAnomalyは2回目で解決日時が上書きされるため再試行しない）
+// UploadBatchは連番で重複を除くため再試行できる
+// waitForReadyにより、サーバーの再起動中は接続できるまでタイムアウトの範囲で待つ
+// healthCheckConfigにより、NOT_SERVINGを返すサーバーには送らない（複数のアドレスに解決される場合は他のサーバーを使う）
+const licenseServiceConfig = `{
+	"loadBalancingConfig": [{"round_robin": {}}],
+	"healthCheckConfig": {"serviceName": "license.LicenseReader"},
+	"methodConfig": [{
+		"name": [
+			{"service": "license.LicenseReader", "method": "GetLogs"},
+			{"service": "license.LicenseReader", "method": "GetReadHistory"},
+			{"service": "license.LicenseReader", "method": "GetWorkHours"},
+			{"service": "license.LicenseReader", "method": "ListAnomalies"},
+			{"service": "license.LicenseReader", "method": "RegisterReader"},
+			{"service": "license.LicenseReader", "method": "ReaderHeartbeat"},
+			{"service": "license.LicenseReader", "method": "ListReaders"},
+			{"service": "license.LicenseReader", "method": "ReportConfigStatus"},
+			{"service": "license.LicenseReader", "method": "UploadBatch"},
+			{"service": "grpc.health.v1.Health", "method": "Check"}
+		],
+		"waitForReady": true,
+		"retryPolicy": {
+			"maxAttempts": 4,
+			"initialBackoff": "0.2s",
+			"maxBackoff": "2s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE", "RESOURCE_EXHAUSTED"]
+		}
+	}]
+}`
+
+// dbServiceConfig db_serviceへの接続のサービス設定（参照のみ再試行する）
+const dbServiceConfig = `{
+	"methodConfig": [{
+		"name": [
+			{"service": "db_service.db_CarsService", "method": "Get"},
+			{"service": "db_service.db_DriversService", "method": "Get"}
+		],
+		"waitForReady": true,
+		"retryPolicy": {
+			"maxAttempts": 3,
+			"initialBackoff": "0.2s",
+			"maxBackoff": "1s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+// clientKeepalive 通信がない間もPINGで接続を確認する（NATやファイアウォールでの切断を早く検知する）
+// サーバー側のEnforcementPolicy（ServerKeepaliveOptions）のMinTimeより長くすること
+var clientKeepalive = keepalive.ClientParameters{
+	Time:                30 * time.Second,
+	Timeout:             10 * time.Second,
+	PermitWithoutStream: true,
+}
+
+// ServerKeepaliveOptions ライセンスサーバーのkeepalive設定（クライアントのPINGを許可する）
+func ServerKeepaliveOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             15 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    time.Minute,
+			Timeout: 10 * time.Second,
+		}),
+	}
+}
+
+// callContext 1回の呼び出し用のコンテキスト
+func (c *Client) callContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.callTimeout)
+}
+
+// State ライセンスサーバーへの接続状態
+func (c *Client) State() connectivity.State {
+	return c.conn.GetState()
+}
+
+// MonitorConnection 接続状態の変化を通知する（ctxがキャンセルされるまで）
+// 切断された場合はすぐに再接続を試みる（通常は次の呼び出しまで再接続しない）
+func (c *Client) MonitorConnection(ctx context.Context, notify func(connectivity.State)) {
+	state := c.conn.GetState()
+	notify(state)
+
+	for {
+		if state == connectivity.Idle || state == connectivity.TransientFailure {
+			c.conn.Connect()
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return
+		}
+
+		state = c.conn.GetState()
+		notify(state)
+	}
+}
+
+// CheckHealth grpc.health.v1でサーバーの状態を確認（serviceが空の場合はサーバー全体）
+func (c *Client) CheckHealth(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, fmt.Errorf("health check failed: %w", err)
+	}
+	return resp.Status, nil
+}
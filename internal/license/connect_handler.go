@@ -0,0 +1,115 @@
+package license
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	pb "menkyo_go/proto/license"
+	"menkyo_go/proto/license/licenseconnect"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/status"
+)
+
+// connectService ServerをConnectのハンドラーとして公開するアダプター
+// Connect・gRPC・gRPC-Webの各プロトコルをHTTP/1.1とHTTP/2で受け付ける
+type connectService struct {
+	server *Server
+}
+
+// NewConnectHandler Connectハンドラーを作成（マウントするパスとハンドラーを返す）
+func (s *Server) NewConnectHandler(opts ...connect.HandlerOption) (string, http.Handler) {
+	path, handler := licenseconnect.NewLicenseReaderHandler(&connectService{server: s}, opts...)
+	return path, withHTTPPeer(handler)
+}
+
+// unary gRPC形式のメソッドをConnect形式に変換
+func unary[Req, Res any](ctx context.Context, req *connect.Request[Req], fn func(context.Context, *Req) (*Res, error)) (*connect.Response[Res], error) {
+	res, err := fn(ctx, req.Msg)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// toConnectError gRPCのステータスをConnectのエラーに変換（コードの値は共通）
+func toConnectError(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	}
+	return err
+}
+
+func (c *connectService) PushLicenseData(ctx context.Context, req *connect.Request[pb.LicenseData]) (*connect.Response[pb.PushResponse], error) {
+	return unary(ctx, req, c.server.PushLicenseData)
+}
+
+func (c *connectService) PushReadLog(ctx context.Context, req *connect.Request[pb.ReadLog]) (*connect.Response[pb.PushResponse], error) {
+	return unary(ctx, req, c.server.PushReadLog)
+}
+
+func (c *connectService) GetLogs(ctx context.Context, req *connect.Request[pb.GetLogsRequest]) (*connect.Response[pb.GetLogsResponse], error) {
+	return unary(ctx, req, c.server.GetLogs)
+}
+
+func (c *connectService) GetReadHistory(ctx context.Context, req *connect.Request[pb.GetReadHistoryRequest]) (*connect.Response[pb.GetReadHistoryResponse], error) {
+	return unary(ctx, req, c.server.GetReadHistory)
+}
+
+func (c *connectService) PushAssignment(ctx context.Context, req *connect.Request[pb.Assignment]) (*connect.Response[pb.PushResponse], error) {
+	return unary(ctx, req, c.server.PushAssignment)
+}
+
+func (c *connectService) GetWorkHours(ctx context.Context, req *connect.Request[pb.GetWorkHoursRequest]) (*connect.Response[pb.GetWorkHoursResponse], error) {
+	return unary(ctx, req, c.server.GetWorkHours)
+}
+
+func (c *connectService) ListAnomalies(ctx context.Context, req *connect.Request[pb.ListAnomaliesRequest]) (*connect.Response[pb.ListAnomaliesResponse], error) {
+	return unary(ctx, req, c.server.ListAnomalies)
+}
+
+func (c *connectService) ResolveAnomaly(ctx context.Context, req *connect.Request[pb.ResolveAnomalyRequest]) (*connect.Response[pb.ResolveAnomalyResponse], error) {
+	return unary(ctx, req, c.server.ResolveAnomaly)
+}
+
+func (c *connectService) WatchReads(ctx context.Context, req *connect.Request[pb.WatchReadsRequest], stream *connect.ServerStream[pb.ReadEvent]) error {
+	return toConnectError(c.server.watchReads(ctx, req.Msg, stream.Send))
+}
+
+func (c *connectService) RegisterReader(ctx context.Context, req *connect.Request[pb.RegisterReaderRequest]) (*connect.Response[pb.RegisterReaderResponse], error) {
+	return unary(ctx, req, c.server.RegisterReader)
+}
+
+func (c *connectService) ReaderHeartbeat(ctx context.Context, req *connect.Request[pb.ReaderHeartbeatRequest]) (*connect.Response[pb.ReaderHeartbeatResponse], error) {
+	return unary(ctx, req, c.server.ReaderHeartbeat)
+}
+
+func (c *connectService) ListReaders(ctx context.Context, req *connect.Request[pb.ListReadersRequest]) (*connect.Response[pb.ListReadersResponse], error) {
+	return unary(ctx, req, c.server.ListReaders)
+}
+
+func (c *connectService) WatchConfig(ctx context.Context, req *connect.Request[pb.WatchConfigRequest], stream *connect.ServerStream[pb.ReaderConfig]) error {
+	return toConnectError(c.server.watchConfig(ctx, req.Msg, stream.Send))
+}
+
+func (c *connectService) ReportConfigStatus(ctx context.Context, req *connect.Request[pb.ReportConfigStatusRequest]) (*connect.Response[pb.ReportConfigStatusResponse], error) {
+	return unary(ctx, req, c.server.ReportConfigStatus)
+}
+
+func (c *connectService) UploadBatch(ctx context.Context, stream *connect.ClientStream[pb.UploadItem]) (*connect.Response[pb.UploadBatchResponse], error) {
+	res, err := c.server.uploadBatch(ctx, func() (*pb.UploadItem, error) {
+		if !stream.Receive() {
+			if err := stream.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return stream.Msg(), nil
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
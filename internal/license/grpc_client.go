@@ -2,14 +2,23 @@ package license
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"time"
 
+	"menkyo_go/internal/logging"
 	pb "menkyo_go/proto/license"
 	dbpb "github.com/yhonda-ohishi/db_service/src/proto"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 // Client gRPCクライアント
@@ -19,51 +28,125 @@ type Client struct {
 	target       string
 	dbConn       *grpc.ClientConn
 	dbClient     dbpb.Db_TimeCardDevServiceClient
+	carsClient   dbpb.Db_CarsServiceClient
+	drivers      dbpb.Db_DriversServiceClient
 	dbServerAddr string
+	callTimeout  time.Duration
+}
+
+// ClientOptions Clientの接続オプション
+type ClientOptions struct {
+	ServerTLS *tls.Config // ライセンスサーバーへの接続のTLS設定（nilの場合は平文）
+	DBTLS     *tls.Config // db_serviceへの接続のTLS設定（nilの場合は平文）
+	APIKey    string      // ライセンスサーバーのAPIキー（mTLSを使わない場合）
+
+	// 1回の呼び出しのタイムアウト（再試行・再接続の待ち時間を含む。0の場合は5秒）
+	CallTimeout time.Duration
 }
 
 // NewClient 新しいClientを作成
 func NewClient(target string) (*Client, error) {
-	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return NewClientWithOptions(target, "", ClientOptions{})
+}
+
+// NewClientWithDB DBサーバー接続付きのClientを作成
+func NewClientWithDB(target, dbServerAddr string) (*Client, error) {
+	return NewClientWithOptions(target, dbServerAddr, ClientOptions{})
+}
+
+// NewClientWithOptions 接続オプションを指定してClientを作成
+// dbServerAddrが空の場合はDBサーバーに接続しない
+func NewClientWithOptions(target, dbServerAddr string, opts ClientOptions) (*Client, error) {
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials(opts.ServerTLS)),
+		grpc.WithDefaultServiceConfig(licenseServiceConfig),
+		grpc.WithKeepaliveParams(clientKeepalive),
+		grpc.WithStatsHandler(tracedCallsOnly{otelgrpc.NewClientHandler()}),
+	}
+	if opts.APIKey != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(apiKeyCredentials(opts.APIKey)))
+	}
+
+	// 接続は最初の呼び出し時（またはMonitorConnection）に行う
+	conn, err := grpc.NewClient(target, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to server: %w", err)
 	}
 
-	client := pb.NewLicenseReaderClient(conn)
+	c := &Client{
+		conn:        conn,
+		client:      pb.NewLicenseReaderClient(conn),
+		target:      target,
+		callTimeout: opts.CallTimeout,
+	}
+	if c.callTimeout <= 0 {
+		c.callTimeout = defaultCallTimeout
+	}
+
+	if dbServerAddr == "" {
+		return c, nil
+	}
 
-	return &Client{
-		conn:   conn,
-		client: client,
-		target: target,
-	}, nil
+	if err := c.connectDB(dbServerAddr, opts.DBTLS); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
 }
 
-// NewClientWithDB DBサーバー接続付きのClientを作成
-func NewClientWithDB(target, dbServerAddr string) (*Client, error) {
-	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to server: %w", err)
+// NewDBClient db_serviceのみに接続するClientを作成（運転者・車両情報の取得用）
+// ライセンスサーバーには接続しないため、ライセンスサーバーのRPCは使用できない
+func NewDBClient(dbServerAddr string, opts ClientOptions) (*Client, error) {
+	c := &Client{callTimeout: opts.CallTimeout}
+	if c.callTimeout <= 0 {
+		c.callTimeout = defaultCallTimeout
 	}
 
-	client := pb.NewLicenseReaderClient(conn)
+	if err := c.connectDB(dbServerAddr, opts.DBTLS); err != nil {
+		return nil, err
+	}
 
-	// DBサーバーに接続
-	dbConn, err := grpc.Dial(dbServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return c, nil
+}
+
+// connectDB DBサーバーに接続
+func (c *Client) connectDB(dbServerAddr string, tlsConfig *tls.Config) error {
+	dbConn, err := grpc.NewClient(dbServerAddr,
+		grpc.WithTransportCredentials(transportCredentials(tlsConfig)),
+		grpc.WithDefaultServiceConfig(dbServiceConfig),
+		grpc.WithStatsHandler(tracedCallsOnly{otelgrpc.NewClientHandler()}))
 	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to connect to db server: %w", err)
+		return fmt.Errorf("failed to connect to db server: %w", err)
+	}
+
+	c.dbConn = dbConn
+	c.dbClient = dbpb.NewDb_TimeCardDevServiceClient(dbConn)
+	c.carsClient = dbpb.NewDb_CarsServiceClient(dbConn)
+	c.drivers = dbpb.NewDb_DriversServiceClient(dbConn)
+	c.dbServerAddr = dbServerAddr
+
+	return nil
+}
+
+// transportCredentials TLS設定からgRPCの認証情報を作成
+func transportCredentials(tlsConfig *tls.Config) credentials.TransportCredentials {
+	if tlsConfig == nil {
+		return insecure.NewCredentials()
 	}
+	return credentials.NewTLS(tlsConfig)
+}
+
+// apiKeyCredentials APIキーをauthorizationヘッダーで送る
+// TLSなしのLAN内でも使えるようにするが、キーが平文で流れるためTLSとの併用を推奨
+type apiKeyCredentials string
 
-	dbClient := dbpb.NewDb_TimeCardDevServiceClient(dbConn)
+func (k apiKeyCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(k)}, nil
+}
 
-	return &Client{
-		conn:         conn,
-		client:       client,
-		target:       target,
-		dbConn:       dbConn,
-		dbClient:     dbClient,
-		dbServerAddr: dbServerAddr,
-	}, nil
+func (k apiKeyCredentials) RequireTransportSecurity() bool {
+	return false
 }
 
 // Close クライアントを閉じる
@@ -84,7 +167,12 @@ func (c *Client) Close() error {
 
 // PushLicenseData 免許証データをプッシュ
 func (c *Client) PushLicenseData(data *pb.LicenseData) (*pb.PushResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return c.PushLicenseDataContext(context.Background(), data)
+}
+
+// PushLicenseDataContext PushLicenseDataのctx指定版（ctxのトレースをメタデータでサーバーに伝搬する）
+func (c *Client) PushLicenseDataContext(ctx context.Context, data *pb.LicenseData) (*pb.PushResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
 	defer cancel()
 
 	resp, err := c.client.PushLicenseData(ctx, data)
@@ -97,7 +185,12 @@ func (c *Client) PushLicenseData(data *pb.LicenseData) (*pb.PushResponse, error)
 
 // PushReadLog 読み取りログをプッシュ
 func (c *Client) PushReadLog(logData *pb.ReadLog) (*pb.PushResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return c.PushReadLogContext(context.Background(), logData)
+}
+
+// PushReadLogContext PushReadLogのctx指定版（ctxのトレースをメタデータでサーバーに伝搬する）
+func (c *Client) PushReadLogContext(ctx context.Context, logData *pb.ReadLog) (*pb.PushResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
 	defer cancel()
 
 	resp, err := c.client.PushReadLog(ctx, logData)
@@ -108,13 +201,54 @@ func (c *Client) PushReadLog(logData *pb.ReadLog) (*pb.PushResponse, error) {
 	return resp, nil
 }
 
+// UploadBatch 未送信のデータをまとめて送信（レスポンスのacked_seqまでサーバーで確定済み）
+func (c *Client) UploadBatch(items []*pb.UploadItem) (*pb.UploadBatchResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stream, err := c.client.UploadBatch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload stream: %w", err)
+	}
+
+	for _, item := range items {
+		if err := stream.Send(item); err != nil {
+			// io.EOFの場合はサーバーがストリームを終了している（エラーはCloseAndRecvで受け取る）
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to send upload item: %w", err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload batch: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PushAssignment 配車（運転者と車両の割り当て）をプッシュ
+func (c *Client) PushAssignment(assignment *pb.Assignment) (*pb.PushResponse, error) {
+	ctx, cancel := c.callContext()
+	defer cancel()
+
+	resp, err := c.client.PushAssignment(ctx, assignment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to push assignment: %w", err)
+	}
+
+	return resp, nil
+}
+
 // InsertTimeCard TimeCardをDBに挿入
 func (c *Client) InsertTimeCard(driverID int32, cardID string, state string) (*dbpb.Db_TimeCardResponse, error) {
 	if c.dbClient == nil {
 		return nil, fmt.Errorf("db client not initialized - use NewClientWithDB")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := c.callContext()
 	defer cancel()
 
 	now := time.Now()
@@ -143,3 +277,130 @@ func (c *Client) InsertTimeCard(driverID int32, cardID string, state string) (*d
 
 	return resp, nil
 }
+
+// WatchReads 読み取りイベントを受信（ctxがキャンセルされるまで再接続して続きから受信）
+// 切断中のイベントがサーバーに残っていない、またはサーバーが再起動して続きから受信できない場合は、codes.OutOfRangeのエラーを返す
+// （GetReadHistoryで取りこぼしを取得してから呼び直す）
+func (c *Client) WatchReads(ctx context.Context, readerID, cardType string, handle func(*pb.ReadEvent)) error {
+	var lastSeq uint64
+	var lastEpoch string
+
+	for {
+		stream, err := c.client.WatchReads(ctx, &pb.WatchReadsRequest{
+			ReaderId:       readerID,
+			CardType:       cardType,
+			ResumeAfterSeq: lastSeq,
+			ResumeEpoch:    lastEpoch,
+		})
+
+		for err == nil {
+			var event *pb.ReadEvent
+			event, err = stream.Recv()
+			if err == nil {
+				lastSeq, lastEpoch = event.Seq, event.Epoch
+				handle(event)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if status.Code(err) == codes.OutOfRange {
+			return err
+		}
+
+		slog.Warn("WatchReads disconnected", "last_seq", lastSeq, logging.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// WatchConfig リーダーの設定を受信し続ける（切断された場合は受信済みのバージョンから再接続）
+// ctxがキャンセルされるまで戻らない
+func (c *Client) WatchConfig(ctx context.Context, req *pb.WatchConfigRequest, handle func(*pb.ReaderConfig)) error {
+	currentVersion := req.CurrentVersion
+
+	for {
+		stream, err := c.client.WatchConfig(ctx, &pb.WatchConfigRequest{
+			ReaderId:       req.ReaderId,
+			Site:           req.Site,
+			CurrentVersion: currentVersion,
+		})
+
+		for err == nil {
+			var config *pb.ReaderConfig
+			config, err = stream.Recv()
+			if err == nil {
+				currentVersion = config.Version
+				handle(config)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		slog.Warn("WatchConfig disconnected", "version", currentVersion, logging.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// ReportConfigStatus 設定の適用結果を報告
+func (c *Client) ReportConfigStatus(readerID string, version int64, errMsg string) error {
+	ctx, cancel := c.callContext()
+	defer cancel()
+
+	_, err := c.client.ReportConfigStatus(ctx, &pb.ReportConfigStatusRequest{
+		ReaderId: readerID,
+		Version:  version,
+		Error:    errMsg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to report config status: %w", err)
+	}
+
+	return nil
+}
+
+// GetCar db_serviceから車両情報を取得
+func (c *Client) GetCar(id string) (*dbpb.Db_Cars, error) {
+	if c.carsClient == nil {
+		return nil, fmt.Errorf("db client not initialized - use NewClientWithDB")
+	}
+
+	ctx, cancel := c.callContext()
+	defer cancel()
+
+	resp, err := c.carsClient.Get(ctx, &dbpb.Db_GetCarsRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get car: %w", err)
+	}
+
+	return resp.Cars, nil
+}
+
+// GetDriver db_serviceから運転者情報を取得
+func (c *Client) GetDriver(id int32) (*dbpb.Db_Drivers, error) {
+	if c.drivers == nil {
+		return nil, fmt.Errorf("db client not initialized - use NewClientWithDB")
+	}
+
+	ctx, cancel := c.callContext()
+	defer cancel()
+
+	resp, err := c.drivers.Get(ctx, &dbpb.Db_GetDriversRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver: %w", err)
+	}
+
+	return resp.Drivers, nil
+}
@@ -2,63 +2,132 @@ package license
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
 	"time"
 
-	pb "menkyo_go/proto/license"
+	"menkyo_go/internal/attendance"
 	"menkyo_go/internal/database"
+	"menkyo_go/internal/logging"
+	"menkyo_go/internal/metrics"
+	"menkyo_go/internal/webhook"
+	pb "menkyo_go/proto/license"
 
-	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Server gRPCサーバー
 type Server struct {
 	pb.UnimplementedLicenseReaderServer
-	logger   *database.Logger
-	callback func(*pb.LicenseData)
+	logger     *database.Logger
+	callback   func(*pb.LicenseData)
+	attendance *attendance.Engine
+	reads      *Broadcaster
+	webhooks   *webhook.Dispatcher
+	staleAfter time.Duration
+
+	// 登録済みのリーダー（メトリクスのラベルに使ってよいreader_id）
+	readersMu    sync.RWMutex
+	knownReaders map[string]struct{}
 }
 
 // NewServer 新しいServerを作成
 func NewServer(logger *database.Logger, callback func(*pb.LicenseData)) *Server {
-	return &Server{
-		logger:   logger,
-		callback: callback,
+	s := &Server{
+		logger:       logger,
+		callback:     callback,
+		reads:        NewBroadcaster(DefaultHistorySize, DefaultSubscriberSize),
+		staleAfter:   DefaultReaderStaleAfter,
+		knownReaders: make(map[string]struct{}),
+	}
+
+	// 再起動前に登録済みのリーダー
+	if logger != nil {
+		if readers, err := logger.ListReaders(""); err == nil {
+			for _, r := range readers {
+				s.knownReaders[r.ReaderID] = struct{}{}
+			}
+		} else {
+			slog.Warn("Failed to load registered readers", logging.Err(err))
+		}
+	}
+
+	// 勤務時間はサーバーのread_historyから集計（SetAttendanceEngineで運転者の勤務体系を設定しない場合は全員DefaultRule）
+	if logger != nil {
+		s.attendance = attendance.NewEngine(nil, attendance.NewReadHistorySource(logger))
+	}
+
+	return s
+}
+
+// SetWebhookDispatcher 受信した読み取りを送るWebhookのDispatcherを設定
+func (s *Server) SetWebhookDispatcher(d *webhook.Dispatcher) {
+	s.webhooks = d
+}
+
+// publishLicenseData 受信した免許証データをコールバック・WatchReads・Webhookに配信
+func (s *Server) publishLicenseData(data *pb.LicenseData) {
+	metrics.CountServerRead(data.ReaderId, s.isKnownReader(data.ReaderId), "driver_license", "success")
+
+	if s.callback != nil {
+		s.callback(data)
+	}
+
+	s.reads.PublishLicenseData(data)
+
+	if s.webhooks != nil {
+		s.webhooks.Publish(webhook.NewEvent(webhook.EventLicenseRead, webhook.EventData{
+			ReaderID:   data.ReaderId,
+			CardID:     data.CardId,
+			CardType:   "driver_license",
+			ExpiryDate: data.ExpiryDate,
+			ReadAt:     time.Unix(data.ReadTimestamp, 0).UTC(),
+		}))
+	}
+}
+
+// publishReadLog 受信した読み取りログをWatchReads・Webhook（エラーのみ）に配信
+func (s *Server) publishReadLog(logData *pb.ReadLog) {
+	metrics.CountServerRead(logData.ReaderId, s.isKnownReader(logData.ReaderId), logData.CardType, logData.Status)
+
+	s.reads.PublishReadLog(logData)
+
+	if s.webhooks != nil && logData.Status == "error" {
+		s.webhooks.Publish(webhook.NewEvent(webhook.EventReadError, webhook.EventData{
+			ReaderID:     logData.ReaderId,
+			CardID:       logData.CardId,
+			CardType:     logData.CardType,
+			ReadAt:       time.Unix(logData.Timestamp, 0).UTC(),
+			ErrorMessage: logData.ErrorMessage,
+		}))
 	}
 }
 
+// SetAttendanceEngine 勤務時間の集計に使うEngineを設定
+func (s *Server) SetAttendanceEngine(engine *attendance.Engine) {
+	s.attendance = engine
+}
+
 // PushLicenseData 免許証データを受信
 func (s *Server) PushLicenseData(ctx context.Context, data *pb.LicenseData) (*pb.PushResponse, error) {
-	requestID := uuid.New().String()
+	requestID := newRequestID(ctx)
 
-	log.Printf("[%s] Received license data: CardID=%s, Type=%s",
-		requestID, data.CardId, data.LicenseType)
+	reqLog := slog.With(logging.KeyRequestID, requestID, logging.KeyReaderID, data.ReaderId, logging.KeyCardID, data.CardId)
+	reqLog.InfoContext(ctx, "Received license data", "card_type", data.LicenseType)
 
 	// データベースに記録
 	if s.logger != nil {
-		record := &database.ReadHistoryRecord{
-			ReaderID:    data.ReaderId,
-			CardID:      data.CardId,
-			CardType:    "driver_license",
-			ATR:         "", // ATRは含まれていない
-			ExpiryDate:  data.ExpiryDate,
-			RemainCount: "",
-			FeliCaUID:   "",
-			Status:      "success",
-			Timestamp:   time.Unix(data.ReadTimestamp, 0),
-		}
-
-		if err := s.logger.LogReadHistory(record); err != nil {
-			log.Printf("[%s] Failed to log read history: %v", requestID, err)
-		}
-
-		s.logger.LogMessage("INFO", fmt.Sprintf("Received license data: %s", data.CardId))
+		s.logger.LogReadHistory(ctx, licenseDataRecord(data), func(err error) {
+			if err != nil {
+				reqLog.ErrorContext(ctx, "Failed to log read history", logging.Err(err))
+			}
+		})
 	}
 
-	// コールバックを実行
-	if s.callback != nil {
-		s.callback(data)
-	}
+	s.publishLicenseData(data)
 
 	return &pb.PushResponse{
 		Success:   true,
@@ -69,51 +138,249 @@ func (s *Server) PushLicenseData(ctx context.Context, data *pb.LicenseData) (*pb
 
 // PushReadLog 読み取りログを受信
 func (s *Server) PushReadLog(ctx context.Context, logData *pb.ReadLog) (*pb.PushResponse, error) {
-	requestID := uuid.New().String()
+	requestID := newRequestID(ctx)
 
-	log.Printf("[%s] Received read log: ReaderID=%s, Status=%s",
-		requestID, logData.ReaderId, logData.Status)
+	// リーダーから受信したログはそのままlogsテーブルに記録する（受信したことはコンソールのみ）
+	slog.DebugContext(ctx, "Received read log", logging.KeyRequestID, requestID,
+		logging.KeyReaderID, logData.ReaderId, "status", logData.Status)
 
 	// データベースに記録
 	if s.logger != nil {
-		level := "INFO"
-		if logData.Status == "error" {
-			level = "ERROR"
+		entry := readLogEntry(logData)
+
+		if err := s.logger.LogMessageWithAttrs(entry.Level, entry.Message, entry.ReaderID, entry.CardID,
+			fmt.Sprintf(`{"request_id":%q}`, requestID)); err != nil {
+			slog.ErrorContext(ctx, "Failed to log message", logging.KeyRequestID, requestID, logging.Err(err))
+		}
+	}
+
+	s.publishReadLog(logData)
+
+	return &pb.PushResponse{
+		Success:   true,
+		Message:   "Read log received successfully",
+		RequestId: requestID,
+	}, nil
+}
+
+// WatchReads 読み取りイベントを配信
+func (s *Server) WatchReads(req *pb.WatchReadsRequest, stream pb.LicenseReader_WatchReadsServer) error {
+	return s.watchReads(stream.Context(), req, stream.Send)
+}
+
+// watchReads gRPCとConnectで共通のWatchReads
+func (s *Server) watchReads(ctx context.Context, req *pb.WatchReadsRequest, send func(*pb.ReadEvent) error) error {
+	sub, backlog, err := s.reads.Subscribe(req.ReaderId, req.CardType, req.ResumeEpoch, req.ResumeAfterSeq)
+	if err != nil {
+		// 取りこぼしたイベントを再送できないため、クライアントに全件の再取得を求める
+		return status.Errorf(codes.OutOfRange,
+			"%v: resync with GetReadHistory and resume with resume_after_seq=0", err)
+	}
+	defer s.reads.Unsubscribe(sub)
+
+	slog.Info("WatchReads subscribed", "reader", req.ReaderId, "card_type", req.CardType,
+		"resume_after", req.ResumeAfterSeq, "backlog", len(backlog))
+
+	// 再接続時は取りこぼしたイベントを先に送信
+	var lastSeq uint64
+	resumeSeq := req.ResumeAfterSeq
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return err
 		}
+		lastSeq = event.Seq
+		resumeSeq = event.Seq
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Lagged() {
+					return status.Errorf(codes.ResourceExhausted,
+						"%v: resume with resume_epoch=%s resume_after_seq=%d", ErrSubscriberLagged, s.reads.Epoch(), resumeSeq)
+				}
+				return nil
+			}
+			if event.Seq <= lastSeq {
+				continue // backlogで送信済み
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+			lastSeq = event.Seq
+			resumeSeq = event.Seq
+		}
+	}
+}
+
+// PushAssignment 配車（運転者と車両の割り当て）を受信
+func (s *Server) PushAssignment(ctx context.Context, assignment *pb.Assignment) (*pb.PushResponse, error) {
+	requestID := newRequestID(ctx)
 
-		message := fmt.Sprintf("Reader %s: %s", logData.ReaderId, logData.Status)
-		if logData.ErrorMessage != "" {
-			message += fmt.Sprintf(" - %s", logData.ErrorMessage)
+	reqLog := slog.With(logging.KeyRequestID, requestID, logging.KeyReaderID, assignment.ReaderId, logging.KeyCardID, assignment.LicenseCardId)
+
+	// データベースに記録
+	if s.logger != nil {
+		record := &database.AssignmentRecord{
+			Timestamp:     time.Unix(assignment.AssignedAt, 0),
+			ReaderID:      assignment.ReaderId,
+			LicenseCardID: assignment.LicenseCardId,
+			DriverID:      assignment.DriverId,
+			VehicleCardID: assignment.VehicleCardId,
+			VehicleID:     assignment.VehicleId,
+			Status:        database.AssignmentStatusAssigned,
+			Synced:        true,
 		}
 
-		if err := s.logger.LogMessageWithContext(level, message, logData.ReaderId, logData.CardId); err != nil {
-			log.Printf("[%s] Failed to log message: %v", requestID, err)
+		if err := s.logger.LogAssignment(record); err != nil {
+			reqLog.ErrorContext(ctx, "Failed to log assignment", logging.Err(err))
 		}
 	}
 
+	reqLog.InfoContext(ctx, "Received assignment", "driver_id", assignment.DriverId, "vehicle_id", assignment.VehicleId)
+
 	return &pb.PushResponse{
 		Success:   true,
-		Message:   "Read log received successfully",
+		Message:   "Assignment received successfully",
 		RequestId: requestID,
 	}, nil
 }
 
-// TODO: GetLogs/GetReadHistory - protoファイルを更新して再生成後に有効化
-/*
+// GetWorkHours 勤務時間の集計を取得
+func (s *Server) GetWorkHours(ctx context.Context, req *pb.GetWorkHoursRequest) (*pb.GetWorkHoursResponse, error) {
+	if s.attendance == nil {
+		return nil, status.Error(codes.FailedPrecondition, "attendance engine not initialized")
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start_date: %v", err)
+	}
+
+	endDate := startDate
+	if req.EndDate != "" {
+		endDate, err = time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid end_date: %v", err)
+		}
+	}
+
+	shifts, err := s.attendance.Compute(startDate, endDate, req.DriverId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compute work hours: %v", err)
+	}
+
+	pbShifts := make([]*pb.WorkShift, len(shifts))
+	for i, shift := range shifts {
+		pbShifts[i] = &pb.WorkShift{
+			DriverId:         shift.DriverID,
+			WorkDate:         shift.WorkDate,
+			StartTime:        unixOrZero(shift.Start),
+			EndTime:          unixOrZero(shift.End),
+			TotalMinutes:     int32(shift.TotalMinutes),
+			BreakMinutes:     int32(shift.BreakMinutes),
+			OvertimeMinutes:  int32(shift.OvertimeMinutes),
+			LateNightMinutes: int32(shift.LateNightMinutes),
+			MissingClockIn:   shift.MissingIn,
+			MissingClockOut:  shift.MissingOut,
+			InProgress:       shift.InProgress,
+		}
+	}
+
+	return &pb.GetWorkHoursResponse{Shifts: pbShifts}, nil
+}
+
+// ListAnomalies 打刻の異常一覧を取得
+func (s *Server) ListAnomalies(ctx context.Context, req *pb.ListAnomaliesRequest) (*pb.ListAnomaliesResponse, error) {
+	if s.logger == nil {
+		return nil, status.Error(codes.FailedPrecondition, "logger not initialized")
+	}
+
+	records, err := s.logger.ListAnomalies(req.WorkDate, req.IncludeResolved)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list anomalies: %v", err)
+	}
+
+	anomalies := make([]*pb.Anomaly, len(records))
+	for i, record := range records {
+		anomalies[i] = &pb.Anomaly{
+			Id:             record.ID,
+			DetectedAt:     unixOrZero(record.DetectedAt),
+			WorkDate:       record.WorkDate,
+			Kind:           record.Kind,
+			DriverId:       record.DriverID,
+			CardId:         record.CardID,
+			PunchTime:      unixOrZero(record.PunchTime),
+			Detail:         record.Detail,
+			Resolved:       record.Resolved,
+			ResolvedAt:     unixOrZero(record.ResolvedAt),
+			ResolvedBy:     record.ResolvedBy,
+			ResolutionNote: record.ResolutionNote,
+		}
+	}
+
+	return &pb.ListAnomaliesResponse{Anomalies: anomalies}, nil
+}
+
+// ResolveAnomaly 打刻の異常を解決済みにする
+func (s *Server) ResolveAnomaly(ctx context.Context, req *pb.ResolveAnomalyRequest) (*pb.ResolveAnomalyResponse, error) {
+	if s.logger == nil {
+		return nil, status.Error(codes.FailedPrecondition, "logger not initialized")
+	}
+
+	if err := s.logger.ResolveAnomaly(req.Id, req.ResolvedBy, req.Note); err != nil {
+		if errors.Is(err, database.ErrAnomalyNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to resolve anomaly: %v", err)
+	}
+
+	// 解決メモは自由記述で個人データを含み得るためログに出力しない
+	slog.Info("Anomaly resolved", "anomaly_id", req.Id, "resolved_by", req.ResolvedBy)
+
+	return &pb.ResolveAnomalyResponse{
+		Success: true,
+		Message: "Anomaly resolved",
+	}, nil
+}
+
+// unixOrZero ゼロ値の時刻は0に変換
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
 // GetLogs ログを取得
 func (s *Server) GetLogs(ctx context.Context, req *pb.GetLogsRequest) (*pb.GetLogsResponse, error) {
 	if s.logger == nil {
 		return nil, fmt.Errorf("logger not initialized")
 	}
 
+	filter := filterHash(req.SortOrder, req.ReaderId, req.Level, req.CardId, req.CardRef,
+		fmt.Sprint(req.StartTime), fmt.Sprint(req.EndTime))
+
+	after, err := decodePageToken(req.PageToken, filter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// データベースからログを取得
-	logs, totalCount, err := s.logger.GetLogs(
-		req.ReaderId,
-		req.Level,
-		req.StartTime,
-		req.EndTime,
-		req.Limit,
-	)
+	logs, totalCount, next, err := s.logger.QueryLogs(&database.LogQuery{
+		ReaderID:  req.ReaderId,
+		Level:     req.Level,
+		CardID:    req.CardId,
+		CardRef:   req.CardRef,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Limit:     req.Limit,
+		Ascending: req.SortOrder == pb.SortOrder_SORT_ORDER_OLDEST_FIRST,
+		After:     after,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get logs: %w", err)
 	}
@@ -122,16 +389,21 @@ func (s *Server) GetLogs(ctx context.Context, req *pb.GetLogsRequest) (*pb.GetLo
 	pbLogs := make([]*pb.LogEntry, len(logs))
 	for i, logEntry := range logs {
 		pbLogs[i] = &pb.LogEntry{
+			Id:        logEntry.ID,
 			Timestamp: logEntry.Timestamp.Unix(),
 			Level:     logEntry.Level,
 			Message:   logEntry.Message,
 			ReaderId:  logEntry.ReaderID,
+			CardId:    logEntry.CardID,
+			CardRef:   logEntry.CardRef,
+			Attrs:     logEntry.Attrs,
 		}
 	}
 
 	return &pb.GetLogsResponse{
-		Logs:       pbLogs,
-		TotalCount: totalCount,
+		Logs:          pbLogs,
+		TotalCount:    totalCount,
+		NextPageToken: encodePageToken(next, filter),
 	}, nil
 }
 
@@ -141,14 +413,27 @@ func (s *Server) GetReadHistory(ctx context.Context, req *pb.GetReadHistoryReque
 		return nil, fmt.Errorf("logger not initialized")
 	}
 
+	filter := filterHash(req.SortOrder, req.ReaderId, req.Status, req.CardId, req.CardRef, req.CardType,
+		fmt.Sprint(req.StartTime), fmt.Sprint(req.EndTime))
+
+	after, err := decodePageToken(req.PageToken, filter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// データベースから履歴を取得
-	records, totalCount, err := s.logger.GetReadHistory(
-		req.ReaderId,
-		req.Status,
-		req.StartTime,
-		req.EndTime,
-		req.Limit,
-	)
+	records, totalCount, next, err := s.logger.QueryReadHistory(&database.ReadHistoryQuery{
+		ReaderID:  req.ReaderId,
+		Status:    req.Status,
+		CardID:    req.CardId,
+		CardRef:   req.CardRef,
+		CardType:  req.CardType,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Limit:     req.Limit,
+		Ascending: req.SortOrder == pb.SortOrder_SORT_ORDER_OLDEST_FIRST,
+		After:     after,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get read history: %w", err)
 	}
@@ -157,9 +442,11 @@ func (s *Server) GetReadHistory(ctx context.Context, req *pb.GetReadHistoryReque
 	pbEntries := make([]*pb.ReadHistoryEntry, len(records))
 	for i, record := range records {
 		pbEntries[i] = &pb.ReadHistoryEntry{
+			Id:           record.ID,
 			Timestamp:    record.Timestamp.Unix(),
 			ReaderId:     record.ReaderID,
 			CardId:       record.CardID,
+			CardRef:      record.CardRef,
 			CardType:     record.CardType,
 			Atr:          record.ATR,
 			ExpiryDate:   record.ExpiryDate,
@@ -171,8 +458,8 @@ func (s *Server) GetReadHistory(ctx context.Context, req *pb.GetReadHistoryReque
 	}
 
 	return &pb.GetReadHistoryResponse{
-		Entries:    pbEntries,
-		TotalCount: totalCount,
+		Entries:       pbEntries,
+		TotalCount:    totalCount,
+		NextPageToken: encodePageToken(next, filter),
 	}, nil
 }
-*/
@@ -0,0 +1,29 @@
+package license
+
+import (
+	"fmt"
+	"net/http"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthHandler grpc.health.v1の状態をHTTPで返す（HTTPのロードバランサー用）
+// SERVINGの場合は200、それ以外は503。?service=でサービスを指定できる
+func HealthHandler(checker healthpb.HealthServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := checker.Check(r.Context(), &healthpb.HealthCheckRequest{Service: r.URL.Query().Get("service")})
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		switch {
+		case err != nil:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, err)
+		case resp.Status != healthpb.HealthCheckResponse_SERVING:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, resp.Status)
+		default:
+			fmt.Fprintln(w, resp.Status)
+		}
+	})
+}
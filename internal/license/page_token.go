@@ -0,0 +1,66 @@
+package license
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"menkyo_go/internal/database"
+	pb "menkyo_go/proto/license"
+)
+
+// pageToken ページトークンの中身（クライアントには不透明な文字列として渡す）
+type pageToken struct {
+	Timestamp int64  `json:"t"`
+	ID        int64  `json:"i"`
+	Filter    uint32 `json:"f"` // 発行時の条件（条件を変えてトークンを使い回すのを防ぐ）
+}
+
+// filterHash 条件と並び順のハッシュ
+func filterHash(order pb.SortOrder, fields ...string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(order.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(fields, "\x00")))
+	return h.Sum32()
+}
+
+// encodePageToken 次のページのトークンを作成（続きがない場合は空）
+func encodePageToken(cursor *database.Cursor, filter uint32) string {
+	if cursor == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal(&pageToken{
+		Timestamp: cursor.Timestamp.Unix(),
+		ID:        cursor.ID,
+		Filter:    filter,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken トークンからページ位置を取得（空の場合はnil）
+func decodePageToken(token string, filter uint32) (*database.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page_token")
+	}
+
+	var t pageToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid page_token")
+	}
+
+	if t.Filter != filter {
+		return nil, fmt.Errorf("page_token does not match the request filters")
+	}
+
+	return &database.Cursor{Timestamp: time.Unix(t.Timestamp, 0), ID: t.ID}, nil
+}
@@ -0,0 +1,59 @@
+package license
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"menkyo_go/internal/database"
+	pb "menkyo_go/proto/license"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	cursor := &database.Cursor{Timestamp: time.Unix(1761466530, 0), ID: 42}
+	filter := filterHash(pb.SortOrder_SORT_ORDER_NEWEST_FIRST, "reader-1", "license")
+
+	token := encodePageToken(cursor, filter)
+	got, err := decodePageToken(token, filter)
+	if err != nil {
+		t.Fatalf("decodePageToken: %v", err)
+	}
+	if !got.Timestamp.Equal(cursor.Timestamp) || got.ID != cursor.ID {
+		t.Errorf("decodePageToken() = %+v, want %+v", got, cursor)
+	}
+
+	if token := encodePageToken(nil, filter); token != "" {
+		t.Errorf("encodePageToken(nil) = %q, want empty", token)
+	}
+	if got, err := decodePageToken("", filter); got != nil || err != nil {
+		t.Errorf("decodePageToken(\"\") = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestPageTokenFilterBinding(t *testing.T) {
+	cursor := &database.Cursor{Timestamp: time.Unix(1761466530, 0), ID: 42}
+	token := encodePageToken(cursor, filterHash(pb.SortOrder_SORT_ORDER_NEWEST_FIRST, "ab", "c"))
+
+	tests := []struct {
+		name    string
+		token   string
+		filter  uint32
+		wantErr bool
+	}{
+		{name: "same filter", token: token, filter: filterHash(pb.SortOrder_SORT_ORDER_NEWEST_FIRST, "ab", "c")},
+		{name: "different order", token: token, filter: filterHash(pb.SortOrder_SORT_ORDER_OLDEST_FIRST, "ab", "c"), wantErr: true},
+		{name: "different field", token: token, filter: filterHash(pb.SortOrder_SORT_ORDER_NEWEST_FIRST, "ab", "d"), wantErr: true},
+		{name: "shifted field boundary", token: token, filter: filterHash(pb.SortOrder_SORT_ORDER_NEWEST_FIRST, "a", "bc"), wantErr: true},
+		{name: "not base64", token: "!!!", filter: 0, wantErr: true},
+		{name: "not json", token: base64.RawURLEncoding.EncodeToString([]byte("cursor")), filter: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePageToken(tt.token, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodePageToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package license
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"menkyo_go/internal/logging"
+	"menkyo_go/internal/remoteconfig"
+	pb "menkyo_go/proto/license"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// configPollInterval 設定の変更を確認する間隔（設定はCLIからDBに直接書き込まれるため）
+const configPollInterval = 10 * time.Second
+
+// WatchConfig リーダーの設定を配信
+func (s *Server) WatchConfig(req *pb.WatchConfigRequest, stream pb.LicenseReader_WatchConfigServer) error {
+	return s.watchConfig(stream.Context(), req, stream.Send)
+}
+
+// watchConfig gRPCとConnectで共通のWatchConfig
+func (s *Server) watchConfig(ctx context.Context, req *pb.WatchConfigRequest, send func(*pb.ReaderConfig) error) error {
+	if s.logger == nil {
+		return fmt.Errorf("logger not initialized")
+	}
+	if req.ReaderId == "" {
+		return status.Error(codes.InvalidArgument, "reader_id is required")
+	}
+
+	slog.Info("WatchConfig subscribed", logging.KeyReaderID, req.ReaderId, "site", req.Site, "current_version", req.CurrentVersion)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	lastVersion := req.CurrentVersion
+	for {
+		config, err := s.effectiveConfig(req.ReaderId, req.Site)
+		if err != nil {
+			slog.Warn("WatchConfig failed to resolve config", logging.KeyReaderID, req.ReaderId, logging.Err(err))
+		} else if config.Version != lastVersion {
+			if err := send(config); err != nil {
+				return err
+			}
+			lastVersion = config.Version
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// effectiveConfig リーダーに適用する設定（拠点が空の場合は登録済みの拠点）
+func (s *Server) effectiveConfig(readerID, site string) (*pb.ReaderConfig, error) {
+	if site == "" {
+		readers, err := s.logger.ListReaders("")
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range readers {
+			if r.ReaderID == readerID {
+				site = r.Site
+			}
+		}
+	}
+
+	records, err := s.logger.ApplicableReaderConfigs(readerID, site)
+	if err != nil {
+		return nil, err
+	}
+
+	version, settings, err := remoteconfig.Effective(records)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedAt time.Time
+	for _, record := range records {
+		if record.CreatedAt.After(updatedAt) {
+			updatedAt = record.CreatedAt
+		}
+	}
+
+	return &pb.ReaderConfig{
+		Version:      version,
+		SettingsJson: settings.JSON(),
+		UpdatedAt:    unixOrZero(updatedAt),
+	}, nil
+}
+
+// ReportConfigStatus 設定の適用結果を記録
+func (s *Server) ReportConfigStatus(ctx context.Context, req *pb.ReportConfigStatusRequest) (*pb.ReportConfigStatusResponse, error) {
+	if s.logger == nil {
+		return nil, fmt.Errorf("logger not initialized")
+	}
+	if req.ReaderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reader_id is required")
+	}
+
+	if err := s.logger.ReportReaderConfigStatus(req.ReaderId, req.Version, req.Error); err != nil {
+		return nil, fmt.Errorf("failed to record config status: %w", err)
+	}
+
+	if req.Error != "" {
+		slog.Warn("Reader failed to apply config", logging.KeyReaderID, req.ReaderId, "version", req.Version, "error", req.Error)
+	} else {
+		slog.Info("Reader applied config", logging.KeyReaderID, req.ReaderId, "version", req.Version)
+	}
+
+	return &pb.ReportConfigStatusResponse{
+		Success: true,
+		Message: "Config status recorded",
+	}, nil
+}
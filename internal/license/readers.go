@@ -0,0 +1,184 @@
+package license
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/logging"
+	pb "menkyo_go/proto/license"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultReaderStaleAfter この時間ReaderHeartbeatがないリーダーを応答なしとする
+const DefaultReaderStaleAfter = 3 * time.Minute
+
+// SetReaderStaleAfter 応答なしと判定するまでの時間を設定
+func (s *Server) SetReaderStaleAfter(d time.Duration) {
+	if d > 0 {
+		s.staleAfter = d
+	}
+}
+
+// heartbeatInterval リーダーがReaderHeartbeatを送る間隔（応答なしの判定までに3回送る）
+func (s *Server) heartbeatInterval() int32 {
+	interval := s.staleAfter / 3
+	if interval < 10*time.Second {
+		interval = 10 * time.Second
+	}
+	return int32(interval / time.Second)
+}
+
+// addKnownReader 登録済みのリーダーとして記録
+func (s *Server) addKnownReader(readerID string) {
+	s.readersMu.Lock()
+	defer s.readersMu.Unlock()
+	s.knownReaders[readerID] = struct{}{}
+}
+
+// isKnownReader 登録済みのリーダーか
+func (s *Server) isKnownReader(readerID string) bool {
+	s.readersMu.RLock()
+	defer s.readersMu.RUnlock()
+	_, ok := s.knownReaders[readerID]
+	return ok
+}
+
+// RegisterReader リーダーを登録
+func (s *Server) RegisterReader(ctx context.Context, req *pb.RegisterReaderRequest) (*pb.RegisterReaderResponse, error) {
+	if s.logger == nil {
+		return nil, fmt.Errorf("logger not initialized")
+	}
+	if req.ReaderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reader_id is required")
+	}
+
+	record := &database.ReaderRecord{
+		ReaderID:   req.ReaderId,
+		Site:       req.Site,
+		Location:   req.Location,
+		Version:    req.Version,
+		BuildTime:  req.BuildTime,
+		NFCDevices: req.NfcDevices,
+		Hostname:   req.Hostname,
+		PeerAddr:   peerAddr(ctx),
+	}
+	if err := s.logger.RegisterReader(record); err != nil {
+		return nil, fmt.Errorf("failed to register reader: %w", err)
+	}
+	s.addKnownReader(req.ReaderId)
+
+	slog.Info("Reader registered", logging.KeyReaderID, req.ReaderId, "site", req.Site, "location", req.Location,
+		"version", req.Version, "build_time", req.BuildTime, "host", req.Hostname, "devices", len(req.NfcDevices))
+
+	return &pb.RegisterReaderResponse{
+		Success:                  true,
+		Message:                  "Reader registered",
+		HeartbeatIntervalSeconds: s.heartbeatInterval(),
+	}, nil
+}
+
+// ReaderHeartbeat リーダーの死活監視
+func (s *Server) ReaderHeartbeat(ctx context.Context, req *pb.ReaderHeartbeatRequest) (*pb.ReaderHeartbeatResponse, error) {
+	if s.logger == nil {
+		return nil, fmt.Errorf("logger not initialized")
+	}
+	if req.ReaderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reader_id is required")
+	}
+
+	found, wasStale, err := s.logger.TouchReader(req.ReaderId, req.NfcDevices, peerAddr(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reader: %w", err)
+	}
+	if !found {
+		// サーバーのDBが作り直された場合など。リーダーは再登録する
+		return nil, status.Errorf(codes.NotFound, "reader %s is not registered", req.ReaderId)
+	}
+	s.addKnownReader(req.ReaderId)
+
+	if wasStale {
+		slog.Info("Reader is responding again", logging.KeyReaderID, req.ReaderId)
+	}
+
+	return &pb.ReaderHeartbeatResponse{
+		Success:                  true,
+		Message:                  "OK",
+		HeartbeatIntervalSeconds: s.heartbeatInterval(),
+	}, nil
+}
+
+// ListReaders 登録済みのリーダー一覧を取得
+func (s *Server) ListReaders(ctx context.Context, req *pb.ListReadersRequest) (*pb.ListReadersResponse, error) {
+	if s.logger == nil {
+		return nil, fmt.Errorf("logger not initialized")
+	}
+
+	records, err := s.logger.ListReaders(req.Site)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list readers: %w", err)
+	}
+
+	// 監視の間隔に関わらず、一覧の時点での応答の有無を返す
+	cutoff := time.Now().Add(-s.staleAfter)
+
+	var readers []*pb.ReaderInfo
+	for _, record := range records {
+		stale := record.LastSeen.Before(cutoff)
+		if req.StaleOnly && !stale {
+			continue
+		}
+		readers = append(readers, &pb.ReaderInfo{
+			ReaderId:      record.ReaderID,
+			Site:          record.Site,
+			Location:      record.Location,
+			Version:       record.Version,
+			BuildTime:     record.BuildTime,
+			NfcDevices:    record.NFCDevices,
+			Hostname:      record.Hostname,
+			PeerAddr:      record.PeerAddr,
+			RegisteredAt:  unixOrZero(record.RegisteredAt),
+			LastSeen:      unixOrZero(record.LastSeen),
+			Stale:         stale,
+			StaleSince:    unixOrZero(record.StaleSince),
+			ConfigVersion: record.ConfigVersion,
+			ConfigError:   record.ConfigError,
+		})
+	}
+
+	return &pb.ListReadersResponse{
+		Readers:           readers,
+		StaleAfterSeconds: int32(s.staleAfter / time.Second),
+	}, nil
+}
+
+// MonitorReaders 応答のないリーダーを定期的に判定し、警告ログを記録する（stopが閉じられるまで）
+func (s *Server) MonitorReaders(stop <-chan struct{}) {
+	if s.logger == nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		stale, err := s.logger.MarkStaleReaders(time.Now().Add(-s.staleAfter))
+		if err != nil {
+			slog.Error("Failed to check stale readers", logging.Err(err))
+		}
+		for _, record := range stale {
+			slog.Warn("Reader not responding", logging.KeyReaderID, record.ReaderID,
+				"last_seen", record.LastSeen.Local().Format("2006-01-02 15:04:05"), "site", record.Site, "location", record.Location)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
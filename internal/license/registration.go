@@ -0,0 +1,75 @@
+package license
+
+import (
+	"fmt"
+	"time"
+
+	pb "menkyo_go/proto/license"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultHeartbeatInterval サーバーから間隔を受け取るまでのReaderHeartbeatの間隔
+const defaultHeartbeatInterval = time.Minute
+
+// RunRegistration リーダーを登録し、サーバーが指定する間隔でReaderHeartbeatを送る（stopが閉じられるまで）
+// サーバーに接続できない間は同じ間隔で再試行し、サーバーが登録を失っている場合は再登録する
+func (c *Client) RunRegistration(stop <-chan struct{}, reg *pb.RegisterReaderRequest, logf func(string)) {
+	registered := false
+	interval := defaultHeartbeatInterval
+	var lastErr string
+
+	for {
+		next, err := c.registerOrHeartbeat(reg, &registered)
+		if err != nil {
+			// 同じエラーが続く場合は記録しない
+			if msg := err.Error(); msg != lastErr {
+				logf(msg)
+				lastErr = msg
+			}
+		} else {
+			if lastErr != "" {
+				logf("Reader registration restored")
+			}
+			lastErr = ""
+			if next > 0 {
+				interval = next
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// registerOrHeartbeat 未登録なら登録、登録済みならReaderHeartbeatを送り、次の間隔を返す
+func (c *Client) registerOrHeartbeat(reg *pb.RegisterReaderRequest, registered *bool) (time.Duration, error) {
+	ctx, cancel := c.callContext()
+	defer cancel()
+
+	if *registered {
+		resp, err := c.client.ReaderHeartbeat(ctx, &pb.ReaderHeartbeatRequest{
+			ReaderId:   reg.ReaderId,
+			NfcDevices: reg.NfcDevices,
+		})
+		if err == nil {
+			return time.Duration(resp.HeartbeatIntervalSeconds) * time.Second, nil
+		}
+		if status.Code(err) != codes.NotFound {
+			return 0, fmt.Errorf("reader heartbeat failed: %w", err)
+		}
+		*registered = false
+	}
+
+	resp, err := c.client.RegisterReader(ctx, reg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register reader: %w", err)
+	}
+	*registered = true
+
+	return time.Duration(resp.HeartbeatIntervalSeconds) * time.Second, nil
+}
@@ -0,0 +1,75 @@
+package license
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"menkyo_go/internal/logging"
+	pb "menkyo_go/proto/license"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// CallbackSink 受信した免許証データの通知先
+type CallbackSink struct {
+	Callback func(*pb.LicenseData)
+	close    func() error
+}
+
+// Close 通知先を閉じる
+func (s *CallbackSink) Close() error {
+	if s.close == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// NewCallbackSink 設定値から通知先を作成
+//
+//	none          通知しない
+//	log           標準ログに出力
+//	jsonl:<path>  1行1件のJSONでファイルに追記
+func NewCallbackSink(spec string) (*CallbackSink, error) {
+	switch {
+	case spec == "" || spec == "none":
+		return &CallbackSink{}, nil
+
+	case spec == "log":
+		return &CallbackSink{
+			Callback: func(data *pb.LicenseData) {
+				slog.Debug("License data", logging.KeyCardID, data.CardId, "card_type", data.LicenseType, logging.KeyReaderID, data.ReaderId)
+			},
+		}, nil
+
+	case strings.HasPrefix(spec, "jsonl:"):
+		path := strings.TrimPrefix(spec, "jsonl:")
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open callback file: %w", err)
+		}
+
+		var mu sync.Mutex
+		return &CallbackSink{
+			Callback: func(data *pb.LicenseData) {
+				line, err := protojson.Marshal(data)
+				if err != nil {
+					slog.Error("Failed to marshal license data", logging.Err(err))
+					return
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				if _, err := file.Write(append(line, '\n')); err != nil {
+					slog.Error("Failed to write license data", logging.Err(err))
+				}
+			},
+			close: file.Close,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown callback sink: %s", spec)
+	}
+}
@@ -0,0 +1,66 @@
+package license
+
+import (
+	"context"
+
+	"menkyo_go/internal/tracing"
+	pb "menkyo_go/proto/license"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/otelconnect"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+)
+
+// untracedMethods 定期的に呼ばれるためトレースしないメソッド
+var untracedMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName:            true,
+	healthpb.Health_Watch_FullMethodName:            true,
+	pb.LicenseReader_ReaderHeartbeat_FullMethodName: true,
+}
+
+// ServerStatsHandler gRPCサーバーのトレース（リーダーからメタデータで伝搬したトレースを引き継ぐ）
+func ServerStatsHandler() stats.Handler {
+	return otelgrpc.NewServerHandler(otelgrpc.WithFilter(func(info *stats.RPCTagInfo) bool {
+		return !untracedMethods[info.FullMethodName]
+	}))
+}
+
+// ConnectTracingInterceptor Connectのハンドラーのトレース（ヘッダーで伝搬したトレースを引き継ぐ）
+func ConnectTracingInterceptor() (connect.Interceptor, error) {
+	return otelconnect.NewInterceptor(
+		otelconnect.WithTrustRemote(),
+		otelconnect.WithoutMetrics(),
+		otelconnect.WithFilter(func(_ context.Context, spec connect.Spec) bool {
+			return !untracedMethods[spec.Procedure]
+		}),
+	)
+}
+
+// newRequestID レスポンスのリクエストID
+// トレースされている場合はトレースIDを使い、リーダーのログ・サーバーのログ・トレースを同じIDで検索できるようにする
+func newRequestID(ctx context.Context) string {
+	requestID := tracing.TraceID(ctx)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("menkyo.request_id", requestID))
+	return requestID
+}
+
+// tracedCallsOnly トレース中の呼び出しのみスパンを作成してメタデータで伝搬する
+// （死活監視などの定期的な呼び出しはトレースしない）
+type tracedCallsOnly struct {
+	stats.Handler
+}
+
+func (h tracedCallsOnly) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return h.Handler.TagRPC(ctx, info)
+}
@@ -0,0 +1,205 @@
+package license
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/logging"
+	"menkyo_go/internal/tracing"
+	pb "menkyo_go/proto/license"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uploadCommitSize 一括送信を1つのトランザクションで記録する件数
+const uploadCommitSize = 100
+
+// licenseDataRecord 免許証データを読み取り履歴に変換
+func licenseDataRecord(data *pb.LicenseData) *database.ReadHistoryRecord {
+	return &database.ReadHistoryRecord{
+		ReaderID:    data.ReaderId,
+		CardID:      data.CardId,
+		CardType:    "driver_license",
+		ATR:         "", // ATRは含まれていない
+		ExpiryDate:  data.ExpiryDate,
+		RemainCount: "",
+		FeliCaUID:   "",
+		Status:      "success",
+		Timestamp:   time.Unix(data.ReadTimestamp, 0),
+	}
+}
+
+// readLogEntry 読み取りログをログに変換
+func readLogEntry(logData *pb.ReadLog) *database.LogEntry {
+	level := "INFO"
+	if logData.Status == "error" {
+		level = "ERROR"
+	}
+
+	message := fmt.Sprintf("Reader %s: %s", logData.ReaderId, logData.Status)
+	if logData.ErrorMessage != "" {
+		message += fmt.Sprintf(" - %s", logData.ErrorMessage)
+	}
+
+	return &database.LogEntry{
+		Timestamp: time.Unix(logData.Timestamp, 0),
+		Level:     level,
+		Message:   message,
+		ReaderID:  logData.ReaderId,
+		CardID:    logData.CardId,
+	}
+}
+
+// UploadBatch 未送信の読み取りをまとめて受信
+func (s *Server) UploadBatch(stream pb.LicenseReader_UploadBatchServer) error {
+	resp, err := s.uploadBatch(stream.Context(), stream.Recv)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+// uploadBatch gRPCとConnectで共通のUploadBatch
+// uploadCommitSize件ごとにトランザクションで記録するため、途中で切断されても記録済みの分は再送時に重複として無視される
+func (s *Server) uploadBatch(ctx context.Context, recv func() (*pb.UploadItem, error)) (*pb.UploadBatchResponse, error) {
+	if s.logger == nil {
+		return nil, fmt.Errorf("logger not initialized")
+	}
+
+	requestID := newRequestID(ctx)
+	resp := &pb.UploadBatchResponse{RequestId: requestID}
+
+	var readerID, source string
+	var entries []*database.UploadEntry
+	items := make(map[*database.UploadEntry]*pb.UploadItem)
+
+	commit := func() error {
+		if len(entries) == 0 {
+			return nil
+		}
+
+		acked, committed, err := s.logger.CommitUploads(readerID, source, entries)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to commit uploads: %v", err)
+		}
+
+		resp.AckedSeq = max(resp.AckedSeq, acked)
+		resp.Committed += int32(len(committed))
+		resp.Duplicates += int32(len(entries) - len(committed))
+
+		// 記録できたものだけ通知・配信（送信待ちに保存した時のタッチのトレースに関連付ける）
+		for _, entry := range committed {
+			seq := attribute.Int64("menkyo.upload_seq", entry.Seq)
+			switch item := items[entry].Item.(type) {
+			case *pb.UploadItem_LicenseData:
+				_, span := tracing.StartFromTraceParent(ctx, item.LicenseData.TraceParent, "license.UploadItem", seq)
+				s.publishLicenseData(item.LicenseData)
+				span.End()
+			case *pb.UploadItem_ReadLog:
+				_, span := tracing.StartFromTraceParent(ctx, item.ReadLog.TraceParent, "license.UploadItem", seq)
+				s.publishReadLog(item.ReadLog)
+				span.End()
+			}
+		}
+
+		entries = entries[:0]
+		clear(items)
+		return nil
+	}
+
+	for {
+		item, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// 切断された場合も受信済みの分は記録する
+			if commitErr := commit(); commitErr != nil {
+				slog.ErrorContext(ctx, "Failed to commit uploads", logging.KeyRequestID, requestID, logging.Err(commitErr))
+			}
+			return nil, err
+		}
+		resp.Received++
+
+		entry, err := uploadEntry(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if readerID == "" {
+			readerID, source = item.ReaderId, item.Source
+
+			// 全て重複の場合も確定済みの連番を返す
+			acked, err := s.logger.UploadProgress(readerID, source)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get upload progress: %v", err)
+			}
+			resp.AckedSeq = acked
+		} else if item.ReaderId != readerID || item.Source != source {
+			return nil, status.Error(codes.InvalidArgument, "reader_id and source must be the same within a stream")
+		}
+
+		entries = append(entries, entry)
+		items[entry] = item
+
+		if len(entries) >= uploadCommitSize {
+			if err := commit(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := commit(); err != nil {
+		return nil, err
+	}
+
+	if resp.Received > 0 {
+		slog.InfoContext(ctx, "Received upload batch", logging.KeyRequestID, requestID, logging.KeyReaderID, readerID,
+			"received", resp.Received, "committed", resp.Committed, "duplicates", resp.Duplicates, "acked_seq", resp.AckedSeq)
+	}
+
+	return resp, nil
+}
+
+// uploadEntry 一括送信の1件を検証して記録するエントリに変換
+func uploadEntry(item *pb.UploadItem) (*database.UploadEntry, error) {
+	if item.ReaderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reader_id is required")
+	}
+	if item.Seq <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "seq must be positive: %d", item.Seq)
+	}
+
+	entry := &database.UploadEntry{Seq: item.Seq}
+
+	switch v := item.Item.(type) {
+	case *pb.UploadItem_LicenseData:
+		// 認可はUploadItemのreader_idで判定するため、中身のリーダーIDも一致させる
+		if v.LicenseData.ReaderId == "" {
+			v.LicenseData.ReaderId = item.ReaderId
+		}
+		if v.LicenseData.ReaderId != item.ReaderId {
+			return nil, status.Errorf(codes.InvalidArgument, "seq %d: reader_id mismatch", item.Seq)
+		}
+		entry.Read = licenseDataRecord(v.LicenseData)
+	case *pb.UploadItem_ReadLog:
+		if v.ReadLog.ReaderId == "" {
+			v.ReadLog.ReaderId = item.ReaderId
+		}
+		if v.ReadLog.ReaderId != item.ReaderId {
+			return nil, status.Errorf(codes.InvalidArgument, "seq %d: reader_id mismatch", item.Seq)
+		}
+		entry.Log = readLogEntry(v.ReadLog)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "seq %d: item is empty", item.Seq)
+	}
+
+	return entry, nil
+}
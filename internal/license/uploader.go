@@ -0,0 +1,168 @@
+package license
+
+import (
+	"fmt"
+	"time"
+
+	"menkyo_go/internal/database"
+	pb "menkyo_go/proto/license"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	uploadBatchSize     = 500              // 1回のUploadBatchで送信する件数
+	uploadRetryInterval = 30 * time.Second // 送信待ちを確認する間隔（送信に失敗した場合の再試行間隔）
+)
+
+// Uploader 読み取りをリーダーのDBに保存してからサーバーに送信する
+// サーバーに接続できない間は送信待ちとして残し、再接続後にUploadBatchでまとめて送信する
+type Uploader struct {
+	client   *Client
+	logger   *database.Logger
+	readerID string
+	logf     func(string)
+	wake     chan struct{}
+}
+
+// NewUploader 新しいUploaderを作成
+func NewUploader(client *Client, logger *database.Logger, readerID string, logf func(string)) *Uploader {
+	return &Uploader{
+		client:   client,
+		logger:   logger,
+		readerID: readerID,
+		logf:     logf,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// EnqueueLicenseData 免許証データを送信待ちに追加
+func (u *Uploader) EnqueueLicenseData(data *pb.LicenseData) error {
+	return u.enqueue(database.UploadKindLicenseData, data)
+}
+
+// EnqueueReadLog 読み取りログを送信待ちに追加
+func (u *Uploader) EnqueueReadLog(logData *pb.ReadLog) error {
+	return u.enqueue(database.UploadKindReadLog, logData)
+}
+
+func (u *Uploader) enqueue(kind string, msg proto.Message) error {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", kind, err)
+	}
+	if err := u.logger.EnqueueUpload(kind, payload); err != nil {
+		return err
+	}
+
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run 送信待ちをサーバーに送信する（stopが閉じられるまで）
+func (u *Uploader) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(uploadRetryInterval)
+	defer ticker.Stop()
+
+	var lastErr string
+	for {
+		sent, err := u.Drain()
+		if err != nil {
+			// 同じエラーが続く場合は記録しない
+			if msg := err.Error(); msg != lastErr {
+				u.logf(msg)
+				lastErr = msg
+			}
+		} else if lastErr != "" {
+			u.logf(fmt.Sprintf("Upload restored (%d items sent)", sent))
+			lastErr = ""
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-u.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain 送信待ちがなくなるまでUploadBatchで送信し、送信した件数を返す
+func (u *Uploader) Drain() (int, error) {
+	source, err := u.logger.UploadSource()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for {
+		pending, err := u.logger.GetPendingUploads(uploadBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(pending) == 0 {
+			return total, nil
+		}
+		lastSeq := pending[len(pending)-1].Seq
+
+		items := make([]*pb.UploadItem, 0, len(pending))
+		for _, p := range pending {
+			item, err := u.uploadItem(source, p)
+			if err != nil {
+				// 読み込めないデータは送らない（後続の連番が確定した時点で送信済みになる）
+				u.logf(fmt.Sprintf("Skipping upload %d: %v", p.Seq, err))
+				continue
+			}
+			items = append(items, item)
+		}
+
+		acked := lastSeq
+		if len(items) > 0 {
+			resp, err := u.client.UploadBatch(items)
+			if err != nil {
+				return total, err
+			}
+			acked = resp.AckedSeq
+		}
+
+		if err := u.logger.MarkUploadsSent(acked); err != nil {
+			return total, err
+		}
+		total += len(items)
+
+		if acked < lastSeq {
+			return total, fmt.Errorf("upload acknowledged up to %d of %d", acked, lastSeq)
+		}
+	}
+}
+
+// uploadItem 送信待ちをUploadItemに変換
+func (u *Uploader) uploadItem(source string, p *database.PendingUpload) (*pb.UploadItem, error) {
+	item := &pb.UploadItem{
+		ReaderId: u.readerID,
+		Source:   source,
+		Seq:      p.Seq,
+	}
+
+	switch p.Kind {
+	case database.UploadKindLicenseData:
+		data := &pb.LicenseData{}
+		if err := proto.Unmarshal(p.Payload, data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal license data: %w", err)
+		}
+		item.Item = &pb.UploadItem_LicenseData{LicenseData: data}
+	case database.UploadKindReadLog:
+		logData := &pb.ReadLog{}
+		if err := proto.Unmarshal(p.Payload, logData); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal read log: %w", err)
+		}
+		item.Item = &pb.UploadItem_ReadLog{ReadLog: logData}
+	default:
+		return nil, fmt.Errorf("unknown upload kind: %s", p.Kind)
+	}
+
+	return item, nil
+}
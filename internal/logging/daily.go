@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DailyFile 日付が変わると新しいファイルに書き込むio.Writer（<dir>/<prefix>_YYYYMMDD.log）
+type DailyFile struct {
+	dir    string
+	prefix string
+
+	mu   sync.Mutex
+	date string
+	file *os.File
+}
+
+// NewDailyFile 新しいDailyFileを作成（ファイルは最初の書き込みで開く）
+func NewDailyFile(dir, prefix string) *DailyFile {
+	return &DailyFile{dir: dir, prefix: prefix}
+}
+
+// Write 今日のファイルに追記
+func (d *DailyFile) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	date := time.Now().Format("20060102")
+	if d.file == nil || d.date != date {
+		if err := d.open(date); err != nil {
+			return 0, err
+		}
+	}
+	return d.file.Write(p)
+}
+
+// Path 今日のファイルのパス
+func (d *DailyFile) Path() string {
+	return filepath.Join(d.dir, fmt.Sprintf("%s_%s.log", d.prefix, time.Now().Format("20060102")))
+}
+
+// open dateのファイルを開き、前日のファイルを閉じる
+func (d *DailyFile) open(date string) error {
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(d.dir, fmt.Sprintf("%s_%s.log", d.prefix, date)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	if d.file != nil {
+		d.file.Close()
+	}
+	d.file, d.date = f, date
+	return nil
+}
+
+// Close ファイルを閉じる
+func (d *DailyFile) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/pii"
+)
+
+// DBHandler logsテーブルに記録するslog.Handler
+// reader_id・card_idは列に、その他の属性（request_idなど）はattrsにJSONで保存する
+// card_idの列はdatabase.Loggerが仮名（card_ref）にし、attrsの属性は保存する前に秘匿化する
+type DBHandler struct {
+	logger *database.Logger
+	level  slog.Leveler
+	redact redactor
+	attrs  []slog.Attr // WithAttrsで追加した属性（グループ名を付けたキー）
+	group  string      // WithGroupのグループ名（"a.b."の形式）
+}
+
+// NewDBHandler 新しいDBHandlerを作成（levelがnilの場合はINFO以上。cipherがnilの場合はカードの識別子を仮名にしない）
+func NewDBHandler(logger *database.Logger, level slog.Leveler, cipher *pii.Cipher) *DBHandler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &DBHandler{logger: logger, level: level, redact: redactor{cipher: cipher}}
+}
+
+// Enabled levelが最低レベル以上か
+func (h *DBHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle 1行をlogsテーブルに記録
+func (h *DBHandler) Handle(_ context.Context, r slog.Record) error {
+	var readerID, cardID string
+	fields := make(map[string]any)
+
+	add := func(key string, v slog.Value) {
+		switch key {
+		case KeyReaderID:
+			readerID = v.String()
+		case KeyCardID:
+			cardID = v.String()
+		default:
+			// グループの中のcard_idなどもキーで判定して秘匿化する
+			leaf := leafKey(key)
+			a := h.redact.attr(slog.Attr{Key: leaf, Value: v})
+			fields[strings.TrimSuffix(key, leaf)+a.Key] = attrValue(a.Value)
+		}
+	}
+	for _, a := range h.attrs {
+		flatten("", a, add)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flatten(h.group, a, add)
+		return true
+	})
+
+	var attrs string
+	if len(fields) > 0 {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		attrs = string(data)
+	}
+
+	return h.logger.LogMessageWithAttrs(LevelName(r.Level), r.Message, readerID, cardID, attrs)
+}
+
+// WithAttrs 属性を追加したハンドラー
+func (h *DBHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + a.Key
+		}
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup 以降の属性のキーにグループ名を付けたハンドラー
+func (h *DBHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// flatten グループの属性を"group.key"のキーに展開して渡す
+func flatten(prefix string, a slog.Attr, add func(string, slog.Value)) {
+	v := a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flatten(p, ga, add)
+		}
+		return
+	}
+	add(prefix+a.Key, v)
+}
+
+// leafKey "group.key"のグループ名を除いたキー
+func leafKey(key string) string {
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+// attrValue JSONにする値（エラーは文字列にする）
+func attrValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time()
+	}
+
+	switch x := v.Any().(type) {
+	case error:
+		return x.Error()
+	case json.Marshaler:
+		return x
+	case interface{ String() string }:
+		return x.String()
+	default:
+		if _, err := json.Marshal(x); err != nil {
+			return v.String()
+		}
+		return x
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Fanout 複数のハンドラーに同じレコードを渡すslog.Handler
+type Fanout struct {
+	handlers []slog.Handler
+}
+
+// NewFanout 新しいFanoutを作成
+func NewFanout(handlers ...slog.Handler) *Fanout {
+	return &Fanout{handlers: handlers}
+}
+
+// Enabled いずれかのハンドラーがlevelを出力するか
+func (f *Fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle levelを出力する全てのハンドラーに渡す（1つが失敗しても他には渡す）
+func (f *Fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs 全てのハンドラーに属性を追加
+func (f *Fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return &Fanout{handlers: handlers}
+}
+
+// WithGroup 全てのハンドラーにグループを追加
+func (f *Fanout) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+	return &Fanout{handlers: handlers}
+}
@@ -0,0 +1,129 @@
+// Package logging log/slogのハンドラー（コンソール・日付ごとのファイル・DBのlogsテーブルに出力する）
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/pii"
+)
+
+// 属性のキー（DBのlogsテーブルではreader_id・card_idは列に、request_idはattrsに保存する）
+// card_idはPII_KEYを設定している場合、全ての出力先でcard_ref（仮名）に置き換える
+const (
+	KeyReaderID  = "reader_id"
+	KeyCardID    = "card_id"
+	KeyCardRef   = "card_ref"
+	KeyRequestID = "request_id"
+)
+
+// Options ログの出力先
+type Options struct {
+	Level      *slog.LevelVar   // 全ての出力先に共通の最低レベル（LOG_LEVEL）
+	Console    io.Writer        // コンソール（nilの場合は出力しない）
+	Dir        string           // 日付ごとのファイルの保存先（空の場合は出力しない）
+	FilePrefix string           // ファイル名の接頭辞（<Dir>/<FilePrefix>_YYYYMMDD.log）
+	DB         *database.Logger // logsテーブル（nilの場合は記録しない）
+	Cipher     *pii.Cipher      // カードの識別子を仮名にする鍵束（nilの場合は識別子を[redacted]に置き換える）
+}
+
+// Setup 出力先をまとめたLoggerを作成してslogとlogパッケージのデフォルトにする
+// 全ての出力先でカードの識別子を仮名（PII_KEYを設定していない場合は[redacted]）に、有効期限・FeliCaのIDmを[redacted]に置き換える
+// 戻り値のio.Closerで日付ごとのファイルを閉じる
+func Setup(opts Options) (*slog.Logger, io.Closer) {
+	redact := redactor{cipher: opts.Cipher}
+	handlerOpts := &slog.HandlerOptions{
+		Level: opts.Level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			return redact.replaceAttr(groups, replaceLevel(groups, a))
+		},
+	}
+
+	var handlers []slog.Handler
+	var closer io.Closer = nopCloser{}
+	if opts.Console != nil {
+		handlers = append(handlers, slog.NewTextHandler(opts.Console, handlerOpts))
+	}
+	if opts.Dir != "" {
+		file := NewDailyFile(opts.Dir, opts.FilePrefix)
+		handlers = append(handlers, slog.NewJSONHandler(file, handlerOpts))
+		closer = file
+	}
+	if opts.DB != nil {
+		handlers = append(handlers, NewDBHandler(opts.DB, opts.Level, opts.Cipher))
+	}
+
+	// logパッケージの出力（log.Printfなど）もINFOとして同じ出力先に送られる
+	logger := slog.New(NewFanout(handlers...))
+	slog.SetDefault(logger)
+
+	return logger, closer
+}
+
+// ParseLevel LOG_LEVELの値をslogのレベルに変換（DEBUG / INFO / WARNING / ERROR）
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return slog.LevelDebug, nil
+	case "INFO":
+		return slog.LevelInfo, nil
+	case "WARNING", "WARN":
+		return slog.LevelWarn, nil
+	case "ERROR":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level: %s", level)
+	}
+}
+
+// LevelName slogのレベルをDBのログレベルの名前に変換
+func LevelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// SetLevel 全ての出力先の最低レベルを変更（リーダーはサーバーから配信された設定で変更する）
+func SetLevel(v *slog.LevelVar, db *database.Logger, level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	v.Set(l)
+	if db != nil {
+		return db.SetLogLevel(LevelName(l))
+	}
+	return nil
+}
+
+// replaceLevel コンソール・ファイルのレベルの表記をDBと揃える（WARN→WARNING）
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelName(level))
+		}
+	}
+	return a
+}
+
+// Err エラーの属性（キーはerror）
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String("error", "")
+	}
+	return slog.String("error", err.Error())
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
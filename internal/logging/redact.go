@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"log/slog"
+
+	"menkyo_go/internal/pii"
+)
+
+// redactor 出力する前にカードの識別子を仮名（card_refなど）に、有効期限・FeliCaのIDmを[redacted]に置き換える
+// cipherがnilの場合（PII_KEYを設定していない場合）はカードの識別子も[redacted]に置き換える
+type redactor struct {
+	cipher *pii.Cipher
+}
+
+// attr 秘匿化した属性（グループの中の属性もキーで判定する）
+func (r redactor) attr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = r.attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	}
+
+	key, value, ok := r.cipher.RedactAttr(a.Key, v.String())
+	if !ok {
+		return slog.Attr{Key: a.Key, Value: v}
+	}
+	return slog.String(key, value)
+}
+
+// replaceAttr slog.HandlerOptions.ReplaceAttrで属性を秘匿化（コンソール・ファイル）
+func (r redactor) replaceAttr(_ []string, a slog.Attr) slog.Attr {
+	return r.attr(a)
+}
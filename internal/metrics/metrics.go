@@ -0,0 +1,98 @@
+// Package metrics Prometheusのメトリクス（/metricsで公開する）
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace メトリクス名の接頭辞
+const namespace = "menkyo"
+
+// registry リーダー・サーバーそれぞれで使うメトリクスのみ登録する（RegisterReader/RegisterServer）
+var registry = prometheus.NewRegistry()
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Register メトリクスを追加で登録（gRPCサーバーのメトリクスなど）
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// Handler /metricsのハンドラー
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// Serve addrで/metricsを公開する（リーダー用。サーバーはConnectのHTTPポートで公開する）
+func Serve(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+
+	return server, nil
+}
+
+// RegisterOutbox 送信待ちの件数を取得する関数を登録（件数は取得時にDBから数える）
+func RegisterOutbox(outbox string, count func() (int, error)) error {
+	return Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "outbox_pending",
+		Help:        "Records waiting to be sent by outbox (upload, punch, assignment, webhook).",
+		ConstLabels: prometheus.Labels{"outbox": outbox},
+	}, countFunc(count)))
+}
+
+// RegisterDBWriter ログ・読み取り履歴の非同期書き込みのキューの件数と破棄した件数を取得する関数を登録
+func RegisterDBWriter(queued func() int, dropped func() uint64) error {
+	return Register(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_write_queue_length",
+			Help:      "Log and read history rows waiting in the asynchronous SQLite write queue.",
+		}, func() float64 { return float64(queued()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_write_dropped_total",
+			Help:      "Log rows dropped because the asynchronous SQLite write queue was full.",
+		}, func() float64 { return float64(dropped()) }),
+	)
+}
+
+// countFunc 件数をGaugeFuncの値にする（取得できない場合はNaN）
+func countFunc(count func() (int, error)) func() float64 {
+	return func() float64 {
+		n, err := count()
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(n)
+	}
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// リーダーのメトリクス
+var (
+	// ReaderReads 読み取りの件数（card_type: driver_license/car_inspection/other/unknown、status: success/error）
+	ReaderReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "reads_total",
+		Help:      "Card reads by card type and status.",
+	}, []string{"card_type", "status"})
+
+	// ReadRetries MonitorCardsで読み取りを再試行した回数
+	ReadRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "read_retries_total",
+		Help:      "Card read retries in the MonitorCards loop.",
+	})
+
+	// MonitorErrors MonitorCardsでカードの状態の取得に失敗した回数（1秒後に再試行する）
+	MonitorErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "monitor_errors_total",
+		Help:      "Card state wait errors in the MonitorCards loop.",
+	})
+
+	// APDUDuration APDUコマンドの応答時間（ins: 命令バイト、result: ok/error）
+	APDUDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "apdu_duration_seconds",
+		Help:      "APDU command latency by instruction byte and result.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"ins", "result"})
+
+	// WoffSvHeartbeats woff-svへのHeartbeatの結果（result: ok/error）
+	WoffSvHeartbeats = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "woffsv_heartbeats_total",
+		Help:      "woff-sv heartbeats by result.",
+	}, []string{"result"})
+
+	// WoffSvHeartbeatDuration woff-svへのHeartbeatの応答時間
+	WoffSvHeartbeatDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "woffsv_heartbeat_duration_seconds",
+		Help:      "woff-sv heartbeat latency.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// WoffSvUp 最後のwoff-svへのHeartbeatが成功したか（1: 成功、0: 失敗）
+	WoffSvUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reader",
+		Name:      "woffsv_up",
+		Help:      "Whether the last woff-sv heartbeat succeeded.",
+	})
+)
+
+// RegisterReader リーダーのメトリクスを登録
+func RegisterReader() error {
+	return Register(ReaderReads, ReadRetries, MonitorErrors, APDUDuration,
+		WoffSvHeartbeats, WoffSvHeartbeatDuration, WoffSvUp)
+}
+
+// ObserveHeartbeat woff-svへのHeartbeatの結果を記録
+func ObserveHeartbeat(seconds float64, err error) {
+	WoffSvHeartbeatDuration.Observe(seconds)
+	if err != nil {
+		WoffSvHeartbeats.WithLabelValues("error").Inc()
+		WoffSvUp.Set(0)
+		return
+	}
+	WoffSvHeartbeats.WithLabelValues("ok").Inc()
+	WoffSvUp.Set(1)
+}
+
+// CardTypeLabel ラベル用のカード種別（不明な場合はunknown）
+func CardTypeLabel(cardType string) string {
+	if cardType == "" {
+		return "unknown"
+	}
+	return cardType
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// サーバーのメトリクス
+var (
+	// ServerReads 受信した読み取りの件数（PushLicenseData/PushReadLog/UploadBatch）
+	// ラベルはリーダーから送られた値のため、CountServerReadで想定外の値をotherにまとめてから使う
+	ServerReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "server",
+		Name:      "reads_total",
+		Help:      "Card reads received from readers by reader, card type and status.",
+	}, []string{"reader_id", "card_type", "status"})
+)
+
+// RegisterServer サーバーのメトリクスを登録
+func RegisterServer() error {
+	return Register(ServerReads)
+}
+
+// RegisterWebhookDeadLetters 再送の上限に達したWebhookの件数を取得する関数を登録
+func RegisterWebhookDeadLetters(count func() (int, error)) error {
+	return Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "server",
+		Name:      "webhook_dead_letters",
+		Help:      "Webhook deliveries that gave up after the maximum number of attempts.",
+	}, countFunc(count)))
+}
+
+// LabelOther 想定外の値をまとめるラベル
+const LabelOther = "other"
+
+// serverCardTypes サーバーのメトリクスで区別するカード種別
+var serverCardTypes = map[string]bool{"driver_license": true, "car_inspection": true, "other": true, "unknown": true}
+
+// serverReadStatuses サーバーのメトリクスで区別する読み取りのステータス
+var serverReadStatuses = map[string]bool{"success": true, "error": true}
+
+// CountServerRead 受信した読み取りを数える
+// 登録されていないリーダー・想定外のカード種別やステータスはotherにまとめる（任意の値で系列が増えないように）
+func CountServerRead(readerID string, registered bool, cardType, status string) {
+	if !registered {
+		readerID = LabelOther
+	}
+	cardType = CardTypeLabel(cardType)
+	if !serverCardTypes[cardType] {
+		cardType = LabelOther
+	}
+	if !serverReadStatuses[status] {
+		status = LabelOther
+	}
+	ServerReads.WithLabelValues(readerID, cardType, status).Inc()
+}
@@ -0,0 +1,8 @@
+package nfc
+
+// カード種別（Windows以外でも参照できるよう独立したファイルに定義）
+const (
+	CardTypeDriverLicense = "driver_license"
+	CardTypeCarInspection = "car_inspection"
+	CardTypeOther         = "other"
+)
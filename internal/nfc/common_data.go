@@ -0,0 +1,78 @@
+package nfc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// 共通データ要素（EF 2F01）のタグ
+const (
+	commonDataTagDates = 0x45 // 仕様書バージョン番号・交付年月日・有効期間の満了日
+)
+
+// CommonData 免許証の共通データ要素
+type CommonData struct {
+	SpecVersion string    // 仕様書バージョン番号
+	IssueDate   time.Time // 交付年月日
+	ExpiryDate  time.Time // 有効期間の満了日
+}
+
+// ParseCommonData CMD_READ_EXPIRE_DFで読み取った共通データ要素（16進数）を解析
+// LicenseData.ExpiryDateの値をそのまま渡す
+func ParseCommonData(expiryHex string) (*CommonData, error) {
+	raw, err := hex.DecodeString(expiryHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid common data: %w", err)
+	}
+
+	// TLVを順に読み、日付のタグを探す
+	for i := 0; i+2 <= len(raw); {
+		tag, length := raw[i], int(raw[i+1])
+		value := raw[i+2:]
+		if length > len(value) {
+			return nil, fmt.Errorf("truncated common data: tag %02X", tag)
+		}
+		value = value[:length]
+
+		if tag == commonDataTagDates {
+			if length < 11 {
+				return nil, fmt.Errorf("invalid common data length: %d", length)
+			}
+
+			issueDate, err := parseBCDDate(value[3:7])
+			if err != nil {
+				return nil, fmt.Errorf("invalid issue date: %w", err)
+			}
+			expiryDate, err := parseBCDDate(value[7:11])
+			if err != nil {
+				return nil, fmt.Errorf("invalid expiry date: %w", err)
+			}
+
+			return &CommonData{
+				SpecVersion: string(value[0:3]),
+				IssueDate:   issueDate,
+				ExpiryDate:  expiryDate,
+			}, nil
+		}
+
+		i += 2 + length
+	}
+
+	return nil, fmt.Errorf("date tag not found in common data")
+}
+
+// parseBCDDate BCD形式のYYYYMMDD（4バイト）を解析
+func parseBCDDate(b []byte) (time.Time, error) {
+	s := hex.EncodeToString(b)
+	t, err := time.ParseInLocation("20060102", s, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// Expired 有効期間の満了日を過ぎているか（満了日当日は有効）
+func (d *CommonData) Expired(now time.Time) bool {
+	return now.After(d.ExpiryDate.AddDate(0, 0, 1))
+}
@@ -3,11 +3,17 @@
 package nfc
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"strings"
 	"syscall"
 	"time"
+
+	"menkyo_go/internal/metrics"
+	"menkyo_go/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // APDUコマンド定義
@@ -43,13 +49,6 @@ const (
 // 免許証ATRのプレフィックス
 const DRIVER_LICENSE_ATR_PREFIX = "3B888001000000"
 
-// カード種別
-const (
-	CardTypeDriverLicense  = "driver_license"
-	CardTypeCarInspection = "car_inspection"
-	CardTypeOther         = "other"
-)
-
 // LicenseData 免許証データ
 type LicenseData struct {
 	CardID          string
@@ -121,7 +120,7 @@ func (lr *LicenseReader) ReadCard(readerName string) (*LicenseData, error) {
 	if errIdm == nil && sw1Idm == 0x90 && sw2Idm == 0x00 && len(idmResp) >= 8 {
 		// 8バイト: FeliCa IDm（固有ID）
 		data.FeliCaUID = hex.EncodeToString(idmResp[:8])
-		lr.log(fmt.Sprintf("FeliCa IDm: %s", data.FeliCaUID))
+		lr.log("FeliCa IDm read")
 	} else if errIdm == nil && sw1Idm == 0x90 && sw2Idm == 0x00 && len(idmResp) == 4 {
 		// 4バイト: ランダムUID → Mobile FeliCa（固定IDm取得は不可）
 		data.FeliCaUID = hex.EncodeToString(idmResp)
@@ -215,14 +214,15 @@ func (lr *LicenseReader) readDriverLicenseData(card *Card, data *LicenseData) er
 
 	if sw1 == 0x90 && sw2 == 0x00 {
 		data.ExpiryDate = hex.EncodeToString(expireResp)
-		lr.log(fmt.Sprintf("Expiry date (hex): %s", data.ExpiryDate))
+		lr.log("Expiry date read")
 	}
 
 	return nil
 }
 
 // MonitorCards カード挿入を監視
-func (lr *LicenseReader) MonitorCards(callback func(*LicenseData, error)) error {
+// コールバックのctxには1回のタッチのスパン（card.tap）が含まれる
+func (lr *LicenseReader) MonitorCards(callback func(context.Context, *LicenseData, error)) error {
 	readers, err := lr.ListReaders()
 	if err != nil {
 		return fmt.Errorf("failed to list readers: %w", err)
@@ -250,6 +250,7 @@ func (lr *LicenseReader) MonitorCards(callback func(*LicenseData, error)) error
 		err := lr.context.WaitForCardChangeWithStates(states, 1000)
 		if err != nil {
 			lr.log(fmt.Sprintf("WaitForCardChange error: %v", err))
+			metrics.MonitorErrors.Inc()
 			time.Sleep(1 * time.Second)
 			continue
 		}
@@ -280,6 +281,8 @@ func (lr *LicenseReader) MonitorCards(callback func(*LicenseData, error)) error
 
 				lr.log(fmt.Sprintf("Reading card on: %s", readerName))
 
+				ctx, tapSpan := tracing.Start(context.Background(), "card.tap", attribute.String("nfc.reader", readerName))
+
 				// 最大3回リトライ
 				var data *LicenseData
 				var readErr error
@@ -289,6 +292,7 @@ func (lr *LicenseReader) MonitorCards(callback func(*LicenseData, error)) error
 				for retry := 0; retry < maxRetries; retry++ {
 					if retry > 0 {
 						lr.log(fmt.Sprintf("Retry %d/%d", retry, maxRetries-1))
+						metrics.ReadRetries.Inc()
 						time.Sleep(500 * time.Millisecond)
 
 						// リトライ前にカードがまだ存在するか確認
@@ -304,7 +308,12 @@ func (lr *LicenseReader) MonitorCards(callback func(*LicenseData, error)) error
 						}
 					}
 
+					_, readSpan := tracing.Start(ctx, "nfc.ReadCard", attribute.Int("nfc.attempt", retry+1))
 					data, readErr = lr.ReadCard(readerName)
+					if data != nil {
+						readSpan.SetAttributes(attribute.String("card.type", data.CardType))
+					}
+					tracing.End(readSpan, readErr)
 
 					// 成功判定：エラーがなく、免許証の場合はExpiryDateがある
 					if readErr == nil {
@@ -340,7 +349,11 @@ func (lr *LicenseReader) MonitorCards(callback func(*LicenseData, error)) error
 				if !successRead && readErr == nil {
 					readErr = fmt.Errorf("failed to read complete data after %d attempts", maxRetries)
 				}
-				callback(data, readErr)
+				if data != nil {
+					tapSpan.SetAttributes(attribute.String("card.type", data.CardType))
+				}
+				callback(ctx, data, readErr)
+				tracing.End(tapSpan, readErr)
 
 				// 状態をリセット
 				states[i].CurrentState = state.EventState
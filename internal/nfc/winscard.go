@@ -5,7 +5,10 @@ package nfc
 import (
 	"fmt"
 	"syscall"
+	"time"
 	"unsafe"
+
+	"menkyo_go/internal/metrics"
 )
 
 var (
@@ -267,6 +270,7 @@ func (c *Card) Transmit(apdu []byte) ([]byte, byte, byte, error) {
 		pioSendPci = &g_rgSCardT1Pci
 	}
 
+	start := time.Now()
 	ret, _, _ := procTransmit.Call(
 		c.handle,
 		uintptr(unsafe.Pointer(pioSendPci)),
@@ -276,6 +280,7 @@ func (c *Card) Transmit(apdu []byte) ([]byte, byte, byte, error) {
 		uintptr(unsafe.Pointer(&recvBuf[0])),
 		uintptr(unsafe.Pointer(&recvLen)),
 	)
+	observeAPDU(apdu, time.Since(start), ret == 0)
 
 	if ret != 0 {
 		return nil, 0, 0, fmt.Errorf("SCardTransmit failed: 0x%X", ret)
@@ -360,3 +365,16 @@ func (c *Context) WaitForCardChangeWithStates(states []ReaderState, timeout uint
 
 	return nil
 }
+
+// observeAPDU APDUコマンドの応答時間を命令バイト（INS）ごとに記録
+func observeAPDU(apdu []byte, elapsed time.Duration, ok bool) {
+	ins := "unknown"
+	if len(apdu) >= 2 {
+		ins = fmt.Sprintf("%02X", apdu[1])
+	}
+	result := "ok"
+	if !ok {
+		result = "error"
+	}
+	metrics.APDUDuration.WithLabelValues(ins, result).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,180 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// prefix 暗号化した値の接頭辞（enc:<鍵ID>:<base64(nonce+暗号文)>）
+const prefix = "enc:"
+
+// ErrNoKey 暗号化された値を読み出したが鍵束が設定されていない
+var ErrNoKey = errors.New("value is encrypted but no PII key is configured")
+
+// Cipher 個人データの列の暗号化・復号とキー付きハッシュ
+// nilの場合は暗号化せず、平文のまま保存・読み出しする
+type Cipher struct {
+	active int
+	aeads  map[int]cipher.AEAD
+	index  []byte
+	ref    []byte // 仮名（card_ref）の鍵（検索用のハッシュの鍵から導出する）
+}
+
+// NewCipher 鍵束からCipherを作成
+func NewCipher(k *Keyring) (*Cipher, error) {
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{
+		active: k.Active,
+		aeads:  make(map[int]cipher.AEAD, len(k.Keys)),
+		index:  k.Index,
+		ref:    hmacSum(k.Index, []byte("card_ref")),
+	}
+	for id, key := range k.Keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		c.aeads[id] = aead
+	}
+	return c, nil
+}
+
+// Encrypt 暗号化に使う鍵で値を暗号化（空の値は空のまま）
+// fieldは"table.column"で、別の列に値を移し替えても復号できないように認証データにする
+func (c *Cipher) Encrypt(field, plaintext string) (string, error) {
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := c.aeads[c.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+
+	return prefix + strconv.Itoa(c.active) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 暗号化した値を復号（暗号化していない値はそのまま返す）
+func (c *Cipher) Decrypt(field, value string) (string, error) {
+	keyID, sealed, ok, err := parse(value)
+	if err != nil || !ok {
+		return value, err
+	}
+	if c == nil {
+		return "", ErrNoKey
+	}
+
+	aead, found := c.aeads[keyID]
+	if !found {
+		return "", fmt.Errorf("failed to decrypt %s: key %d not found in keyring", field, keyID)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt %s: value too short", field)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// Index 検索用のキー付きハッシュ（HMAC-SHA256の16進数。nilまたは空の値の場合は空）
+// 同じ値は常に同じハッシュになるため、暗号化した列の代わりにハッシュの列で一致を検索する
+func (c *Cipher) Index(value string) string {
+	if c == nil || value == "" {
+		return ""
+	}
+	return hex.EncodeToString(hmacSum(c.index, []byte(value)))
+}
+
+// Ref ログなどに記録するカードの識別子の仮名（HMAC-SHA256の先頭16バイトの16進数。nilまたは空の値の場合は空）
+// 同じカードは常に同じ仮名になるため、識別子を記録せずにログと読み取り履歴を紐付けられる
+// 検索用のハッシュとは別の鍵を使うため、仮名からcard_id_hashの値は分からない
+func (c *Cipher) Ref(value string) string {
+	if c == nil || value == "" {
+		return ""
+	}
+	return hex.EncodeToString(hmacSum(c.ref, []byte(value))[:16])
+}
+
+// NeedsReencrypt 値が平文、または暗号化に使う鍵以外で暗号化されているか
+func (c *Cipher) NeedsReencrypt(value string) bool {
+	if c == nil || value == "" {
+		return false
+	}
+	keyID, _, ok, err := parse(value)
+	if err != nil || !ok {
+		return true
+	}
+	return keyID != c.active
+}
+
+// ActiveKey 暗号化に使う鍵のID
+func (c *Cipher) ActiveKey() int {
+	if c == nil {
+		return 0
+	}
+	return c.active
+}
+
+// IsEncrypted 暗号化した値か
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID 暗号化した値の鍵ID（暗号化していない値は0）
+func KeyID(value string) int {
+	keyID, _, ok, err := parse(value)
+	if err != nil || !ok {
+		return 0
+	}
+	return keyID
+}
+
+// hmacSum HMAC-SHA256
+func hmacSum(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// parse 暗号化した値を鍵IDとnonce+暗号文に分ける（暗号化していない値はok=false）
+func parse(value string) (int, []byte, bool, error) {
+	if !IsEncrypted(value) {
+		return 0, nil, false, nil
+	}
+
+	id, encoded, found := strings.Cut(value[len(prefix):], ":")
+	if !found {
+		return 0, nil, false, errors.New("invalid encrypted value")
+	}
+	keyID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, nil, false, errors.New("invalid encrypted value: bad key id")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, nil, false, errors.New("invalid encrypted value: bad encoding")
+	}
+	return keyID, sealed, true, nil
+}
@@ -0,0 +1,182 @@
+package pii
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const field = "read_history.card_id"
+
+// testKeyring 固定の鍵の鍵束
+func testKeyring() *Keyring {
+	return &Keyring{
+		Active: 1,
+		Keys:   map[int][]byte{1: bytes.Repeat([]byte{1}, keySize)},
+		Index:  bytes.Repeat([]byte{9}, keySize),
+	}
+}
+
+func newTestCipher(t *testing.T, k *Keyring) *Cipher {
+	t.Helper()
+	c, err := NewCipher(k)
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t, testKeyring())
+
+	for _, value := range []string{"", "012345678901", "山田 太郎", "enc-like:value"} {
+		encrypted, err := c.Encrypt(field, value)
+		if err != nil {
+			t.Fatalf("Encrypt(%q): %v", value, err)
+		}
+		if value == "" {
+			if encrypted != "" {
+				t.Errorf("Encrypt(\"\") = %q, want empty", encrypted)
+			}
+			continue
+		}
+		if !strings.HasPrefix(encrypted, "enc:1:") || strings.Contains(encrypted, value) {
+			t.Errorf("Encrypt(%q) = %q, want ciphertext with key 1", value, encrypted)
+		}
+
+		decrypted, err := c.Decrypt(field, encrypted)
+		if err != nil || decrypted != value {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q, %v", value, decrypted, err)
+		}
+	}
+
+	// 平文はそのまま読み出す
+	if got, err := c.Decrypt(field, "012345678901"); err != nil || got != "012345678901" {
+		t.Errorf("Decrypt(plaintext) = %q, %v", got, err)
+	}
+}
+
+func TestCipherNil(t *testing.T) {
+	var c *Cipher
+
+	if got, err := c.Encrypt(field, "012345678901"); err != nil || got != "012345678901" {
+		t.Errorf("nil Encrypt() = %q, %v, want plaintext", got, err)
+	}
+	if got, err := c.Decrypt(field, "012345678901"); err != nil || got != "012345678901" {
+		t.Errorf("nil Decrypt(plaintext) = %q, %v, want plaintext", got, err)
+	}
+
+	encrypted, err := newTestCipher(t, testKeyring()).Encrypt(field, "012345678901")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := c.Decrypt(field, encrypted); !errors.Is(err, ErrNoKey) {
+		t.Errorf("nil Decrypt(encrypted) error = %v, want ErrNoKey", err)
+	}
+	if c.Index("012345678901") != "" || c.Ref("012345678901") != "" {
+		t.Error("nil Index/Ref should be empty")
+	}
+}
+
+func TestCipherDecryptErrors(t *testing.T) {
+	c := newTestCipher(t, testKeyring())
+	encrypted, err := c.Encrypt(field, "012345678901")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	// 暗号文の途中の1文字を書き換える
+	tampered := []byte(encrypted)
+	if i := len(tampered) / 2; tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		value string
+	}{
+		{name: "other column", field: "registered_cards.card_id", value: encrypted},
+		{name: "unknown key", field: field, value: strings.Replace(encrypted, "enc:1:", "enc:7:", 1)},
+		{name: "tampered", field: field, value: string(tampered)},
+		{name: "missing separator", field: field, value: "enc:1"},
+		{name: "bad key id", field: field, value: "enc:x:AAAA"},
+		{name: "bad encoding", field: field, value: "enc:1:!!!"},
+		{name: "too short", field: field, value: "enc:1:AAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := c.Decrypt(tt.field, tt.value); err == nil {
+				t.Errorf("Decrypt() = %q, want error", got)
+			}
+		})
+	}
+}
+
+func TestCipherRotation(t *testing.T) {
+	k := testKeyring()
+	old := newTestCipher(t, k)
+	oldValue, err := old.Encrypt(field, "012345678901")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	id, err := k.Rotate()
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if id != 2 || k.Active != 2 {
+		t.Fatalf("Rotate() = %d (active %d), want 2", id, k.Active)
+	}
+	c := newTestCipher(t, k)
+
+	newValue, err := c.Encrypt(field, "012345678901")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		value     string
+		keyID     int
+		reencrypt bool
+		plaintext string
+	}{
+		{name: "old key", value: oldValue, keyID: 1, reencrypt: true, plaintext: "012345678901"},
+		{name: "active key", value: newValue, keyID: 2, reencrypt: false, plaintext: "012345678901"},
+		{name: "plaintext", value: "012345678901", keyID: 0, reencrypt: true, plaintext: "012345678901"},
+		{name: "empty", value: "", keyID: 0, reencrypt: false, plaintext: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeyID(tt.value); got != tt.keyID {
+				t.Errorf("KeyID() = %d, want %d", got, tt.keyID)
+			}
+			if got := c.NeedsReencrypt(tt.value); got != tt.reencrypt {
+				t.Errorf("NeedsReencrypt() = %v, want %v", got, tt.reencrypt)
+			}
+			if got, err := c.Decrypt(field, tt.value); err != nil || got != tt.plaintext {
+				t.Errorf("Decrypt() = %q, %v, want %q", got, err, tt.plaintext)
+			}
+		})
+	}
+
+	// 検索用のハッシュと仮名は鍵を入れ替えても変わらない
+	if old.Index("012345678901") != c.Index("012345678901") || old.Ref("012345678901") != c.Ref("012345678901") {
+		t.Error("Index/Ref changed after rotation")
+	}
+	if c.Ref("012345678901") == c.Index("012345678901")[:32] {
+		t.Error("Ref should use a key separate from Index")
+	}
+
+	// 古い鍵を削除すると古い値は復号できない
+	delete(k.Keys, 1)
+	if _, err := newTestCipher(t, k).Decrypt(field, oldValue); err == nil {
+		t.Error("Decrypt() with removed key succeeded, want error")
+	}
+}
@@ -0,0 +1,110 @@
+// Package pii 個人データ（免許証の番号・有効期限など）の列の暗号化（AES-GCM）とキー付きハッシュ・ログに出力する仮名
+package pii
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// keySize AES-256・HMAC-SHA256の鍵の長さ（バイト）
+const keySize = 32
+
+// Keyring 暗号化の鍵束
+// 鍵を入れ替えても古い鍵で暗号化した値を復号できるように、全ての鍵を残す
+type Keyring struct {
+	Active int            `json:"active"` // 暗号化に使う鍵のID
+	Keys   map[int][]byte `json:"keys"`   // 鍵ID → AES-256の鍵
+	Index  []byte         `json:"index"`  // 検索用のキー付きハッシュ（HMAC-SHA256）の鍵（入れ替えない）
+}
+
+// GenerateKeyring 新しい鍵束を作成（鍵ID 1を使う）
+func GenerateKeyring() (*Keyring, error) {
+	key, err := randomKey()
+	if err != nil {
+		return nil, err
+	}
+	index, err := randomKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{
+		Active: 1,
+		Keys:   map[int][]byte{1: key},
+		Index:  index,
+	}, nil
+}
+
+// ParseKeyring JSONの鍵束を読み込む
+func ParseKeyring(data []byte) (*Keyring, error) {
+	var k Keyring
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring: %w", err)
+	}
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Marshal 鍵束をJSONにする
+func (k *Keyring) Marshal() ([]byte, error) {
+	return json.MarshalIndent(k, "", "  ")
+}
+
+// Rotate 新しい鍵を追加して暗号化に使う鍵にする（新しい鍵IDを返す）
+// 既存の値はcmd/piikey reencryptで新しい鍵で暗号化し直す
+func (k *Keyring) Rotate() (int, error) {
+	key, err := randomKey()
+	if err != nil {
+		return 0, err
+	}
+
+	id := 0
+	for _, existing := range k.KeyIDs() {
+		if existing > id {
+			id = existing
+		}
+	}
+	id++
+
+	k.Keys[id] = key
+	k.Active = id
+	return id, nil
+}
+
+// KeyIDs 鍵IDの一覧（昇順）
+func (k *Keyring) KeyIDs() []int {
+	ids := make([]int, 0, len(k.Keys))
+	for id := range k.Keys {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// validate 鍵の長さと暗号化に使う鍵があるかを確認
+func (k *Keyring) validate() error {
+	if len(k.Index) != keySize {
+		return fmt.Errorf("invalid keyring: index key must be %d bytes", keySize)
+	}
+	for id, key := range k.Keys {
+		if len(key) != keySize {
+			return fmt.Errorf("invalid keyring: key %d must be %d bytes", id, keySize)
+		}
+	}
+	if _, ok := k.Keys[k.Active]; !ok {
+		return fmt.Errorf("invalid keyring: active key %d not found", k.Active)
+	}
+	return nil
+}
+
+// randomKey ランダムな鍵を作成
+func randomKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
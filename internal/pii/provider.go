@@ -0,0 +1,146 @@
+package pii
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrReadOnly 鍵束を保存できない（環境変数の場合は表示された鍵束を設定し直す）
+var ErrReadOnly = errors.New("key provider is read-only")
+
+// ErrNotFound 鍵束がまだ作成されていない
+var ErrNotFound = errors.New("keyring not found")
+
+// KeyProvider 鍵束の保存先
+type KeyProvider interface {
+	Load() (*Keyring, error)
+	Save(k *Keyring) error
+	String() string
+}
+
+// ParseProvider PII_KEYの値から鍵束の保存先を作成（空またはoffの場合はnil）
+//
+//	file:<path>      JSONの鍵束のファイル
+//	env:<name>       JSONの鍵束を設定した環境変数
+//	keystore:<name>  ユーザーごとのキーストア（OSのキーストアの代わりにユーザー設定ディレクトリに保存する）
+func ParseProvider(spec string) (KeyProvider, error) {
+	if spec == "" || spec == "off" {
+		return nil, nil
+	}
+
+	kind, value, ok := strings.Cut(spec, ":")
+	if !ok || value == "" {
+		return nil, fmt.Errorf("invalid PII key provider: %s (expected file:<path>, env:<name> or keystore:<name>)", spec)
+	}
+
+	switch kind {
+	case "file":
+		return &FileProvider{Path: value}, nil
+	case "env":
+		return &EnvProvider{Name: value}, nil
+	case "keystore":
+		dir, err := keystoreDir()
+		if err != nil {
+			return nil, err
+		}
+		return &FileProvider{Path: filepath.Join(dir, value+".json"), name: "keystore:" + value}, nil
+	default:
+		return nil, fmt.Errorf("unknown PII key provider: %s", kind)
+	}
+}
+
+// Open PII_KEYの鍵束を読み込んでCipherを作成（空またはoffの場合はnil。暗号化しない）
+func Open(spec string) (*Cipher, error) {
+	provider, err := ParseProvider(spec)
+	if err != nil || provider == nil {
+		return nil, err
+	}
+
+	keyring, err := provider.Load()
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(keyring)
+}
+
+// FileProvider JSONの鍵束のファイル（所有者のみ読み書きできるように保存する）
+type FileProvider struct {
+	Path string
+	name string // 表示名（keystoreの場合）
+}
+
+// Load ファイルから読み込む
+func (p *FileProvider) Load() (*Keyring, error) {
+	data, err := os.ReadFile(p.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring: %w", err)
+	}
+	return ParseKeyring(data)
+}
+
+// Save 一時ファイルに書き込んでから置き換える（途中で止まっても古い鍵束が残る）
+func (p *FileProvider) Save(k *Keyring) error {
+	data, err := k.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create keyring directory: %w", err)
+	}
+
+	tmp := p.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keyring: %w", err)
+	}
+	if err := os.Rename(tmp, p.Path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write keyring: %w", err)
+	}
+	return nil
+}
+
+func (p *FileProvider) String() string {
+	if p.name != "" {
+		return p.name
+	}
+	return "file:" + p.Path
+}
+
+// EnvProvider JSONの鍵束を設定した環境変数（保存はできない）
+type EnvProvider struct {
+	Name string
+}
+
+// Load 環境変数から読み込む
+func (p *EnvProvider) Load() (*Keyring, error) {
+	value := os.Getenv(p.Name)
+	if value == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+	return ParseKeyring([]byte(value))
+}
+
+// Save 環境変数には保存できないためErrReadOnlyを返す
+func (p *EnvProvider) Save(*Keyring) error {
+	return ErrReadOnly
+}
+
+func (p *EnvProvider) String() string {
+	return "env:" + p.Name
+}
+
+// keystoreDir キーストアの保存先（Windowsは%AppData%\menkyo_go\keystore）
+func keystoreDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate keystore: %w", err)
+	}
+	return filepath.Join(dir, "menkyo_go", "keystore"), nil
+}
@@ -0,0 +1,34 @@
+package pii
+
+// Redacted ログに出力しない値の置き換え
+const Redacted = "[redacted]"
+
+// cardKeys ログの属性のうちカードの識別子のキー → 仮名のキー
+var cardKeys = map[string]string{
+	"card_id":         "card_ref",
+	"license_card_id": "license_card_ref",
+	"vehicle_card_id": "vehicle_card_ref",
+}
+
+// secretKeys ログの属性のうち値を出力しないキー（紐付けにも使わない個人データ）
+var secretKeys = map[string]bool{
+	"expiry_date": true,
+	"felica_uid":  true,
+}
+
+// RedactAttr ログの属性のキーと値を秘匿化した結果（対象外の属性はokがfalse）
+// カードの識別子は仮名（*_ref）に置き換え、nilの場合は仮名を作れないためRedactedに置き換える
+// （免許証のカードの識別子は共通データから作るため、有効期限と同じく出力しない）
+// 有効期限・FeliCaのIDmは鍵に関係なく常にRedactedに置き換える
+func (c *Cipher) RedactAttr(key, value string) (string, string, bool) {
+	if secretKeys[key] {
+		return key, Redacted, true
+	}
+	if refKey, found := cardKeys[key]; found {
+		if c == nil || value == Redacted {
+			return key, Redacted, true
+		}
+		return refKey, c.Ref(value), true
+	}
+	return key, value, false
+}
@@ -0,0 +1,78 @@
+package remoteconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Applied 適用済みの設定
+type Applied struct {
+	Version   int64     `json:"version"`
+	Settings  *Settings `json:"settings"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Cache 最後に適用できた設定をファイルに保存する（サーバーに接続できない起動時に使う）
+type Cache struct {
+	path string
+}
+
+// NewCache 新しいCacheを作成
+func NewCache(path string) *Cache {
+	return &Cache{path: path}
+}
+
+// Load 保存済みの設定を読み込む（ファイルがない場合はnil）
+func (c *Cache) Load() (*Applied, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config cache: %w", err)
+	}
+
+	applied := &Applied{}
+	if err := json.Unmarshal(data, applied); err != nil {
+		return nil, fmt.Errorf("failed to parse config cache: %w", err)
+	}
+	if applied.Settings == nil {
+		applied.Settings = &Settings{}
+	}
+	if err := applied.Settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// Save 設定を保存（書き込み途中で停止しても壊れないよう一時ファイルから置き換える）
+func (c *Cache) Save(applied *Applied) error {
+	data, err := json.MarshalIndent(applied, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create config cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to replace config cache: %w", err)
+	}
+
+	return nil
+}
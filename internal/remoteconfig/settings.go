@@ -0,0 +1,134 @@
+// Package remoteconfig サーバーから配信するリーダーの設定
+package remoteconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"menkyo_go/internal/database"
+)
+
+// 打刻の種類の決め方
+const (
+	PunchStateIn   = "in"   // 常に出勤
+	PunchStateOut  = "out"  // 常に退勤
+	PunchStateAuto = "auto" // 当日の直前の打刻が出勤なら退勤、それ以外は出勤
+)
+
+// logLevels 設定できるログレベル
+var logLevels = []string{"DEBUG", "INFO", "WARNING", "ERROR"}
+
+// Settings リーダーの設定（nilの項目は下位の設定またはリーダーの.envの値を使う）
+type Settings struct {
+	DedupCooldownSeconds *int    `json:"dedup_cooldown_seconds,omitempty"` // 同じカードの連続タッチを無視する時間（秒、0で無効）
+	PunchState           *string `json:"punch_state,omitempty"`            // 打刻の種類の決め方（in / out / auto）
+	LogLevel             *string `json:"log_level,omitempty"`              // DBに記録するログレベル
+	WoffSvURL            *string `json:"woff_sv_url,omitempty"`            // woff-svのURL（woff-clで取得したURLより優先）
+}
+
+// Parse JSONの設定を読み込み検証する（未知の項目はエラー）
+func Parse(data string) (*Settings, error) {
+	s := &Settings{}
+	if strings.TrimSpace(data) == "" {
+		return s, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Validate 設定の値を検証
+func (s *Settings) Validate() error {
+	if s.DedupCooldownSeconds != nil && (*s.DedupCooldownSeconds < 0 || *s.DedupCooldownSeconds > 3600) {
+		return fmt.Errorf("dedup_cooldown_seconds must be between 0 and 3600")
+	}
+
+	if s.PunchState != nil {
+		switch *s.PunchState {
+		case PunchStateIn, PunchStateOut, PunchStateAuto:
+		default:
+			return fmt.Errorf("unknown punch_state: %s", *s.PunchState)
+		}
+	}
+
+	if s.LogLevel != nil {
+		valid := false
+		for _, level := range logLevels {
+			if *s.LogLevel == level {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown log_level: %s (expected one of %s)", *s.LogLevel, strings.Join(logLevels, ", "))
+		}
+	}
+
+	if s.WoffSvURL != nil && *s.WoffSvURL != "" {
+		u, err := url.Parse(*s.WoffSvURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("woff_sv_url must be an http(s) URL: %s", *s.WoffSvURL)
+		}
+	}
+
+	return nil
+}
+
+// JSON 設定をJSONに変換
+func (s *Settings) JSON() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// Merge overrideで設定されている項目を上書きした設定を返す
+func (s *Settings) Merge(override *Settings) *Settings {
+	merged := *s
+	if override.DedupCooldownSeconds != nil {
+		merged.DedupCooldownSeconds = override.DedupCooldownSeconds
+	}
+	if override.PunchState != nil {
+		merged.PunchState = override.PunchState
+	}
+	if override.LogLevel != nil {
+		merged.LogLevel = override.LogLevel
+	}
+	if override.WoffSvURL != nil {
+		merged.WoffSvURL = override.WoffSvURL
+	}
+	return &merged
+}
+
+// Effective 全体・拠点・リーダーの順に並んだ設定を重ねる
+// バージョンは重ねた設定の最大値（削除も含むため、いずれかが変更されると増える）
+func Effective(records []*database.ReaderConfigRecord) (int64, *Settings, error) {
+	var version int64
+	settings := &Settings{}
+
+	for _, record := range records {
+		if record.Version > version {
+			version = record.Version
+		}
+		if record.Deleted() {
+			continue
+		}
+
+		layer, err := Parse(record.Settings)
+		if err != nil {
+			return 0, nil, fmt.Errorf("%s %q version %d: %w", record.Scope, record.Target, record.Version, err)
+		}
+		settings = settings.Merge(layer)
+	}
+
+	return version, settings, nil
+}
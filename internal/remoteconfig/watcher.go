@@ -0,0 +1,119 @@
+package remoteconfig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "menkyo_go/proto/license"
+)
+
+// Source 設定の配信元（license.Client）
+type Source interface {
+	WatchConfig(ctx context.Context, req *pb.WatchConfigRequest, handle func(*pb.ReaderConfig)) error
+	ReportConfigStatus(readerID string, version int64, errMsg string) error
+}
+
+// Watcher サーバーから設定を受信して適用し、適用できた設定をキャッシュに保存する
+type Watcher struct {
+	source   Source
+	cache    *Cache
+	readerID string
+	site     string
+	apply    func(*Settings) error
+	logf     func(string)
+
+	mu      sync.Mutex
+	version int64
+}
+
+// NewWatcher 新しいWatcherを作成
+// applyは受信した設定を適用する（エラーの場合は前回の設定のまま）
+func NewWatcher(source Source, cache *Cache, readerID, site string, apply func(*Settings) error, logf func(string)) *Watcher {
+	return &Watcher{
+		source:   source,
+		cache:    cache,
+		readerID: readerID,
+		site:     site,
+		apply:    apply,
+		logf:     logf,
+	}
+}
+
+// LoadCached キャッシュの設定を適用（サーバーに接続する前に呼ぶ）
+func (w *Watcher) LoadCached() {
+	applied, err := w.cache.Load()
+	if err != nil {
+		w.logf(fmt.Sprintf("Ignoring cached config: %v", err))
+		return
+	}
+	if applied == nil {
+		return
+	}
+
+	if err := w.apply(applied.Settings); err != nil {
+		w.logf(fmt.Sprintf("Failed to apply cached config version %d: %v", applied.Version, err))
+		return
+	}
+
+	w.mu.Lock()
+	w.version = applied.Version
+	w.mu.Unlock()
+	w.logf(fmt.Sprintf("Applied cached config version %d", applied.Version))
+}
+
+// Version 適用済みの設定のバージョン
+func (w *Watcher) Version() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.version
+}
+
+// Run サーバーから設定を受信し続ける（ctxがキャンセルされるまで）
+func (w *Watcher) Run(ctx context.Context) error {
+	return w.source.WatchConfig(ctx, &pb.WatchConfigRequest{
+		ReaderId:       w.readerID,
+		Site:           w.site,
+		CurrentVersion: w.Version(),
+	}, w.handle)
+}
+
+func (w *Watcher) handle(config *pb.ReaderConfig) {
+	if config.Version == w.Version() {
+		return
+	}
+
+	errMsg := ""
+	if err := w.applyConfig(config); err != nil {
+		errMsg = err.Error()
+		w.logf(fmt.Sprintf("Failed to apply config version %d: %v", config.Version, err))
+	} else {
+		w.logf(fmt.Sprintf("Applied config version %d: %s", config.Version, config.SettingsJson))
+	}
+
+	if err := w.source.ReportConfigStatus(w.readerID, config.Version, errMsg); err != nil {
+		w.logf(fmt.Sprintf("Failed to report config status: %v", err))
+	}
+}
+
+func (w *Watcher) applyConfig(config *pb.ReaderConfig) error {
+	settings, err := Parse(config.SettingsJson)
+	if err != nil {
+		return err
+	}
+
+	if err := w.apply(settings); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.version = config.Version
+	w.mu.Unlock()
+
+	if err := w.cache.Save(&Applied{Version: config.Version, Settings: settings, AppliedAt: time.Now()}); err != nil {
+		w.logf(fmt.Sprintf("Failed to save config cache: %v", err))
+	}
+
+	return nil
+}
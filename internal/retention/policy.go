@@ -0,0 +1,129 @@
+// Package retention テーブル・ログレベルごとの保持期間と、期間を過ぎた行の削除
+package retention
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"menkyo_go/internal/database"
+)
+
+// Off 保持期間を設定しない（RETENTION）
+const Off = "off"
+
+// Rule 1つのテーブル（logsはレベルごと）の保持期間
+type Rule struct {
+	Table  string
+	Level  string // logsのみ（空の場合は個別に設定していないレベル）
+	MaxAge time.Duration
+}
+
+// Name 設定での名前（logs.DEBUG、read_historyなど）
+func (r Rule) Name() string {
+	if r.Level != "" {
+		return r.Table + "." + r.Level
+	}
+	return r.Table
+}
+
+// Policy 保持期間の一覧（設定のないテーブルは削除しない）
+type Policy []Rule
+
+// ParsePolicy "logs.DEBUG=7d,logs.INFO=90d,read_history=3y"の形式の保持期間を解析
+// 期間はd（日）・y（365日）の単位で指定する。offまたは空の場合は何も削除しない
+func ParsePolicy(s string) (Policy, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, Off) {
+		return nil, nil
+	}
+
+	var policy Policy
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid retention %q (expected table[.LEVEL]=<n>d|<n>y)", item)
+		}
+
+		table, level, _ := strings.Cut(strings.TrimSpace(key), ".")
+		level = strings.ToUpper(level)
+		if !slices.Contains(database.PrunableTables(), table) {
+			return nil, fmt.Errorf("unknown retention table: %s (available: %s)", table, strings.Join(database.PrunableTables(), ", "))
+		}
+		if level != "" && (table != "logs" || !database.IsLogLevel(level)) {
+			return nil, fmt.Errorf("invalid retention level: %s (only logs.DEBUG, logs.INFO, logs.WARNING, logs.ERROR)", key)
+		}
+
+		maxAge, err := parseAge(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention for %s: %w", key, err)
+		}
+
+		rule := Rule{Table: table, Level: level, MaxAge: maxAge}
+		if seen[rule.Name()] {
+			return nil, fmt.Errorf("duplicate retention for %s", rule.Name())
+		}
+		seen[rule.Name()] = true
+		policy = append(policy, rule)
+	}
+
+	return policy, nil
+}
+
+// parseAge "7d"・"3y"を期間に変換
+func parseAge(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+
+	switch s[len(s)-1] {
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'y':
+		return time.Duration(n) * 365 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid period %q (use d or y)", s)
+	}
+}
+
+// String 設定の形式に戻す（起動時のログ用）
+func (p Policy) String() string {
+	if len(p) == 0 {
+		return Off
+	}
+
+	items := make([]string, len(p))
+	for i, r := range p {
+		days := int(r.MaxAge / (24 * time.Hour))
+		if days%365 == 0 {
+			items[i] = fmt.Sprintf("%s=%dy", r.Name(), days/365)
+		} else {
+			items[i] = fmt.Sprintf("%s=%dd", r.Name(), days)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// levelsWithRule logsでレベルごとの保持期間を設定したレベル
+func (p Policy) levelsWithRule() []string {
+	var levels []string
+	for _, r := range p {
+		if r.Table == "logs" && r.Level != "" {
+			levels = append(levels, r.Level)
+		}
+	}
+	return levels
+}
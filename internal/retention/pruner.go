@@ -0,0 +1,178 @@
+package retention
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"menkyo_go/internal/database"
+)
+
+// startDelay 起動直後の処理と重ならないように最初の削除を遅らせる時間
+const startDelay = time.Minute
+
+// Pruner 定期的に保持期間を過ぎた行を削除し、空いた領域を解放する
+type Pruner struct {
+	logger     *database.Logger
+	policy     Policy
+	interval   time.Duration
+	archiveDir string // 空の場合はアーカイブしない
+	logf       func(string)
+}
+
+// NewPruner 新しいPrunerを作成
+// archiveDirを指定した場合は、削除する行を<archiveDir>/<table>-<日時>.jsonl.gzに書き出してから削除する
+func NewPruner(logger *database.Logger, policy Policy, interval time.Duration, archiveDir string, logf func(string)) *Pruner {
+	if logf == nil {
+		logf = func(string) {}
+	}
+	return &Pruner{
+		logger:     logger,
+		policy:     policy,
+		interval:   interval,
+		archiveDir: archiveDir,
+		logf:       logf,
+	}
+}
+
+// Run stopが閉じられるまでintervalごとにRunOnceを実行
+func (p *Pruner) Run(stop <-chan struct{}) {
+	if len(p.policy) == 0 {
+		return
+	}
+
+	timer := time.NewTimer(startDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+
+		if err := p.RunOnce(time.Now()); err != nil {
+			p.logf(fmt.Sprintf("Retention pruning failed: %v", err))
+		}
+		timer.Reset(p.interval)
+	}
+}
+
+// RunOnce 保持期間を過ぎた行を削除してincremental_vacuumを実行
+func (p *Pruner) RunOnce(now time.Time) error {
+	// 既存のデータベースは最初の1回だけ作り直す
+	converted, err := p.logger.EnableIncrementalVacuum()
+	if err != nil {
+		return err
+	}
+	if converted {
+		p.logf("Enabled incremental vacuum (database rebuilt with VACUUM)")
+	}
+
+	var deleted int64
+	for _, rule := range p.policy {
+		spec := database.PruneSpec{
+			Table:  rule.Table,
+			Before: now.Add(-rule.MaxAge),
+			Level:  rule.Level,
+		}
+		if rule.Table == "logs" && rule.Level == "" {
+			spec.ExcludeLevels = p.policy.levelsWithRule()
+		}
+
+		n, err := p.prune(spec, rule, now)
+		deleted += n
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			p.logf(fmt.Sprintf("Pruned %d rows from %s (older than %s)", n, rule.Name(), spec.Before.Format("2006-01-02 15:04")))
+		}
+	}
+
+	if deleted == 0 {
+		return nil
+	}
+
+	pages, err := p.logger.IncrementalVacuum()
+	if err != nil {
+		return err
+	}
+	p.logf(fmt.Sprintf("Incremental vacuum released %d pages", pages))
+
+	return nil
+}
+
+// prune 1つの保持期間の行を削除（アーカイブする場合はファイルに書き出してから削除）
+func (p *Pruner) prune(spec database.PruneSpec, rule Rule, now time.Time) (int64, error) {
+	if p.archiveDir == "" {
+		return p.logger.Prune(spec, nil)
+	}
+
+	archive := &archiveFile{path: filepath.Join(p.archiveDir, fmt.Sprintf("%s-%s.jsonl.gz", rule.Name(), now.Format("20060102-150405")))}
+	n, err := p.logger.Prune(spec, archive.write)
+	if closeErr := archive.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if n > 0 {
+		p.logf(fmt.Sprintf("Archived %d rows from %s to %s", n, rule.Name(), archive.path))
+	}
+	return n, err
+}
+
+// archiveFile 削除する行を1行1レコードのJSON（gzip）で書き出す（最初の書き込みで作成する）
+type archiveFile struct {
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+// write 行を書き出してディスクに同期する（削除をコミットする前に書き出しを確定させる）
+func (a *archiveFile) write(rows []map[string]any) error {
+	if a.file == nil {
+		if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+			return fmt.Errorf("failed to create archive directory: %w", err)
+		}
+		f, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open archive file: %w", err)
+		}
+		a.file = f
+		a.gz = gzip.NewWriter(f)
+		a.enc = json.NewEncoder(a.gz)
+	}
+
+	for _, row := range rows {
+		for column, value := range row {
+			// TEXTの列は[]byteで読み出される場合がある（BLOBなどUTF-8でない値はbase64のまま）
+			if b, ok := value.([]byte); ok && utf8.Valid(b) {
+				row[column] = string(b)
+			}
+		}
+		if err := a.enc.Encode(row); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+
+	if err := a.gz.Flush(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return a.file.Sync()
+}
+
+// close gzipを閉じてファイルを閉じる（書き出していない場合は何もしない）
+func (a *archiveFile) close() error {
+	if a.file == nil {
+		return nil
+	}
+	err := a.gz.Close()
+	if closeErr := a.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
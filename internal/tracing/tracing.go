@@ -0,0 +1,145 @@
+// Package tracing OpenTelemetryのトレース（1回のタッチの読み取り・記録・送信を関連付ける）
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// トレースの出力先（TRACING_EXPORTER）
+const (
+	ExporterNone = "none" // 出力しない（受信したトレースの伝搬のみ行う）
+	ExporterOTLP = "otlp" // OTLP/gRPC（送信先はOTEL_EXPORTER_OTLP_ENDPOINT、デフォルトlocalhost:4317）
+
+	// ExporterFilePrefix "file:<path>"でファイルに1行1スパンのJSONで追記（オフラインの拠点向け）
+	ExporterFilePrefix = "file:"
+)
+
+// instrumentationName スパンを作成するライブラリ名
+const instrumentationName = "menkyo_go"
+
+// propagator gRPCのメタデータ・Connectのヘッダー・traceparentで伝搬する形式（W3C Trace Context）
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Setup トレースの出力先を設定し、終了時に呼ぶ関数を返す（未送信のスパンを出力する）
+// serviceNameはmenkyo-reader/menkyo-serverなど、attrsはリーダーIDなどのリソース属性
+func Setup(exporter, serviceName, version string, attrs ...attribute.KeyValue) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	var spanExporter sdktrace.SpanExporter
+	var file *os.File
+
+	switch {
+	case exporter == "" || exporter == ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case exporter == ExporterOTLP:
+		exp, err := otlptracegrpc.New(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		spanExporter = exp
+	case strings.HasPrefix(exporter, ExporterFilePrefix):
+		path := strings.TrimPrefix(exporter, ExporterFilePrefix)
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		spanExporter, file = exp, f
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s (none, otlp, file:<path>)", exporter)
+	}
+
+	attrs = append(attrs,
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", version),
+	)
+	if hostname, err := os.Hostname(); err == nil {
+		attrs = append(attrs, attribute.String("host.name", hostname))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// Start スパンを開始
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End エラーを記録してスパンを終了
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID ctxのトレースID（トレースされていない場合は空）
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// TraceParent ctxのスパンをW3C traceparentの形式で返す（メッセージに含めて後から送信する場合に使う）
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// RemoteSpanContext traceparentのスパン（不正な場合は無効なSpanContext）
+func RemoteSpanContext(traceParent string) trace.SpanContext {
+	if traceParent == "" {
+		return trace.SpanContext{}
+	}
+	carrier := propagation.MapCarrier{"traceparent": traceParent}
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
+
+// StartFromTraceParent traceParentのスパンの子としてスパンを開始し、ctxのスパンをリンクする
+// 送信待ちを経由して後から受信した読み取りを元のタッチのトレースに関連付ける（traceParentが不正な場合はctxの子）
+func StartFromTraceParent(ctx context.Context, traceParent, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	remote := RemoteSpanContext(traceParent)
+	if !remote.IsValid() {
+		return Start(ctx, name, attrs...)
+	}
+
+	parent := trace.ContextWithRemoteSpanContext(ctx, remote)
+	return otel.Tracer(instrumentationName).Start(parent, name,
+		trace.WithAttributes(attrs...),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+	)
+}
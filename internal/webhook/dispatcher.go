@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"menkyo_go/internal/database"
+)
+
+// ペイロードの形式
+const (
+	FormatJSON  = "json"  // Eventをそのまま送る
+	FormatSlack = "slack" // Slack互換の{"text": ...}（チャットの受信Webhook向け）
+)
+
+const (
+	maxAttempts       = 10               // この回数失敗したら再送をやめる（dead）
+	retryBaseDelay    = 30 * time.Second // 1回目の失敗後の再送までの時間（以降は倍）
+	retryMaxDelay     = time.Hour        // 再送までの最大の時間
+	pollInterval      = 5 * time.Second  // 再送待ちを確認する間隔
+	sendTimeout       = 10 * time.Second // 1回の送信のタイムアウト
+	deliveryBatchSize = 50               // 1回に送信する件数
+)
+
+// Dispatcher イベントを送信先ごとにDBに保存し、順に送信する
+// 送信に失敗した場合は間隔を空けて再送し、上限に達したものはdeadとして残す
+type Dispatcher struct {
+	logger            *database.Logger
+	client            *http.Client
+	expiryWarningDays int
+	logf              func(string)
+	wake              chan struct{}
+}
+
+// NewDispatcher 新しいDispatcherを作成
+// expiryWarningDaysは有効期限の警告を送る日数（0の場合は送らない）
+func NewDispatcher(logger *database.Logger, expiryWarningDays int, logf func(string)) *Dispatcher {
+	return &Dispatcher{
+		logger:            logger,
+		client:            &http.Client{Timeout: sendTimeout},
+		expiryWarningDays: expiryWarningDays,
+		logf:              logf,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// Publish イベントをフィルターに一致する送信先の送信待ちに追加
+// 免許証の読み取りで有効期限が近い場合は有効期限の警告も追加する
+// カードの識別子は保存・送信せず、PII_KEYを設定している場合のみ仮名（card_ref）を送る
+func (d *Dispatcher) Publish(e *Event) {
+	e.Data.CardID, e.Data.CardRef = "", d.logger.CardRef(e.Data.CardID)
+
+	events := []*Event{e}
+	if e.Type == EventLicenseRead && e.Data.ExpiryDate != "" {
+		expiry, err := licenseExpiry(e.Data.ExpiryDate)
+		if err != nil {
+			// 解析できない共通データは送信先に渡さない
+			d.logf(fmt.Sprintf("Failed to decode license expiry from reader %s: %v", e.Data.ReaderID, err))
+			e.Data.ExpiryDate = ""
+		} else {
+			e.Data.ExpiryDate = expiry.Format("2006-01-02")
+			if expiring, ok := expiringEvent(e, expiry, time.Now(), d.expiryWarningDays); ok {
+				events = append(events, expiring)
+			}
+		}
+	}
+
+	endpoints, err := d.logger.ListWebhookEndpoints(true)
+	if err != nil {
+		d.logf(fmt.Sprintf("Failed to list webhook endpoints: %v", err))
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	var deliveries []*database.WebhookDelivery
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			d.logf(fmt.Sprintf("Failed to marshal webhook event: %v", err))
+			continue
+		}
+
+		for _, endpoint := range endpoints {
+			if !matches(endpoint, event) {
+				continue
+			}
+			deliveries = append(deliveries, &database.WebhookDelivery{
+				EndpointID: endpoint.ID,
+				EventID:    event.ID,
+				EventType:  event.Type,
+				Payload:    string(payload),
+			})
+		}
+	}
+
+	if err := d.logger.EnqueueWebhookDeliveries(deliveries); err != nil {
+		d.logf(fmt.Sprintf("Failed to enqueue webhooks: %v", err))
+		return
+	}
+
+	if len(deliveries) > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run 送信待ちを送信する（stopが閉じられるまで）
+func (d *Dispatcher) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue()
+
+		select {
+		case <-stop:
+			return
+		case <-d.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue 送信時刻になった送信待ちがなくなるまで送信
+func (d *Dispatcher) deliverDue() {
+	endpoints := make(map[int64]*database.WebhookEndpoint)
+	attempted := make(map[int64]bool)
+
+	for {
+		deliveries, err := d.logger.DueWebhookDeliveries(time.Now(), deliveryBatchSize)
+		if err != nil {
+			d.logf(fmt.Sprintf("Failed to get webhook deliveries: %v", err))
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		for _, delivery := range deliveries {
+			// 結果を記録できなかった場合に同じ送信を繰り返さない
+			if attempted[delivery.ID] {
+				return
+			}
+			attempted[delivery.ID] = true
+
+			endpoint, ok := endpoints[delivery.EndpointID]
+			if !ok {
+				if endpoint, err = d.logger.GetWebhookEndpoint(delivery.EndpointID); err != nil {
+					d.logf(fmt.Sprintf("Failed to get webhook endpoint: %v", err))
+					return
+				}
+				endpoints[delivery.EndpointID] = endpoint
+			}
+
+			d.deliver(endpoint, delivery)
+		}
+	}
+}
+
+// deliver 1件を送信して結果を記録
+func (d *Dispatcher) deliver(endpoint *database.WebhookEndpoint, delivery *database.WebhookDelivery) {
+	// 無効にした送信先への送信待ちは送らずに止める（有効に戻してから再送できる）
+	if !endpoint.Enabled {
+		if err := d.logger.MarkWebhookFailed(delivery.ID, 0, "endpoint disabled", time.Time{}); err != nil {
+			d.logf(err.Error())
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	statusCode, err := d.Send(ctx, endpoint, strconv.FormatInt(delivery.ID, 10), delivery.EventType, []byte(delivery.Payload))
+	if err == nil {
+		if err := d.logger.MarkWebhookDelivered(delivery.ID, statusCode); err != nil {
+			d.logf(err.Error())
+		}
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	var next time.Time
+	if attempts < maxAttempts {
+		next = time.Now().Add(retryDelay(attempts))
+	} else {
+		d.logf(fmt.Sprintf("Webhook %d to %s gave up after %d attempts: %v", delivery.ID, endpoint.Name, attempts, err))
+	}
+
+	if err := d.logger.MarkWebhookFailed(delivery.ID, statusCode, err.Error(), next); err != nil {
+		d.logf(err.Error())
+	}
+}
+
+// retryDelay attempts回失敗した後の再送までの時間
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+// Send 送信先にイベントを送信し、HTTPステータスを返す（2xx以外はエラー）
+func (d *Dispatcher) Send(ctx context.Context, endpoint *database.WebhookEndpoint, deliveryID, eventType string, payload []byte) (int, error) {
+	body, err := render(endpoint.Format, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "menkyo-webhook/1")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Test 送信先にテストイベントをすぐに送信する（送信待ちには追加しない）
+func (d *Dispatcher) Test(ctx context.Context, endpoint *database.WebhookEndpoint) (int, error) {
+	event := NewEvent(EventTest, EventData{ReaderID: "test", ReadAt: time.Now().UTC().Truncate(time.Second)})
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	return d.Send(ctx, endpoint, "test-"+event.ID, event.Type, payload)
+}
+
+// render 送信先の形式に合わせて本文を作成
+func render(format string, payload []byte) ([]byte, error) {
+	switch format {
+	case "", FormatJSON:
+		return payload, nil
+	case FormatSlack:
+		event := &Event{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook event: %w", err)
+		}
+		return json.Marshal(map[string]string{"text": event.Text()})
+	default:
+		return nil, fmt.Errorf("unknown webhook format: %s", format)
+	}
+}
@@ -0,0 +1,130 @@
+// Package webhook 読み取りイベントを登録されたHTTPの送信先に署名付きで送信する
+package webhook
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"menkyo_go/internal/database"
+	"menkyo_go/internal/nfc"
+
+	"github.com/google/uuid"
+)
+
+// イベントの種類
+const (
+	EventLicenseRead     = "license.read"     // 免許証の読み取り
+	EventReadError       = "read.error"       // 読み取りエラー
+	EventLicenseExpiring = "license.expiring" // 有効期限が近い（または切れている）免許証の読み取り
+	EventTest            = "webhook.test"     // テスト送信（フィルターに関係なく送る）
+)
+
+// EventTypes フィルターに指定できるイベントの種類
+var EventTypes = []string{EventLicenseRead, EventReadError, EventLicenseExpiring}
+
+// Event 送信するイベント
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      EventData `json:"data"`
+}
+
+// EventData イベントの内容
+type EventData struct {
+	ReaderID        string    `json:"reader_id"`
+	CardID          string    `json:"card_id,omitempty"`  // Publishで消す（送信しない）
+	CardRef         string    `json:"card_ref,omitempty"` // カードの識別子の仮名（PII_KEYを設定していない場合は空）
+	CardType        string    `json:"card_type,omitempty"`
+	ExpiryDate      string    `json:"expiry_date,omitempty"`       // 有効期間の満了日（YYYY-MM-DD。Publishで免許証の共通データから変換）
+	DaysUntilExpiry *int      `json:"days_until_expiry,omitempty"` // 有効期限までの日数（切れている場合は負）
+	ReadAt          time.Time `json:"read_at"`
+	ErrorMessage    string    `json:"error_message,omitempty"`
+}
+
+// NewEvent 新しいイベントを作成
+func NewEvent(eventType string, data EventData) *Event {
+	return &Event{
+		ID:        "evt_" + uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Data:      data,
+	}
+}
+
+// Text チャット向けの1行の説明（カードの識別子は含めず、仮名がある場合のみ仮名を含める）
+func (e *Event) Text() string {
+	switch e.Type {
+	case EventLicenseRead:
+		return fmt.Sprintf("免許証を読み取りました（リーダー: %s%s）", e.Data.ReaderID, e.cardText())
+	case EventReadError:
+		return fmt.Sprintf("読み取りエラー（リーダー: %s）: %s", e.Data.ReaderID, e.Data.ErrorMessage)
+	case EventLicenseExpiring:
+		if e.Data.DaysUntilExpiry != nil && *e.Data.DaysUntilExpiry < 0 {
+			return fmt.Sprintf("有効期限切れの免許証です（リーダー: %s%s、有効期限: %s）",
+				e.Data.ReaderID, e.cardText(), e.Data.ExpiryDate)
+		}
+		days := 0
+		if e.Data.DaysUntilExpiry != nil {
+			days = *e.Data.DaysUntilExpiry
+		}
+		return fmt.Sprintf("免許証の有効期限まであと%d日です（リーダー: %s%s、有効期限: %s）",
+			days, e.Data.ReaderID, e.cardText(), e.Data.ExpiryDate)
+	case EventTest:
+		return "Webhookのテスト送信です"
+	default:
+		return e.Type
+	}
+}
+
+// cardText Textに含めるカードの仮名（仮名がない場合は空）
+func (e *Event) cardText() string {
+	if e.Data.CardRef == "" {
+		return ""
+	}
+	return "、カード: " + e.Data.CardRef
+}
+
+// matches 送信先のフィルターに一致するか（テスト送信は常に一致）
+func matches(endpoint *database.WebhookEndpoint, e *Event) bool {
+	if e.Type == EventTest {
+		return true
+	}
+	if len(endpoint.Events) > 0 && !slices.Contains(endpoint.Events, e.Type) {
+		return false
+	}
+	if len(endpoint.ReaderIDs) > 0 && !slices.Contains(endpoint.ReaderIDs, e.Data.ReaderID) {
+		return false
+	}
+	if len(endpoint.CardTypes) > 0 && !slices.Contains(endpoint.CardTypes, e.Data.CardType) {
+		return false
+	}
+	return true
+}
+
+// licenseExpiry 免許証データのExpiryDate（共通データ要素の16進数）から有効期間の満了日を取得
+func licenseExpiry(commonDataHex string) (time.Time, error) {
+	commonData, err := nfc.ParseCommonData(commonDataHex)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commonData.ExpiryDate, nil
+}
+
+// expiringEvent 有効期限がwithinDays日以内（切れている場合を含む）なら有効期限の警告イベントを作成
+func expiringEvent(e *Event, expiry, now time.Time, withinDays int) (*Event, bool) {
+	if e.Type != EventLicenseRead || withinDays <= 0 {
+		return nil, false
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	days := int(expiry.Sub(today).Hours() / 24)
+	if days > withinDays {
+		return nil, false
+	}
+
+	data := e.Data
+	data.DaysUntilExpiry = &days
+	return NewEvent(EventLicenseExpiring, data), true
+}
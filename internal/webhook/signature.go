@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// 送信時のHTTPヘッダー
+const (
+	HeaderEvent     = "X-Webhook-Event"     // イベントの種類
+	HeaderDelivery  = "X-Webhook-Delivery"  // 送信ID（再送時も同じ。受信側で重複を除くのに使う）
+	HeaderTimestamp = "X-Webhook-Timestamp" // 送信時刻（Unix時刻）
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + HMAC-SHA256(secret, timestamp + "." + body)
+)
+
+// secretPrefix 署名の鍵の接頭辞
+const secretPrefix = "whsec_"
+
+// GenerateSecret 署名の鍵を生成
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign 本文の署名を作成
+// 送信時刻も署名に含めるため、受信側は時刻を確認して古い送信の再利用を防げる
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 受信した本文の署名を確認（受信側の実装例として公開）
+// toleranceより古い・新しい送信時刻は拒否する
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %s", timestamp)
+	}
+
+	if d := time.Since(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return fmt.Errorf("timestamp out of tolerance: %s", timestamp)
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	"connectrpc.com/otelconnect"
+	"go.opentelemetry.io/otel/trace"
+	"menkyo_go/internal/metrics"
 	authv1 "menkyo_go/proto/auth/v1"
 	"menkyo_go/proto/auth/v1/authv1connect"
 )
@@ -25,9 +28,22 @@ func NewAuthClient(backendURL string, apiSecret string) (*AuthClient, error) {
 		Timeout: 30 * time.Second,
 	}
 
+	// 打刻などトレース中の呼び出しのみスパンを作成し、Connectのヘッダーでwoff-svに伝搬する
+	// （定期的なHeartbeatなどはトレースしない）
+	otelInterceptor, err := otelconnect.NewInterceptor(
+		otelconnect.WithoutMetrics(),
+		otelconnect.WithFilter(func(ctx context.Context, _ connect.Spec) bool {
+			return trace.SpanContextFromContext(ctx).IsValid()
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing interceptor: %w", err)
+	}
+
 	client := authv1connect.NewAuthServiceClient(
 		httpClient,
 		backendURL,
+		connect.WithInterceptors(otelInterceptor),
 	)
 
 	return &AuthClient{
@@ -49,7 +65,9 @@ func (c *AuthClient) Heartbeat() (*authv1.HeartbeatResponse, error) {
 
 	req := connect.NewRequest(&authv1.HeartbeatRequest{})
 
+	start := time.Now()
 	resp, err := c.client.Heartbeat(ctx, req)
+	metrics.ObserveHeartbeat(time.Since(start).Seconds(), err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send heartbeat: %w", err)
 	}
@@ -59,19 +77,43 @@ func (c *AuthClient) Heartbeat() (*authv1.HeartbeatResponse, error) {
 
 // CreateTimeCard TimeCardLogを作成 (DEV環境)
 func (c *AuthClient) CreateTimeCard(driverID int32, cardID string, state string, machineIP string) (*authv1.TimeCardLog, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	return c.CreateTimeCardAt(time.Now(), driverID, cardID, state, machineIP)
+}
 
-	now := time.Now()
+// CreateTimeCardAt 打刻時刻を指定してTimeCardLogを作成 (DEV環境)
+// 送信待ちの打刻を後から送信する場合に使う
+func (c *AuthClient) CreateTimeCardAt(punchedAt time.Time, driverID int32, cardID string, state string, machineIP string) (*authv1.TimeCardLog, error) {
+	return c.CreateTimeCardAtContext(context.Background(), punchedAt, driverID, cardID, state, machineIP)
+}
 
-	req := connect.NewRequest(&authv1.CreateTimeCardLogRequest{
-		Datetime:    now.Format(time.RFC3339),
+// CreateTimeCardAtContext CreateTimeCardAtのctx指定版（ctxのトレースをwoff-svに伝搬する）
+func (c *AuthClient) CreateTimeCardAtContext(ctx context.Context, punchedAt time.Time, driverID int32, cardID string, state string, machineIP string) (*authv1.TimeCardLog, error) {
+	return c.createTimeCardLog(ctx, &authv1.CreateTimeCardLogRequest{
+		Datetime:    punchedAt.Format(time.RFC3339),
 		Id:          driverID,
 		CardId:      cardID, // カードIDフィールドに設定
 		MachineIp:   machineIP,
 		State:       state,
 		StateDetail: "", // state_detailは空に
 	})
+}
+
+// PostNotice お知らせをTimeCardLogとして作成 (DEV環境)
+// woff-svに通知用のAPIがないため、stateで区別してstate_detailに本文を入れる
+func (c *AuthClient) PostNotice(state string, detail string, machineIP string) (*authv1.TimeCardLog, error) {
+	return c.createTimeCardLog(context.Background(), &authv1.CreateTimeCardLogRequest{
+		Datetime:    time.Now().Format(time.RFC3339),
+		MachineIp:   machineIP,
+		State:       state,
+		StateDetail: detail,
+	})
+}
+
+func (c *AuthClient) createTimeCardLog(ctx context.Context, msg *authv1.CreateTimeCardLogRequest) (*authv1.TimeCardLog, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req := connect.NewRequest(msg)
 
 	// 認証ヘッダーを追加
 	req.Header().Set("x-api-secret", c.apiSecret)
@@ -83,3 +125,41 @@ func (c *AuthClient) CreateTimeCard(driverID int32, cardID string, state string,
 
 	return resp.Msg.Log, nil
 }
+
+// ListTimeCardLogsSince since以降のTimeCardLogを取得 (DEV環境)
+// 新しい順に100件ずつ取得し、sinceより古いログに達したら終了する
+func (c *AuthClient) ListTimeCardLogsSince(since time.Time) ([]*authv1.TimeCardLog, error) {
+	const pageSize = 100
+
+	var logs []*authv1.TimeCardLog
+	for offset := int32(0); ; offset += pageSize {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		req := connect.NewRequest(&authv1.ListTimeCardLogsRequest{
+			Environment: authv1.DBEnvironment_DB_ENVIRONMENT_DEV,
+			Limit:       pageSize,
+			Offset:      offset,
+			OrderBy:     "datetime DESC",
+		})
+		req.Header().Set("x-api-secret", c.apiSecret)
+
+		resp, err := c.client.ListTimeCardLogs(ctx, req)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list time card logs: %w", err)
+		}
+
+		reachedSince := false
+		for _, l := range resp.Msg.Logs {
+			if t, err := time.Parse(time.RFC3339, l.Datetime); err == nil && t.Before(since) {
+				reachedSince = true
+				break
+			}
+			logs = append(logs, l)
+		}
+
+		if reachedSince || len(resp.Msg.Logs) < pageSize {
+			return logs, nil
+		}
+	}
+}
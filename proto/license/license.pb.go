@@ -21,6 +21,53 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 並び順
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_NEWEST_FIRST SortOrder = 0 // 新しい順（デフォルト）
+	SortOrder_SORT_ORDER_OLDEST_FIRST SortOrder = 1 // 古い順
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_NEWEST_FIRST",
+		1: "SORT_ORDER_OLDEST_FIRST",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_NEWEST_FIRST": 0,
+		"SORT_ORDER_OLDEST_FIRST": 1,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_license_license_proto_enumTypes[0].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_license_license_proto_enumTypes[0]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{0}
+}
+
 // 免許証データ
 type LicenseData struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -36,6 +83,7 @@ type LicenseData struct {
 	Photo         []byte                 `protobuf:"bytes,10,opt,name=photo,proto3" json:"photo,omitempty"`                                       // 顔写真データ
 	ReadTimestamp int64                  `protobuf:"varint,11,opt,name=read_timestamp,json=readTimestamp,proto3" json:"read_timestamp,omitempty"` // 読み取りタイムスタンプ (Unix時刻)
 	ReaderId      string                 `protobuf:"bytes,12,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                 // リーダーID
+	TraceParent   string                 `protobuf:"bytes,13,opt,name=trace_parent,json=traceParent,proto3" json:"trace_parent,omitempty"`        // 読み取りのトレース（W3C traceparent。送信待ち経由で送信した場合に関連付ける）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -154,6 +202,13 @@ func (x *LicenseData) GetReaderId() string {
 	return ""
 }
 
+func (x *LicenseData) GetTraceParent() string {
+	if x != nil {
+		return x.TraceParent
+	}
+	return ""
+}
+
 // 読み取りログ
 type ReadLog struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -162,6 +217,8 @@ type ReadLog struct {
 	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                 // ステータス (success/error)
 	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // エラーメッセージ（エラー時）
 	CardId        string                 `protobuf:"bytes,5,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                   // カードID（成功時）
+	CardType      string                 `protobuf:"bytes,6,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"`             // カード種別（driver_license/car_inspection/other）
+	TraceParent   string                 `protobuf:"bytes,7,opt,name=trace_parent,json=traceParent,proto3" json:"trace_parent,omitempty"`    // 読み取りのトレース（W3C traceparent。送信待ち経由で送信した場合に関連付ける）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -231,19 +288,118 @@ func (x *ReadLog) GetCardId() string {
 	return ""
 }
 
+func (x *ReadLog) GetCardType() string {
+	if x != nil {
+		return x.CardType
+	}
+	return ""
+}
+
+func (x *ReadLog) GetTraceParent() string {
+	if x != nil {
+		return x.TraceParent
+	}
+	return ""
+}
+
+// 配車（運転者と車両の割り当て）
+type Assignment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                  // リーダーID
+	LicenseCardId string                 `protobuf:"bytes,2,opt,name=license_card_id,json=licenseCardId,proto3" json:"license_card_id,omitempty"` // 免許証のカードID
+	DriverId      int32                  `protobuf:"varint,3,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`                 // 運転者ID
+	VehicleCardId string                 `protobuf:"bytes,4,opt,name=vehicle_card_id,json=vehicleCardId,proto3" json:"vehicle_card_id,omitempty"` // 車検証のカードID
+	VehicleId     string                 `protobuf:"bytes,5,opt,name=vehicle_id,json=vehicleId,proto3" json:"vehicle_id,omitempty"`               // 車両ID
+	AssignedAt    int64                  `protobuf:"varint,6,opt,name=assigned_at,json=assignedAt,proto3" json:"assigned_at,omitempty"`           // 割り当て時刻（Unix時刻）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Assignment) Reset() {
+	*x = Assignment{}
+	mi := &file_license_license_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Assignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assignment) ProtoMessage() {}
+
+func (x *Assignment) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assignment.ProtoReflect.Descriptor instead.
+func (*Assignment) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Assignment) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *Assignment) GetLicenseCardId() string {
+	if x != nil {
+		return x.LicenseCardId
+	}
+	return ""
+}
+
+func (x *Assignment) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *Assignment) GetVehicleCardId() string {
+	if x != nil {
+		return x.VehicleCardId
+	}
+	return ""
+}
+
+func (x *Assignment) GetVehicleId() string {
+	if x != nil {
+		return x.VehicleId
+	}
+	return ""
+}
+
+func (x *Assignment) GetAssignedAt() int64 {
+	if x != nil {
+		return x.AssignedAt
+	}
+	return 0
+}
+
 // レスポンス
 type PushResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // リクエストID
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // リクエストID（トレースされている場合はトレースID）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PushResponse) Reset() {
 	*x = PushResponse{}
-	mi := &file_license_license_proto_msgTypes[2]
+	mi := &file_license_license_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -255,7 +411,7 @@ func (x *PushResponse) String() string {
 func (*PushResponse) ProtoMessage() {}
 
 func (x *PushResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[2]
+	mi := &file_license_license_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -268,7 +424,7 @@ func (x *PushResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PushResponse.ProtoReflect.Descriptor instead.
 func (*PushResponse) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{2}
+	return file_license_license_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PushResponse) GetSuccess() bool {
@@ -295,18 +451,22 @@ func (x *PushResponse) GetRequestId() string {
 // ログ取得リクエスト
 type GetLogsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`     // リーダーID（省略時は全リーダー）
-	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`                           // ログレベル（INFO/WARNING/ERROR/DEBUG）
-	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // 開始時刻（Unix時刻）
-	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // 終了時刻（Unix時刻）
-	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                          // 取得件数制限（デフォルト100）
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                            // リーダーID（省略時は全リーダー）
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`                                                  // ログレベル（INFO/WARNING/ERROR/DEBUG）
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                        // 開始時刻（Unix時刻）
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                              // 終了時刻（Unix時刻）
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                                                 // 取得件数制限（デフォルト100、最大1000）
+	CardId        string                 `protobuf:"bytes,6,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                                  // カードID（省略時は全カード）
+	PageToken     string                 `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                         // 前回のnext_page_token（省略時は先頭から）
+	SortOrder     SortOrder              `protobuf:"varint,8,opt,name=sort_order,json=sortOrder,proto3,enum=license.SortOrder" json:"sort_order,omitempty"` // 並び順
+	CardRef       string                 `protobuf:"bytes,9,opt,name=card_ref,json=cardRef,proto3" json:"card_ref,omitempty"`                               // カードIDの仮名（省略時は全カード）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetLogsRequest) Reset() {
 	*x = GetLogsRequest{}
-	mi := &file_license_license_proto_msgTypes[3]
+	mi := &file_license_license_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -318,7 +478,7 @@ func (x *GetLogsRequest) String() string {
 func (*GetLogsRequest) ProtoMessage() {}
 
 func (x *GetLogsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[3]
+	mi := &file_license_license_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -331,7 +491,7 @@ func (x *GetLogsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLogsRequest.ProtoReflect.Descriptor instead.
 func (*GetLogsRequest) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{3}
+	return file_license_license_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetLogsRequest) GetReaderId() string {
@@ -369,18 +529,47 @@ func (x *GetLogsRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *GetLogsRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *GetLogsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetLogsRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_NEWEST_FIRST
+}
+
+func (x *GetLogsRequest) GetCardRef() string {
+	if x != nil {
+		return x.CardRef
+	}
+	return ""
+}
+
 // ログ取得レスポンス
 type GetLogsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Logs          []*LogEntry            `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // 総件数
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`           // 総件数
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 次のページのトークン（最後のページの場合は空）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetLogsResponse) Reset() {
 	*x = GetLogsResponse{}
-	mi := &file_license_license_proto_msgTypes[4]
+	mi := &file_license_license_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -392,7 +581,7 @@ func (x *GetLogsResponse) String() string {
 func (*GetLogsResponse) ProtoMessage() {}
 
 func (x *GetLogsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[4]
+	mi := &file_license_license_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -405,7 +594,7 @@ func (x *GetLogsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLogsResponse.ProtoReflect.Descriptor instead.
 func (*GetLogsResponse) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{4}
+	return file_license_license_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetLogsResponse) GetLogs() []*LogEntry {
@@ -422,6 +611,13 @@ func (x *GetLogsResponse) GetTotalCount() int32 {
 	return 0
 }
 
+func (x *GetLogsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // ログエントリ
 type LogEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -429,13 +625,17 @@ type LogEntry struct {
 	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`                       // ログレベル
 	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                   // ログメッセージ
 	ReaderId      string                 `protobuf:"bytes,4,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"` // リーダーID
+	CardId        string                 `protobuf:"bytes,5,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`       // カードID（PII_KEYを設定している場合は空）
+	Id            int64                  `protobuf:"varint,6,opt,name=id,proto3" json:"id,omitempty"`                            // ログID
+	Attrs         string                 `protobuf:"bytes,7,opt,name=attrs,proto3" json:"attrs,omitempty"`                       // 構造化ログの属性（JSON。request_idなど）
+	CardRef       string                 `protobuf:"bytes,8,opt,name=card_ref,json=cardRef,proto3" json:"card_ref,omitempty"`    // カードIDの仮名（read_historyのcard_refと紐付ける）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LogEntry) Reset() {
 	*x = LogEntry{}
-	mi := &file_license_license_proto_msgTypes[5]
+	mi := &file_license_license_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -447,7 +647,7 @@ func (x *LogEntry) String() string {
 func (*LogEntry) ProtoMessage() {}
 
 func (x *LogEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[5]
+	mi := &file_license_license_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -460,7 +660,7 @@ func (x *LogEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
 func (*LogEntry) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{5}
+	return file_license_license_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *LogEntry) GetTimestamp() int64 {
@@ -491,21 +691,54 @@ func (x *LogEntry) GetReaderId() string {
 	return ""
 }
 
+func (x *LogEntry) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LogEntry) GetAttrs() string {
+	if x != nil {
+		return x.Attrs
+	}
+	return ""
+}
+
+func (x *LogEntry) GetCardRef() string {
+	if x != nil {
+		return x.CardRef
+	}
+	return ""
+}
+
 // 読み取り履歴取得リクエスト
 type GetReadHistoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`     // リーダーID（省略時は全リーダー）
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                         // ステータス（success/error）
-	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // 開始時刻（Unix時刻）
-	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // 終了時刻（Unix時刻）
-	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                          // 取得件数制限（デフォルト100）
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                            // リーダーID（省略時は全リーダー）
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                                                // ステータス（success/error）
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                        // 開始時刻（Unix時刻）
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                              // 終了時刻（Unix時刻）
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                                                 // 取得件数制限（デフォルト100、最大1000）
+	CardId        string                 `protobuf:"bytes,6,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                                  // カードID（省略時は全カード）
+	CardType      string                 `protobuf:"bytes,7,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"`                            // カード種別（省略時は全種別）
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                         // 前回のnext_page_token（省略時は先頭から）
+	SortOrder     SortOrder              `protobuf:"varint,9,opt,name=sort_order,json=sortOrder,proto3,enum=license.SortOrder" json:"sort_order,omitempty"` // 並び順
+	CardRef       string                 `protobuf:"bytes,10,opt,name=card_ref,json=cardRef,proto3" json:"card_ref,omitempty"`                              // カードIDの仮名（省略時は全カード）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetReadHistoryRequest) Reset() {
 	*x = GetReadHistoryRequest{}
-	mi := &file_license_license_proto_msgTypes[6]
+	mi := &file_license_license_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -517,7 +750,7 @@ func (x *GetReadHistoryRequest) String() string {
 func (*GetReadHistoryRequest) ProtoMessage() {}
 
 func (x *GetReadHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[6]
+	mi := &file_license_license_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -530,7 +763,7 @@ func (x *GetReadHistoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReadHistoryRequest.ProtoReflect.Descriptor instead.
 func (*GetReadHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{6}
+	return file_license_license_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetReadHistoryRequest) GetReaderId() string {
@@ -568,18 +801,54 @@ func (x *GetReadHistoryRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *GetReadHistoryRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *GetReadHistoryRequest) GetCardType() string {
+	if x != nil {
+		return x.CardType
+	}
+	return ""
+}
+
+func (x *GetReadHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetReadHistoryRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_NEWEST_FIRST
+}
+
+func (x *GetReadHistoryRequest) GetCardRef() string {
+	if x != nil {
+		return x.CardRef
+	}
+	return ""
+}
+
 // 読み取り履歴取得レスポンス
 type GetReadHistoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Entries       []*ReadHistoryEntry    `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // 総件数
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`           // 総件数
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 次のページのトークン（最後のページの場合は空）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetReadHistoryResponse) Reset() {
 	*x = GetReadHistoryResponse{}
-	mi := &file_license_license_proto_msgTypes[7]
+	mi := &file_license_license_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -591,7 +860,7 @@ func (x *GetReadHistoryResponse) String() string {
 func (*GetReadHistoryResponse) ProtoMessage() {}
 
 func (x *GetReadHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[7]
+	mi := &file_license_license_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,7 +873,7 @@ func (x *GetReadHistoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReadHistoryResponse.ProtoReflect.Descriptor instead.
 func (*GetReadHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{7}
+	return file_license_license_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetReadHistoryResponse) GetEntries() []*ReadHistoryEntry {
@@ -621,6 +890,13 @@ func (x *GetReadHistoryResponse) GetTotalCount() int32 {
 	return 0
 }
 
+func (x *GetReadHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // 読み取り履歴エントリ
 type ReadHistoryEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -634,13 +910,15 @@ type ReadHistoryEntry struct {
 	FelicaUid     string                 `protobuf:"bytes,8,opt,name=felica_uid,json=felicaUid,proto3" json:"felica_uid,omitempty"`           // FeliCa UID
 	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`                                  // ステータス（success/error）
 	ErrorMessage  string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // エラーメッセージ
+	Id            int64                  `protobuf:"varint,11,opt,name=id,proto3" json:"id,omitempty"`                                        // 履歴ID
+	CardRef       string                 `protobuf:"bytes,12,opt,name=card_ref,json=cardRef,proto3" json:"card_ref,omitempty"`                // カードIDの仮名（logsのcard_refと紐付ける）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadHistoryEntry) Reset() {
 	*x = ReadHistoryEntry{}
-	mi := &file_license_license_proto_msgTypes[8]
+	mi := &file_license_license_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -652,7 +930,7 @@ func (x *ReadHistoryEntry) String() string {
 func (*ReadHistoryEntry) ProtoMessage() {}
 
 func (x *ReadHistoryEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_license_license_proto_msgTypes[8]
+	mi := &file_license_license_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -665,7 +943,7 @@ func (x *ReadHistoryEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadHistoryEntry.ProtoReflect.Descriptor instead.
 func (*ReadHistoryEntry) Descriptor() ([]byte, []int) {
-	return file_license_license_proto_rawDescGZIP(), []int{8}
+	return file_license_license_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ReadHistoryEntry) GetTimestamp() int64 {
@@ -738,66 +1016,1824 @@ func (x *ReadHistoryEntry) GetErrorMessage() string {
 	return ""
 }
 
-var File_license_license_proto protoreflect.FileDescriptor
+func (x *ReadHistoryEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
 
-const file_license_license_proto_rawDesc = "" +
-	"\n" +
-	"\x15license/license.proto\x12\alicense\"\xf4\x02\n" +
-	"\vLicenseData\x12\x17\n" +
-	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
-	"\tname_kana\x18\x03 \x01(\tR\bnameKana\x12\x1d\n" +
-	"\n" +
-	"birth_date\x18\x04 \x01(\tR\tbirthDate\x12\x18\n" +
-	"\aaddress\x18\x05 \x01(\tR\aaddress\x12\x1d\n" +
-	"\n" +
-	"issue_date\x18\x06 \x01(\tR\tissueDate\x12\x1f\n" +
-	"\vexpiry_date\x18\a \x01(\tR\n" +
-	"expiryDate\x12%\n" +
-	"\x0elicense_number\x18\b \x01(\tR\rlicenseNumber\x12!\n" +
-	"\flicense_type\x18\t \x01(\tR\vlicenseType\x12\x14\n" +
-	"\x05photo\x18\n" +
-	" \x01(\fR\x05photo\x12%\n" +
-	"\x0eread_timestamp\x18\v \x01(\x03R\rreadTimestamp\x12\x1b\n" +
-	"\treader_id\x18\f \x01(\tR\breaderId\"\x9a\x01\n" +
-	"\aReadLog\x12\x1c\n" +
-	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1b\n" +
-	"\treader_id\x18\x02 \x01(\tR\breaderId\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\tR\x06status\x12#\n" +
-	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\x12\x17\n" +
-	"\acard_id\x18\x05 \x01(\tR\x06cardId\"a\n" +
-	"\fPushResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
-	"\n" +
-	"request_id\x18\x03 \x01(\tR\trequestId\"\x93\x01\n" +
-	"\x0eGetLogsRequest\x12\x1b\n" +
-	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x14\n" +
-	"\x05level\x18\x02 \x01(\tR\x05level\x12\x1d\n" +
-	"\n" +
-	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
-	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
-	"\x05limit\x18\x05 \x01(\x05R\x05limit\"Y\n" +
-	"\x0fGetLogsResponse\x12%\n" +
-	"\x04logs\x18\x01 \x03(\v2\x11.license.LogEntryR\x04logs\x12\x1f\n" +
-	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\"u\n" +
-	"\bLogEntry\x12\x1c\n" +
-	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+func (x *ReadHistoryEntry) GetCardRef() string {
+	if x != nil {
+		return x.CardRef
+	}
+	return ""
+}
+
+// 勤務時間取得リクエスト
+type GetWorkHoursRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`   // 運転者ID（0の場合は全員）
+	StartDate     string                 `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"` // 開始日 (YYYY-MM-DD)
+	EndDate       string                 `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`       // 終了日 (YYYY-MM-DD、この日を含む)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkHoursRequest) Reset() {
+	*x = GetWorkHoursRequest{}
+	mi := &file_license_license_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkHoursRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkHoursRequest) ProtoMessage() {}
+
+func (x *GetWorkHoursRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkHoursRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkHoursRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetWorkHoursRequest) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *GetWorkHoursRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetWorkHoursRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+// 勤務時間取得レスポンス
+type GetWorkHoursResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shifts        []*WorkShift           `protobuf:"bytes,1,rep,name=shifts,proto3" json:"shifts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkHoursResponse) Reset() {
+	*x = GetWorkHoursResponse{}
+	mi := &file_license_license_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkHoursResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkHoursResponse) ProtoMessage() {}
+
+func (x *GetWorkHoursResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkHoursResponse.ProtoReflect.Descriptor instead.
+func (*GetWorkHoursResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetWorkHoursResponse) GetShifts() []*WorkShift {
+	if x != nil {
+		return x.Shifts
+	}
+	return nil
+}
+
+// 1勤務分の集計
+type WorkShift struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DriverId         int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`                           // 運転者ID
+	WorkDate         string                 `protobuf:"bytes,2,opt,name=work_date,json=workDate,proto3" json:"work_date,omitempty"`                            // 勤務日 (YYYY-MM-DD、出勤日)
+	StartTime        int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                        // 出勤時刻（Unix時刻、打刻漏れの場合は0）
+	EndTime          int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                              // 退勤時刻（Unix時刻、打刻漏れ・勤務中の場合は0）
+	TotalMinutes     int32                  `protobuf:"varint,5,opt,name=total_minutes,json=totalMinutes,proto3" json:"total_minutes,omitempty"`               // 実労働時間（分）
+	BreakMinutes     int32                  `protobuf:"varint,6,opt,name=break_minutes,json=breakMinutes,proto3" json:"break_minutes,omitempty"`               // 休憩時間（分）
+	OvertimeMinutes  int32                  `protobuf:"varint,7,opt,name=overtime_minutes,json=overtimeMinutes,proto3" json:"overtime_minutes,omitempty"`      // 時間外労働（分）
+	LateNightMinutes int32                  `protobuf:"varint,8,opt,name=late_night_minutes,json=lateNightMinutes,proto3" json:"late_night_minutes,omitempty"` // 深夜労働（分）
+	MissingClockIn   bool                   `protobuf:"varint,9,opt,name=missing_clock_in,json=missingClockIn,proto3" json:"missing_clock_in,omitempty"`       // 出勤打刻漏れ
+	MissingClockOut  bool                   `protobuf:"varint,10,opt,name=missing_clock_out,json=missingClockOut,proto3" json:"missing_clock_out,omitempty"`   // 退勤打刻漏れ
+	InProgress       bool                   `protobuf:"varint,11,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`                    // 勤務中
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WorkShift) Reset() {
+	*x = WorkShift{}
+	mi := &file_license_license_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkShift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkShift) ProtoMessage() {}
+
+func (x *WorkShift) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkShift.ProtoReflect.Descriptor instead.
+func (*WorkShift) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WorkShift) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *WorkShift) GetWorkDate() string {
+	if x != nil {
+		return x.WorkDate
+	}
+	return ""
+}
+
+func (x *WorkShift) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *WorkShift) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *WorkShift) GetTotalMinutes() int32 {
+	if x != nil {
+		return x.TotalMinutes
+	}
+	return 0
+}
+
+func (x *WorkShift) GetBreakMinutes() int32 {
+	if x != nil {
+		return x.BreakMinutes
+	}
+	return 0
+}
+
+func (x *WorkShift) GetOvertimeMinutes() int32 {
+	if x != nil {
+		return x.OvertimeMinutes
+	}
+	return 0
+}
+
+func (x *WorkShift) GetLateNightMinutes() int32 {
+	if x != nil {
+		return x.LateNightMinutes
+	}
+	return 0
+}
+
+func (x *WorkShift) GetMissingClockIn() bool {
+	if x != nil {
+		return x.MissingClockIn
+	}
+	return false
+}
+
+func (x *WorkShift) GetMissingClockOut() bool {
+	if x != nil {
+		return x.MissingClockOut
+	}
+	return false
+}
+
+func (x *WorkShift) GetInProgress() bool {
+	if x != nil {
+		return x.InProgress
+	}
+	return false
+}
+
+// 打刻の異常一覧取得リクエスト
+type ListAnomaliesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	WorkDate        string                 `protobuf:"bytes,1,opt,name=work_date,json=workDate,proto3" json:"work_date,omitempty"`                       // 勤務日 (YYYY-MM-DD、空の場合は全日)
+	IncludeResolved bool                   `protobuf:"varint,2,opt,name=include_resolved,json=includeResolved,proto3" json:"include_resolved,omitempty"` // 解決済みも含める
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListAnomaliesRequest) Reset() {
+	*x = ListAnomaliesRequest{}
+	mi := &file_license_license_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAnomaliesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAnomaliesRequest) ProtoMessage() {}
+
+func (x *ListAnomaliesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAnomaliesRequest.ProtoReflect.Descriptor instead.
+func (*ListAnomaliesRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListAnomaliesRequest) GetWorkDate() string {
+	if x != nil {
+		return x.WorkDate
+	}
+	return ""
+}
+
+func (x *ListAnomaliesRequest) GetIncludeResolved() bool {
+	if x != nil {
+		return x.IncludeResolved
+	}
+	return false
+}
+
+// 打刻の異常一覧取得レスポンス
+type ListAnomaliesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anomalies     []*Anomaly             `protobuf:"bytes,1,rep,name=anomalies,proto3" json:"anomalies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAnomaliesResponse) Reset() {
+	*x = ListAnomaliesResponse{}
+	mi := &file_license_license_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAnomaliesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAnomaliesResponse) ProtoMessage() {}
+
+func (x *ListAnomaliesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAnomaliesResponse.ProtoReflect.Descriptor instead.
+func (*ListAnomaliesResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListAnomaliesResponse) GetAnomalies() []*Anomaly {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+// 打刻の異常
+type Anomaly struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                               // 異常ID
+	DetectedAt     int64                  `protobuf:"varint,2,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`             // 検出時刻（Unix時刻）
+	WorkDate       string                 `protobuf:"bytes,3,opt,name=work_date,json=workDate,proto3" json:"work_date,omitempty"`                    // 勤務日 (YYYY-MM-DD)
+	Kind           string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`                                            // 種類（missing_clock_out/missing_clock_in/duplicate_tap/unregistered_card/outside_window）
+	DriverId       int32                  `protobuf:"varint,5,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`                   // 運転者ID（未登録カードの場合は0）
+	CardId         string                 `protobuf:"bytes,6,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`                          // カードID
+	PunchTime      int64                  `protobuf:"varint,7,opt,name=punch_time,json=punchTime,proto3" json:"punch_time,omitempty"`                // 対象の打刻時刻（Unix時刻）
+	Detail         string                 `protobuf:"bytes,8,opt,name=detail,proto3" json:"detail,omitempty"`                                        // 詳細
+	Resolved       bool                   `protobuf:"varint,9,opt,name=resolved,proto3" json:"resolved,omitempty"`                                   // 解決済み
+	ResolvedAt     int64                  `protobuf:"varint,10,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`            // 解決時刻（Unix時刻）
+	ResolvedBy     string                 `protobuf:"bytes,11,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`             // 解決者
+	ResolutionNote string                 `protobuf:"bytes,12,opt,name=resolution_note,json=resolutionNote,proto3" json:"resolution_note,omitempty"` // 対応内容
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Anomaly) Reset() {
+	*x = Anomaly{}
+	mi := &file_license_license_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Anomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Anomaly) ProtoMessage() {}
+
+func (x *Anomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Anomaly.ProtoReflect.Descriptor instead.
+func (*Anomaly) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Anomaly) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Anomaly) GetDetectedAt() int64 {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return 0
+}
+
+func (x *Anomaly) GetWorkDate() string {
+	if x != nil {
+		return x.WorkDate
+	}
+	return ""
+}
+
+func (x *Anomaly) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Anomaly) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *Anomaly) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *Anomaly) GetPunchTime() int64 {
+	if x != nil {
+		return x.PunchTime
+	}
+	return 0
+}
+
+func (x *Anomaly) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *Anomaly) GetResolved() bool {
+	if x != nil {
+		return x.Resolved
+	}
+	return false
+}
+
+func (x *Anomaly) GetResolvedAt() int64 {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return 0
+}
+
+func (x *Anomaly) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *Anomaly) GetResolutionNote() string {
+	if x != nil {
+		return x.ResolutionNote
+	}
+	return ""
+}
+
+// 打刻の異常解決リクエスト
+type ResolveAnomalyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                  // 異常ID
+	ResolvedBy    string                 `protobuf:"bytes,2,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"` // 解決者
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`                               // 対応内容
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAnomalyRequest) Reset() {
+	*x = ResolveAnomalyRequest{}
+	mi := &file_license_license_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAnomalyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAnomalyRequest) ProtoMessage() {}
+
+func (x *ResolveAnomalyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAnomalyRequest.ProtoReflect.Descriptor instead.
+func (*ResolveAnomalyRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ResolveAnomalyRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ResolveAnomalyRequest) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *ResolveAnomalyRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+// 打刻の異常解決レスポンス
+type ResolveAnomalyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAnomalyResponse) Reset() {
+	*x = ResolveAnomalyResponse{}
+	mi := &file_license_license_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAnomalyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAnomalyResponse) ProtoMessage() {}
+
+func (x *ResolveAnomalyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAnomalyResponse.ProtoReflect.Descriptor instead.
+func (*ResolveAnomalyResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResolveAnomalyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResolveAnomalyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 読み取りイベント受信リクエスト
+type WatchReadsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId       string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                      // リーダーID（省略時は全リーダー）
+	CardType       string                 `protobuf:"bytes,2,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"`                      // カード種別（省略時は全種別）
+	ResumeAfterSeq uint64                 `protobuf:"varint,3,opt,name=resume_after_seq,json=resumeAfterSeq,proto3" json:"resume_after_seq,omitempty"` // 再接続時、最後に受信したseq（0の場合は新しいイベントのみ）
+	ResumeEpoch    string                 `protobuf:"bytes,4,opt,name=resume_epoch,json=resumeEpoch,proto3" json:"resume_epoch,omitempty"`             // resume_after_seqを受信したサーバーの起動ID（ReadEvent.epoch）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchReadsRequest) Reset() {
+	*x = WatchReadsRequest{}
+	mi := &file_license_license_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchReadsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchReadsRequest) ProtoMessage() {}
+
+func (x *WatchReadsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchReadsRequest.ProtoReflect.Descriptor instead.
+func (*WatchReadsRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchReadsRequest) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *WatchReadsRequest) GetCardType() string {
+	if x != nil {
+		return x.CardType
+	}
+	return ""
+}
+
+func (x *WatchReadsRequest) GetResumeAfterSeq() uint64 {
+	if x != nil {
+		return x.ResumeAfterSeq
+	}
+	return 0
+}
+
+func (x *WatchReadsRequest) GetResumeEpoch() string {
+	if x != nil {
+		return x.ResumeEpoch
+	}
+	return ""
+}
+
+// 読み取りイベント
+type ReadEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Seq       uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`                          // 連番（サーバー起動ごとに1から）
+	Timestamp int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`              // 受信時刻（Unix時刻）
+	ReaderId  string                 `protobuf:"bytes,3,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"` // リーダーID
+	CardType  string                 `protobuf:"bytes,4,opt,name=card_type,json=cardType,proto3" json:"card_type,omitempty"` // カード種別
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ReadEvent_LicenseData
+	//	*ReadEvent_ReadLog
+	Payload       isReadEvent_Payload `protobuf_oneof:"payload"`
+	Epoch         string              `protobuf:"bytes,7,opt,name=epoch,proto3" json:"epoch,omitempty"` // サーバーの起動ID（seqは起動ごとに振り直すため、再接続時にresume_epochで送る）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadEvent) Reset() {
+	*x = ReadEvent{}
+	mi := &file_license_license_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadEvent) ProtoMessage() {}
+
+func (x *ReadEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadEvent.ProtoReflect.Descriptor instead.
+func (*ReadEvent) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReadEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ReadEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReadEvent) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *ReadEvent) GetCardType() string {
+	if x != nil {
+		return x.CardType
+	}
+	return ""
+}
+
+func (x *ReadEvent) GetPayload() isReadEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ReadEvent) GetLicenseData() *LicenseData {
+	if x != nil {
+		if x, ok := x.Payload.(*ReadEvent_LicenseData); ok {
+			return x.LicenseData
+		}
+	}
+	return nil
+}
+
+func (x *ReadEvent) GetReadLog() *ReadLog {
+	if x != nil {
+		if x, ok := x.Payload.(*ReadEvent_ReadLog); ok {
+			return x.ReadLog
+		}
+	}
+	return nil
+}
+
+func (x *ReadEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+type isReadEvent_Payload interface {
+	isReadEvent_Payload()
+}
+
+type ReadEvent_LicenseData struct {
+	LicenseData *LicenseData `protobuf:"bytes,5,opt,name=license_data,json=licenseData,proto3,oneof"` // PushLicenseDataの内容（顔写真は除く）
+}
+
+type ReadEvent_ReadLog struct {
+	ReadLog *ReadLog `protobuf:"bytes,6,opt,name=read_log,json=readLog,proto3,oneof"` // PushReadLogの内容
+}
+
+func (*ReadEvent_LicenseData) isReadEvent_Payload() {}
+
+func (*ReadEvent_ReadLog) isReadEvent_Payload() {}
+
+// リーダー登録リクエスト
+type RegisterReaderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`       // リーダーID
+	Site          string                 `protobuf:"bytes,2,opt,name=site,proto3" json:"site,omitempty"`                               // 拠点
+	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`                       // 設置場所
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`                         // ソフトウェアのバージョン
+	BuildTime     string                 `protobuf:"bytes,5,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`    // ビルド日時
+	NfcDevices    []string               `protobuf:"bytes,6,rep,name=nfc_devices,json=nfcDevices,proto3" json:"nfc_devices,omitempty"` // 接続されているNFCリーダーの名前
+	Hostname      string                 `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`                       // ホスト名
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterReaderRequest) Reset() {
+	*x = RegisterReaderRequest{}
+	mi := &file_license_license_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterReaderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterReaderRequest) ProtoMessage() {}
+
+func (x *RegisterReaderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterReaderRequest.ProtoReflect.Descriptor instead.
+func (*RegisterReaderRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RegisterReaderRequest) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *RegisterReaderRequest) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *RegisterReaderRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *RegisterReaderRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegisterReaderRequest) GetBuildTime() string {
+	if x != nil {
+		return x.BuildTime
+	}
+	return ""
+}
+
+func (x *RegisterReaderRequest) GetNfcDevices() []string {
+	if x != nil {
+		return x.NfcDevices
+	}
+	return nil
+}
+
+func (x *RegisterReaderRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+// リーダー登録レスポンス
+type RegisterReaderResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Success                  bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message                  string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	HeartbeatIntervalSeconds int32                  `protobuf:"varint,3,opt,name=heartbeat_interval_seconds,json=heartbeatIntervalSeconds,proto3" json:"heartbeat_interval_seconds,omitempty"` // ReaderHeartbeatを送る間隔（秒）
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *RegisterReaderResponse) Reset() {
+	*x = RegisterReaderResponse{}
+	mi := &file_license_license_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterReaderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterReaderResponse) ProtoMessage() {}
+
+func (x *RegisterReaderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterReaderResponse.ProtoReflect.Descriptor instead.
+func (*RegisterReaderResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RegisterReaderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterReaderResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegisterReaderResponse) GetHeartbeatIntervalSeconds() int32 {
+	if x != nil {
+		return x.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+// リーダー死活監視リクエスト
+type ReaderHeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`       // リーダーID
+	NfcDevices    []string               `protobuf:"bytes,2,rep,name=nfc_devices,json=nfcDevices,proto3" json:"nfc_devices,omitempty"` // 接続されているNFCリーダーの名前
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReaderHeartbeatRequest) Reset() {
+	*x = ReaderHeartbeatRequest{}
+	mi := &file_license_license_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReaderHeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReaderHeartbeatRequest) ProtoMessage() {}
+
+func (x *ReaderHeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReaderHeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*ReaderHeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReaderHeartbeatRequest) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *ReaderHeartbeatRequest) GetNfcDevices() []string {
+	if x != nil {
+		return x.NfcDevices
+	}
+	return nil
+}
+
+// リーダー死活監視レスポンス
+type ReaderHeartbeatResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Success                  bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message                  string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	HeartbeatIntervalSeconds int32                  `protobuf:"varint,3,opt,name=heartbeat_interval_seconds,json=heartbeatIntervalSeconds,proto3" json:"heartbeat_interval_seconds,omitempty"` // 次のReaderHeartbeatまでの間隔（秒）
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ReaderHeartbeatResponse) Reset() {
+	*x = ReaderHeartbeatResponse{}
+	mi := &file_license_license_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReaderHeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReaderHeartbeatResponse) ProtoMessage() {}
+
+func (x *ReaderHeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReaderHeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*ReaderHeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ReaderHeartbeatResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReaderHeartbeatResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ReaderHeartbeatResponse) GetHeartbeatIntervalSeconds() int32 {
+	if x != nil {
+		return x.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+// リーダー一覧取得リクエスト
+type ListReadersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Site          string                 `protobuf:"bytes,1,opt,name=site,proto3" json:"site,omitempty"`                             // 拠点（省略時は全拠点）
+	StaleOnly     bool                   `protobuf:"varint,2,opt,name=stale_only,json=staleOnly,proto3" json:"stale_only,omitempty"` // 応答のないリーダーのみ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReadersRequest) Reset() {
+	*x = ListReadersRequest{}
+	mi := &file_license_license_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReadersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReadersRequest) ProtoMessage() {}
+
+func (x *ListReadersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReadersRequest.ProtoReflect.Descriptor instead.
+func (*ListReadersRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListReadersRequest) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *ListReadersRequest) GetStaleOnly() bool {
+	if x != nil {
+		return x.StaleOnly
+	}
+	return false
+}
+
+// リーダー一覧取得レスポンス
+type ListReadersResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Readers           []*ReaderInfo          `protobuf:"bytes,1,rep,name=readers,proto3" json:"readers,omitempty"`
+	StaleAfterSeconds int32                  `protobuf:"varint,2,opt,name=stale_after_seconds,json=staleAfterSeconds,proto3" json:"stale_after_seconds,omitempty"` // この時間応答がないリーダーを応答なしとする（秒）
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListReadersResponse) Reset() {
+	*x = ListReadersResponse{}
+	mi := &file_license_license_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReadersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReadersResponse) ProtoMessage() {}
+
+func (x *ListReadersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReadersResponse.ProtoReflect.Descriptor instead.
+func (*ListReadersResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListReadersResponse) GetReaders() []*ReaderInfo {
+	if x != nil {
+		return x.Readers
+	}
+	return nil
+}
+
+func (x *ListReadersResponse) GetStaleAfterSeconds() int32 {
+	if x != nil {
+		return x.StaleAfterSeconds
+	}
+	return 0
+}
+
+// リーダー情報
+type ReaderInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                  // リーダーID
+	Site          string                 `protobuf:"bytes,2,opt,name=site,proto3" json:"site,omitempty"`                                          // 拠点
+	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`                                  // 設置場所
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`                                    // ソフトウェアのバージョン
+	BuildTime     string                 `protobuf:"bytes,5,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`               // ビルド日時
+	NfcDevices    []string               `protobuf:"bytes,6,rep,name=nfc_devices,json=nfcDevices,proto3" json:"nfc_devices,omitempty"`            // 接続されているNFCリーダーの名前
+	Hostname      string                 `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`                                  // ホスト名
+	PeerAddr      string                 `protobuf:"bytes,8,opt,name=peer_addr,json=peerAddr,proto3" json:"peer_addr,omitempty"`                  // 最後に接続したアドレス
+	RegisteredAt  int64                  `protobuf:"varint,9,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`     // 初回登録時刻（Unix時刻）
+	LastSeen      int64                  `protobuf:"varint,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`                // 最後に応答した時刻（Unix時刻）
+	Stale         bool                   `protobuf:"varint,11,opt,name=stale,proto3" json:"stale,omitempty"`                                      // 応答なし
+	StaleSince    int64                  `protobuf:"varint,12,opt,name=stale_since,json=staleSince,proto3" json:"stale_since,omitempty"`          // 応答なしと判定した時刻（Unix時刻、0の場合は未判定）
+	ConfigVersion int64                  `protobuf:"varint,13,opt,name=config_version,json=configVersion,proto3" json:"config_version,omitempty"` // 適用済みの設定のバージョン
+	ConfigError   string                 `protobuf:"bytes,14,opt,name=config_error,json=configError,proto3" json:"config_error,omitempty"`        // 設定の適用に失敗した場合のエラー
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReaderInfo) Reset() {
+	*x = ReaderInfo{}
+	mi := &file_license_license_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReaderInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReaderInfo) ProtoMessage() {}
+
+func (x *ReaderInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReaderInfo.ProtoReflect.Descriptor instead.
+func (*ReaderInfo) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ReaderInfo) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetBuildTime() string {
+	if x != nil {
+		return x.BuildTime
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetNfcDevices() []string {
+	if x != nil {
+		return x.NfcDevices
+	}
+	return nil
+}
+
+func (x *ReaderInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetPeerAddr() string {
+	if x != nil {
+		return x.PeerAddr
+	}
+	return ""
+}
+
+func (x *ReaderInfo) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *ReaderInfo) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *ReaderInfo) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *ReaderInfo) GetStaleSince() int64 {
+	if x != nil {
+		return x.StaleSince
+	}
+	return 0
+}
+
+func (x *ReaderInfo) GetConfigVersion() int64 {
+	if x != nil {
+		return x.ConfigVersion
+	}
+	return 0
+}
+
+func (x *ReaderInfo) GetConfigError() string {
+	if x != nil {
+		return x.ConfigError
+	}
+	return ""
+}
+
+// 設定受信リクエスト
+type WatchConfigRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId       string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"`                    // リーダーID
+	Site           string                 `protobuf:"bytes,2,opt,name=site,proto3" json:"site,omitempty"`                                            // 拠点（省略時は登録済みの拠点）
+	CurrentVersion int64                  `protobuf:"varint,3,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"` // 適用済みの設定のバージョン（同じバージョンの場合は送らない）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchConfigRequest) Reset() {
+	*x = WatchConfigRequest{}
+	mi := &file_license_license_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchConfigRequest) ProtoMessage() {}
+
+func (x *WatchConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchConfigRequest.ProtoReflect.Descriptor instead.
+func (*WatchConfigRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchConfigRequest) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *WatchConfigRequest) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *WatchConfigRequest) GetCurrentVersion() int64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+// リーダーの設定（全体・拠点・リーダーの設定を順に重ねたもの）
+type ReaderConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`                              // 設定のバージョン
+	SettingsJson  string                 `protobuf:"bytes,2,opt,name=settings_json,json=settingsJson,proto3" json:"settings_json,omitempty"` // 設定（JSON）
+	UpdatedAt     int64                  `protobuf:"varint,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`         // 更新時刻（Unix時刻）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReaderConfig) Reset() {
+	*x = ReaderConfig{}
+	mi := &file_license_license_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReaderConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReaderConfig) ProtoMessage() {}
+
+func (x *ReaderConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReaderConfig.ProtoReflect.Descriptor instead.
+func (*ReaderConfig) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ReaderConfig) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ReaderConfig) GetSettingsJson() string {
+	if x != nil {
+		return x.SettingsJson
+	}
+	return ""
+}
+
+func (x *ReaderConfig) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+// 設定の適用結果の報告リクエスト
+type ReportConfigStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId      string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"` // リーダーID
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`                  // 設定のバージョン
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                       // 適用に失敗した場合のエラー（空の場合は適用済み）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportConfigStatusRequest) Reset() {
+	*x = ReportConfigStatusRequest{}
+	mi := &file_license_license_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportConfigStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportConfigStatusRequest) ProtoMessage() {}
+
+func (x *ReportConfigStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportConfigStatusRequest.ProtoReflect.Descriptor instead.
+func (*ReportConfigStatusRequest) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReportConfigStatusRequest) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *ReportConfigStatusRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ReportConfigStatusRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 設定の適用結果の報告レスポンス
+type ReportConfigStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportConfigStatusResponse) Reset() {
+	*x = ReportConfigStatusResponse{}
+	mi := &file_license_license_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportConfigStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportConfigStatusResponse) ProtoMessage() {}
+
+func (x *ReportConfigStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportConfigStatusResponse.ProtoReflect.Descriptor instead.
+func (*ReportConfigStatusResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReportConfigStatusResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReportConfigStatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 一括送信の1件（LicenseDataまたはReadLog）
+type UploadItem struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ReaderId string                 `protobuf:"bytes,1,opt,name=reader_id,json=readerId,proto3" json:"reader_id,omitempty"` // リーダーID（1つのストリームでは同じリーダーID）
+	Source   string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`                     // 送信元の識別子（リーダーのDBごと。DBを作り直した場合に連番が重複しないように）
+	Seq      int64                  `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`                          // 連番（送信元ごとに単調増加。確定済みの連番以下は重複として無視）
+	// Types that are valid to be assigned to Item:
+	//
+	//	*UploadItem_LicenseData
+	//	*UploadItem_ReadLog
+	Item          isUploadItem_Item `protobuf_oneof:"item"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadItem) Reset() {
+	*x = UploadItem{}
+	mi := &file_license_license_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadItem) ProtoMessage() {}
+
+func (x *UploadItem) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadItem.ProtoReflect.Descriptor instead.
+func (*UploadItem) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UploadItem) GetReaderId() string {
+	if x != nil {
+		return x.ReaderId
+	}
+	return ""
+}
+
+func (x *UploadItem) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *UploadItem) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *UploadItem) GetItem() isUploadItem_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *UploadItem) GetLicenseData() *LicenseData {
+	if x != nil {
+		if x, ok := x.Item.(*UploadItem_LicenseData); ok {
+			return x.LicenseData
+		}
+	}
+	return nil
+}
+
+func (x *UploadItem) GetReadLog() *ReadLog {
+	if x != nil {
+		if x, ok := x.Item.(*UploadItem_ReadLog); ok {
+			return x.ReadLog
+		}
+	}
+	return nil
+}
+
+type isUploadItem_Item interface {
+	isUploadItem_Item()
+}
+
+type UploadItem_LicenseData struct {
+	LicenseData *LicenseData `protobuf:"bytes,4,opt,name=license_data,json=licenseData,proto3,oneof"`
+}
+
+type UploadItem_ReadLog struct {
+	ReadLog *ReadLog `protobuf:"bytes,5,opt,name=read_log,json=readLog,proto3,oneof"`
+}
+
+func (*UploadItem_LicenseData) isUploadItem_Item() {}
+
+func (*UploadItem_ReadLog) isUploadItem_Item() {}
+
+// 一括送信レスポンス
+type UploadBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AckedSeq      int64                  `protobuf:"varint,1,opt,name=acked_seq,json=ackedSeq,proto3" json:"acked_seq,omitempty"`   // DBに確定した最大の連番（これ以下は再送不要）
+	Received      int32                  `protobuf:"varint,2,opt,name=received,proto3" json:"received,omitempty"`                   // 受信した件数
+	Committed     int32                  `protobuf:"varint,3,opt,name=committed,proto3" json:"committed,omitempty"`                 // 新たに記録した件数
+	Duplicates    int32                  `protobuf:"varint,4,opt,name=duplicates,proto3" json:"duplicates,omitempty"`               // 確定済みのため無視した件数
+	RequestId     string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // リクエストID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadBatchResponse) Reset() {
+	*x = UploadBatchResponse{}
+	mi := &file_license_license_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBatchResponse) ProtoMessage() {}
+
+func (x *UploadBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_license_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBatchResponse.ProtoReflect.Descriptor instead.
+func (*UploadBatchResponse) Descriptor() ([]byte, []int) {
+	return file_license_license_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UploadBatchResponse) GetAckedSeq() int64 {
+	if x != nil {
+		return x.AckedSeq
+	}
+	return 0
+}
+
+func (x *UploadBatchResponse) GetReceived() int32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *UploadBatchResponse) GetCommitted() int32 {
+	if x != nil {
+		return x.Committed
+	}
+	return 0
+}
+
+func (x *UploadBatchResponse) GetDuplicates() int32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *UploadBatchResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+var File_license_license_proto protoreflect.FileDescriptor
+
+const file_license_license_proto_rawDesc = "" +
+	"\n" +
+	"\x15license/license.proto\x12\alicense\"\x97\x03\n" +
+	"\vLicenseData\x12\x17\n" +
+	"\acard_id\x18\x01 \x01(\tR\x06cardId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tname_kana\x18\x03 \x01(\tR\bnameKana\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\x04 \x01(\tR\tbirthDate\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\x12\x1d\n" +
+	"\n" +
+	"issue_date\x18\x06 \x01(\tR\tissueDate\x12\x1f\n" +
+	"\vexpiry_date\x18\a \x01(\tR\n" +
+	"expiryDate\x12%\n" +
+	"\x0elicense_number\x18\b \x01(\tR\rlicenseNumber\x12!\n" +
+	"\flicense_type\x18\t \x01(\tR\vlicenseType\x12\x14\n" +
+	"\x05photo\x18\n" +
+	" \x01(\fR\x05photo\x12%\n" +
+	"\x0eread_timestamp\x18\v \x01(\x03R\rreadTimestamp\x12\x1b\n" +
+	"\treader_id\x18\f \x01(\tR\breaderId\x12!\n" +
+	"\ftrace_parent\x18\r \x01(\tR\vtraceParent\"\xda\x01\n" +
+	"\aReadLog\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1b\n" +
+	"\treader_id\x18\x02 \x01(\tR\breaderId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\x12\x17\n" +
+	"\acard_id\x18\x05 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tcard_type\x18\x06 \x01(\tR\bcardType\x12!\n" +
+	"\ftrace_parent\x18\a \x01(\tR\vtraceParent\"\xd6\x01\n" +
+	"\n" +
+	"Assignment\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12&\n" +
+	"\x0flicense_card_id\x18\x02 \x01(\tR\rlicenseCardId\x12\x1b\n" +
+	"\tdriver_id\x18\x03 \x01(\x05R\bdriverId\x12&\n" +
+	"\x0fvehicle_card_id\x18\x04 \x01(\tR\rvehicleCardId\x12\x1d\n" +
+	"\n" +
+	"vehicle_id\x18\x05 \x01(\tR\tvehicleId\x12\x1f\n" +
+	"\vassigned_at\x18\x06 \x01(\x03R\n" +
+	"assignedAt\"a\n" +
+	"\fPushResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"\x99\x02\n" +
+	"\x0eGetLogsRequest\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x17\n" +
+	"\acard_id\x18\x06 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\x121\n" +
+	"\n" +
+	"sort_order\x18\b \x01(\x0e2\x12.license.SortOrderR\tsortOrder\x12\x19\n" +
+	"\bcard_ref\x18\t \x01(\tR\acardRef\"\x81\x01\n" +
+	"\x0fGetLogsResponse\x12%\n" +
+	"\x04logs\x18\x01 \x03(\v2\x11.license.LogEntryR\x04logs\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xcf\x01\n" +
+	"\bLogEntry\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
 	"\x05level\x18\x02 \x01(\tR\x05level\x12\x18\n" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1b\n" +
-	"\treader_id\x18\x04 \x01(\tR\breaderId\"\x9c\x01\n" +
+	"\treader_id\x18\x04 \x01(\tR\breaderId\x12\x17\n" +
+	"\acard_id\x18\x05 \x01(\tR\x06cardId\x12\x0e\n" +
+	"\x02id\x18\x06 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05attrs\x18\a \x01(\tR\x05attrs\x12\x19\n" +
+	"\bcard_ref\x18\b \x01(\tR\acardRef\"\xbf\x02\n" +
 	"\x15GetReadHistoryRequest\x12\x1b\n" +
 	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
 	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
 	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
-	"\x05limit\x18\x05 \x01(\x05R\x05limit\"n\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x17\n" +
+	"\acard_id\x18\x06 \x01(\tR\x06cardId\x12\x1b\n" +
+	"\tcard_type\x18\a \x01(\tR\bcardType\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\x121\n" +
+	"\n" +
+	"sort_order\x18\t \x01(\x0e2\x12.license.SortOrderR\tsortOrder\x12\x19\n" +
+	"\bcard_ref\x18\n" +
+	" \x01(\tR\acardRef\"\x96\x01\n" +
 	"\x16GetReadHistoryResponse\x123\n" +
 	"\aentries\x18\x01 \x03(\v2\x19.license.ReadHistoryEntryR\aentries\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\"\xb5\x02\n" +
+	"totalCount\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xe0\x02\n" +
 	"\x10ReadHistoryEntry\x12\x1c\n" +
 	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1b\n" +
 	"\treader_id\x18\x02 \x01(\tR\breaderId\x12\x17\n" +
@@ -811,12 +2847,178 @@ const file_license_license_proto_rawDesc = "" +
 	"felica_uid\x18\b \x01(\tR\tfelicaUid\x12\x16\n" +
 	"\x06status\x18\t \x01(\tR\x06status\x12#\n" +
 	"\rerror_message\x18\n" +
-	" \x01(\tR\ferrorMessage2\x98\x02\n" +
+	" \x01(\tR\ferrorMessage\x12\x0e\n" +
+	"\x02id\x18\v \x01(\x03R\x02id\x12\x19\n" +
+	"\bcard_ref\x18\f \x01(\tR\acardRef\"l\n" +
+	"\x13GetWorkHoursRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\"B\n" +
+	"\x14GetWorkHoursResponse\x12*\n" +
+	"\x06shifts\x18\x01 \x03(\v2\x12.license.WorkShiftR\x06shifts\"\x99\x03\n" +
+	"\tWorkShift\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\x12\x1b\n" +
+	"\twork_date\x18\x02 \x01(\tR\bworkDate\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12#\n" +
+	"\rtotal_minutes\x18\x05 \x01(\x05R\ftotalMinutes\x12#\n" +
+	"\rbreak_minutes\x18\x06 \x01(\x05R\fbreakMinutes\x12)\n" +
+	"\x10overtime_minutes\x18\a \x01(\x05R\x0fovertimeMinutes\x12,\n" +
+	"\x12late_night_minutes\x18\b \x01(\x05R\x10lateNightMinutes\x12(\n" +
+	"\x10missing_clock_in\x18\t \x01(\bR\x0emissingClockIn\x12*\n" +
+	"\x11missing_clock_out\x18\n" +
+	" \x01(\bR\x0fmissingClockOut\x12\x1f\n" +
+	"\vin_progress\x18\v \x01(\bR\n" +
+	"inProgress\"^\n" +
+	"\x14ListAnomaliesRequest\x12\x1b\n" +
+	"\twork_date\x18\x01 \x01(\tR\bworkDate\x12)\n" +
+	"\x10include_resolved\x18\x02 \x01(\bR\x0fincludeResolved\"G\n" +
+	"\x15ListAnomaliesResponse\x12.\n" +
+	"\tanomalies\x18\x01 \x03(\v2\x10.license.AnomalyR\tanomalies\"\xdf\x02\n" +
+	"\aAnomaly\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vdetected_at\x18\x02 \x01(\x03R\n" +
+	"detectedAt\x12\x1b\n" +
+	"\twork_date\x18\x03 \x01(\tR\bworkDate\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1b\n" +
+	"\tdriver_id\x18\x05 \x01(\x05R\bdriverId\x12\x17\n" +
+	"\acard_id\x18\x06 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"punch_time\x18\a \x01(\x03R\tpunchTime\x12\x16\n" +
+	"\x06detail\x18\b \x01(\tR\x06detail\x12\x1a\n" +
+	"\bresolved\x18\t \x01(\bR\bresolved\x12\x1f\n" +
+	"\vresolved_at\x18\n" +
+	" \x01(\x03R\n" +
+	"resolvedAt\x12\x1f\n" +
+	"\vresolved_by\x18\v \x01(\tR\n" +
+	"resolvedBy\x12'\n" +
+	"\x0fresolution_note\x18\f \x01(\tR\x0eresolutionNote\"\\\n" +
+	"\x15ResolveAnomalyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vresolved_by\x18\x02 \x01(\tR\n" +
+	"resolvedBy\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"L\n" +
+	"\x16ResolveAnomalyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9a\x01\n" +
+	"\x11WatchReadsRequest\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x1b\n" +
+	"\tcard_type\x18\x02 \x01(\tR\bcardType\x12(\n" +
+	"\x10resume_after_seq\x18\x03 \x01(\x04R\x0eresumeAfterSeq\x12!\n" +
+	"\fresume_epoch\x18\x04 \x01(\tR\vresumeEpoch\"\x80\x02\n" +
+	"\tReadEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x1b\n" +
+	"\treader_id\x18\x03 \x01(\tR\breaderId\x12\x1b\n" +
+	"\tcard_type\x18\x04 \x01(\tR\bcardType\x129\n" +
+	"\flicense_data\x18\x05 \x01(\v2\x14.license.LicenseDataH\x00R\vlicenseData\x12-\n" +
+	"\bread_log\x18\x06 \x01(\v2\x10.license.ReadLogH\x00R\areadLog\x12\x14\n" +
+	"\x05epoch\x18\a \x01(\tR\x05epochB\t\n" +
+	"\apayload\"\xda\x01\n" +
+	"\x15RegisterReaderRequest\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x12\n" +
+	"\x04site\x18\x02 \x01(\tR\x04site\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x05 \x01(\tR\tbuildTime\x12\x1f\n" +
+	"\vnfc_devices\x18\x06 \x03(\tR\n" +
+	"nfcDevices\x12\x1a\n" +
+	"\bhostname\x18\a \x01(\tR\bhostname\"\x8a\x01\n" +
+	"\x16RegisterReaderResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\x1aheartbeat_interval_seconds\x18\x03 \x01(\x05R\x18heartbeatIntervalSeconds\"V\n" +
+	"\x16ReaderHeartbeatRequest\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x1f\n" +
+	"\vnfc_devices\x18\x02 \x03(\tR\n" +
+	"nfcDevices\"\x8b\x01\n" +
+	"\x17ReaderHeartbeatResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\x1aheartbeat_interval_seconds\x18\x03 \x01(\x05R\x18heartbeatIntervalSeconds\"G\n" +
+	"\x12ListReadersRequest\x12\x12\n" +
+	"\x04site\x18\x01 \x01(\tR\x04site\x12\x1d\n" +
+	"\n" +
+	"stale_only\x18\x02 \x01(\bR\tstaleOnly\"t\n" +
+	"\x13ListReadersResponse\x12-\n" +
+	"\areaders\x18\x01 \x03(\v2\x13.license.ReaderInfoR\areaders\x12.\n" +
+	"\x13stale_after_seconds\x18\x02 \x01(\x05R\x11staleAfterSeconds\"\xaf\x03\n" +
+	"\n" +
+	"ReaderInfo\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x12\n" +
+	"\x04site\x18\x02 \x01(\tR\x04site\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x05 \x01(\tR\tbuildTime\x12\x1f\n" +
+	"\vnfc_devices\x18\x06 \x03(\tR\n" +
+	"nfcDevices\x12\x1a\n" +
+	"\bhostname\x18\a \x01(\tR\bhostname\x12\x1b\n" +
+	"\tpeer_addr\x18\b \x01(\tR\bpeerAddr\x12#\n" +
+	"\rregistered_at\x18\t \x01(\x03R\fregisteredAt\x12\x1b\n" +
+	"\tlast_seen\x18\n" +
+	" \x01(\x03R\blastSeen\x12\x14\n" +
+	"\x05stale\x18\v \x01(\bR\x05stale\x12\x1f\n" +
+	"\vstale_since\x18\f \x01(\x03R\n" +
+	"staleSince\x12%\n" +
+	"\x0econfig_version\x18\r \x01(\x03R\rconfigVersion\x12!\n" +
+	"\fconfig_error\x18\x0e \x01(\tR\vconfigError\"n\n" +
+	"\x12WatchConfigRequest\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x12\n" +
+	"\x04site\x18\x02 \x01(\tR\x04site\x12'\n" +
+	"\x0fcurrent_version\x18\x03 \x01(\x03R\x0ecurrentVersion\"l\n" +
+	"\fReaderConfig\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12#\n" +
+	"\rsettings_json\x18\x02 \x01(\tR\fsettingsJson\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\x03R\tupdatedAt\"h\n" +
+	"\x19ReportConfigStatusRequest\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"P\n" +
+	"\x1aReportConfigStatusResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xc5\x01\n" +
+	"\n" +
+	"UploadItem\x12\x1b\n" +
+	"\treader_id\x18\x01 \x01(\tR\breaderId\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\x03R\x03seq\x129\n" +
+	"\flicense_data\x18\x04 \x01(\v2\x14.license.LicenseDataH\x00R\vlicenseData\x12-\n" +
+	"\bread_log\x18\x05 \x01(\v2\x10.license.ReadLogH\x00R\areadLogB\x06\n" +
+	"\x04item\"\xab\x01\n" +
+	"\x13UploadBatchResponse\x12\x1b\n" +
+	"\tacked_seq\x18\x01 \x01(\x03R\backedSeq\x12\x1a\n" +
+	"\breceived\x18\x02 \x01(\x05R\breceived\x12\x1c\n" +
+	"\tcommitted\x18\x03 \x01(\x05R\tcommitted\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x04 \x01(\x05R\n" +
+	"duplicates\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId*E\n" +
+	"\tSortOrder\x12\x1b\n" +
+	"\x17SORT_ORDER_NEWEST_FIRST\x10\x00\x12\x1b\n" +
+	"\x17SORT_ORDER_OLDEST_FIRST\x10\x012\xe1\b\n" +
 	"\rLicenseReader\x12>\n" +
 	"\x0fPushLicenseData\x12\x14.license.LicenseData\x1a\x15.license.PushResponse\x126\n" +
 	"\vPushReadLog\x12\x10.license.ReadLog\x1a\x15.license.PushResponse\x12<\n" +
 	"\aGetLogs\x12\x17.license.GetLogsRequest\x1a\x18.license.GetLogsResponse\x12Q\n" +
-	"\x0eGetReadHistory\x12\x1e.license.GetReadHistoryRequest\x1a\x1f.license.GetReadHistoryResponseB\x19Z\x17menkyo_go/proto/licenseb\x06proto3"
+	"\x0eGetReadHistory\x12\x1e.license.GetReadHistoryRequest\x1a\x1f.license.GetReadHistoryResponse\x12<\n" +
+	"\x0ePushAssignment\x12\x13.license.Assignment\x1a\x15.license.PushResponse\x12K\n" +
+	"\fGetWorkHours\x12\x1c.license.GetWorkHoursRequest\x1a\x1d.license.GetWorkHoursResponse\x12N\n" +
+	"\rListAnomalies\x12\x1d.license.ListAnomaliesRequest\x1a\x1e.license.ListAnomaliesResponse\x12Q\n" +
+	"\x0eResolveAnomaly\x12\x1e.license.ResolveAnomalyRequest\x1a\x1f.license.ResolveAnomalyResponse\x12>\n" +
+	"\n" +
+	"WatchReads\x12\x1a.license.WatchReadsRequest\x1a\x12.license.ReadEvent0\x01\x12Q\n" +
+	"\x0eRegisterReader\x12\x1e.license.RegisterReaderRequest\x1a\x1f.license.RegisterReaderResponse\x12T\n" +
+	"\x0fReaderHeartbeat\x12\x1f.license.ReaderHeartbeatRequest\x1a .license.ReaderHeartbeatResponse\x12H\n" +
+	"\vListReaders\x12\x1b.license.ListReadersRequest\x1a\x1c.license.ListReadersResponse\x12C\n" +
+	"\vWatchConfig\x12\x1b.license.WatchConfigRequest\x1a\x15.license.ReaderConfig0\x01\x12]\n" +
+	"\x12ReportConfigStatus\x12\".license.ReportConfigStatusRequest\x1a#.license.ReportConfigStatusResponse\x12B\n" +
+	"\vUploadBatch\x12\x13.license.UploadItem\x1a\x1c.license.UploadBatchResponse(\x01B\x19Z\x17menkyo_go/proto/licenseb\x06proto3"
 
 var (
 	file_license_license_proto_rawDescOnce sync.Once
@@ -830,34 +3032,91 @@ func file_license_license_proto_rawDescGZIP() []byte {
 	return file_license_license_proto_rawDescData
 }
 
-var file_license_license_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_license_license_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_license_license_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_license_license_proto_goTypes = []any{
-	(*LicenseData)(nil),            // 0: license.LicenseData
-	(*ReadLog)(nil),                // 1: license.ReadLog
-	(*PushResponse)(nil),           // 2: license.PushResponse
-	(*GetLogsRequest)(nil),         // 3: license.GetLogsRequest
-	(*GetLogsResponse)(nil),        // 4: license.GetLogsResponse
-	(*LogEntry)(nil),               // 5: license.LogEntry
-	(*GetReadHistoryRequest)(nil),  // 6: license.GetReadHistoryRequest
-	(*GetReadHistoryResponse)(nil), // 7: license.GetReadHistoryResponse
-	(*ReadHistoryEntry)(nil),       // 8: license.ReadHistoryEntry
+	(SortOrder)(0),                     // 0: license.SortOrder
+	(*LicenseData)(nil),                // 1: license.LicenseData
+	(*ReadLog)(nil),                    // 2: license.ReadLog
+	(*Assignment)(nil),                 // 3: license.Assignment
+	(*PushResponse)(nil),               // 4: license.PushResponse
+	(*GetLogsRequest)(nil),             // 5: license.GetLogsRequest
+	(*GetLogsResponse)(nil),            // 6: license.GetLogsResponse
+	(*LogEntry)(nil),                   // 7: license.LogEntry
+	(*GetReadHistoryRequest)(nil),      // 8: license.GetReadHistoryRequest
+	(*GetReadHistoryResponse)(nil),     // 9: license.GetReadHistoryResponse
+	(*ReadHistoryEntry)(nil),           // 10: license.ReadHistoryEntry
+	(*GetWorkHoursRequest)(nil),        // 11: license.GetWorkHoursRequest
+	(*GetWorkHoursResponse)(nil),       // 12: license.GetWorkHoursResponse
+	(*WorkShift)(nil),                  // 13: license.WorkShift
+	(*ListAnomaliesRequest)(nil),       // 14: license.ListAnomaliesRequest
+	(*ListAnomaliesResponse)(nil),      // 15: license.ListAnomaliesResponse
+	(*Anomaly)(nil),                    // 16: license.Anomaly
+	(*ResolveAnomalyRequest)(nil),      // 17: license.ResolveAnomalyRequest
+	(*ResolveAnomalyResponse)(nil),     // 18: license.ResolveAnomalyResponse
+	(*WatchReadsRequest)(nil),          // 19: license.WatchReadsRequest
+	(*ReadEvent)(nil),                  // 20: license.ReadEvent
+	(*RegisterReaderRequest)(nil),      // 21: license.RegisterReaderRequest
+	(*RegisterReaderResponse)(nil),     // 22: license.RegisterReaderResponse
+	(*ReaderHeartbeatRequest)(nil),     // 23: license.ReaderHeartbeatRequest
+	(*ReaderHeartbeatResponse)(nil),    // 24: license.ReaderHeartbeatResponse
+	(*ListReadersRequest)(nil),         // 25: license.ListReadersRequest
+	(*ListReadersResponse)(nil),        // 26: license.ListReadersResponse
+	(*ReaderInfo)(nil),                 // 27: license.ReaderInfo
+	(*WatchConfigRequest)(nil),         // 28: license.WatchConfigRequest
+	(*ReaderConfig)(nil),               // 29: license.ReaderConfig
+	(*ReportConfigStatusRequest)(nil),  // 30: license.ReportConfigStatusRequest
+	(*ReportConfigStatusResponse)(nil), // 31: license.ReportConfigStatusResponse
+	(*UploadItem)(nil),                 // 32: license.UploadItem
+	(*UploadBatchResponse)(nil),        // 33: license.UploadBatchResponse
 }
 var file_license_license_proto_depIdxs = []int32{
-	5, // 0: license.GetLogsResponse.logs:type_name -> license.LogEntry
-	8, // 1: license.GetReadHistoryResponse.entries:type_name -> license.ReadHistoryEntry
-	0, // 2: license.LicenseReader.PushLicenseData:input_type -> license.LicenseData
-	1, // 3: license.LicenseReader.PushReadLog:input_type -> license.ReadLog
-	3, // 4: license.LicenseReader.GetLogs:input_type -> license.GetLogsRequest
-	6, // 5: license.LicenseReader.GetReadHistory:input_type -> license.GetReadHistoryRequest
-	2, // 6: license.LicenseReader.PushLicenseData:output_type -> license.PushResponse
-	2, // 7: license.LicenseReader.PushReadLog:output_type -> license.PushResponse
-	4, // 8: license.LicenseReader.GetLogs:output_type -> license.GetLogsResponse
-	7, // 9: license.LicenseReader.GetReadHistory:output_type -> license.GetReadHistoryResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: license.GetLogsRequest.sort_order:type_name -> license.SortOrder
+	7,  // 1: license.GetLogsResponse.logs:type_name -> license.LogEntry
+	0,  // 2: license.GetReadHistoryRequest.sort_order:type_name -> license.SortOrder
+	10, // 3: license.GetReadHistoryResponse.entries:type_name -> license.ReadHistoryEntry
+	13, // 4: license.GetWorkHoursResponse.shifts:type_name -> license.WorkShift
+	16, // 5: license.ListAnomaliesResponse.anomalies:type_name -> license.Anomaly
+	1,  // 6: license.ReadEvent.license_data:type_name -> license.LicenseData
+	2,  // 7: license.ReadEvent.read_log:type_name -> license.ReadLog
+	27, // 8: license.ListReadersResponse.readers:type_name -> license.ReaderInfo
+	1,  // 9: license.UploadItem.license_data:type_name -> license.LicenseData
+	2,  // 10: license.UploadItem.read_log:type_name -> license.ReadLog
+	1,  // 11: license.LicenseReader.PushLicenseData:input_type -> license.LicenseData
+	2,  // 12: license.LicenseReader.PushReadLog:input_type -> license.ReadLog
+	5,  // 13: license.LicenseReader.GetLogs:input_type -> license.GetLogsRequest
+	8,  // 14: license.LicenseReader.GetReadHistory:input_type -> license.GetReadHistoryRequest
+	3,  // 15: license.LicenseReader.PushAssignment:input_type -> license.Assignment
+	11, // 16: license.LicenseReader.GetWorkHours:input_type -> license.GetWorkHoursRequest
+	14, // 17: license.LicenseReader.ListAnomalies:input_type -> license.ListAnomaliesRequest
+	17, // 18: license.LicenseReader.ResolveAnomaly:input_type -> license.ResolveAnomalyRequest
+	19, // 19: license.LicenseReader.WatchReads:input_type -> license.WatchReadsRequest
+	21, // 20: license.LicenseReader.RegisterReader:input_type -> license.RegisterReaderRequest
+	23, // 21: license.LicenseReader.ReaderHeartbeat:input_type -> license.ReaderHeartbeatRequest
+	25, // 22: license.LicenseReader.ListReaders:input_type -> license.ListReadersRequest
+	28, // 23: license.LicenseReader.WatchConfig:input_type -> license.WatchConfigRequest
+	30, // 24: license.LicenseReader.ReportConfigStatus:input_type -> license.ReportConfigStatusRequest
+	32, // 25: license.LicenseReader.UploadBatch:input_type -> license.UploadItem
+	4,  // 26: license.LicenseReader.PushLicenseData:output_type -> license.PushResponse
+	4,  // 27: license.LicenseReader.PushReadLog:output_type -> license.PushResponse
+	6,  // 28: license.LicenseReader.GetLogs:output_type -> license.GetLogsResponse
+	9,  // 29: license.LicenseReader.GetReadHistory:output_type -> license.GetReadHistoryResponse
+	4,  // 30: license.LicenseReader.PushAssignment:output_type -> license.PushResponse
+	12, // 31: license.LicenseReader.GetWorkHours:output_type -> license.GetWorkHoursResponse
+	15, // 32: license.LicenseReader.ListAnomalies:output_type -> license.ListAnomaliesResponse
+	18, // 33: license.LicenseReader.ResolveAnomaly:output_type -> license.ResolveAnomalyResponse
+	20, // 34: license.LicenseReader.WatchReads:output_type -> license.ReadEvent
+	22, // 35: license.LicenseReader.RegisterReader:output_type -> license.RegisterReaderResponse
+	24, // 36: license.LicenseReader.ReaderHeartbeat:output_type -> license.ReaderHeartbeatResponse
+	26, // 37: license.LicenseReader.ListReaders:output_type -> license.ListReadersResponse
+	29, // 38: license.LicenseReader.WatchConfig:output_type -> license.ReaderConfig
+	31, // 39: license.LicenseReader.ReportConfigStatus:output_type -> license.ReportConfigStatusResponse
+	33, // 40: license.LicenseReader.UploadBatch:output_type -> license.UploadBatchResponse
+	26, // [26:41] is the sub-list for method output_type
+	11, // [11:26] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_license_license_proto_init() }
@@ -865,18 +3124,27 @@ func file_license_license_proto_init() {
 	if File_license_license_proto != nil {
 		return
 	}
+	file_license_license_proto_msgTypes[19].OneofWrappers = []any{
+		(*ReadEvent_LicenseData)(nil),
+		(*ReadEvent_ReadLog)(nil),
+	}
+	file_license_license_proto_msgTypes[31].OneofWrappers = []any{
+		(*UploadItem_LicenseData)(nil),
+		(*UploadItem_ReadLog)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_license_license_proto_rawDesc), len(file_license_license_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      1,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_license_license_proto_goTypes,
 		DependencyIndexes: file_license_license_proto_depIdxs,
+		EnumInfos:         file_license_license_proto_enumTypes,
 		MessageInfos:      file_license_license_proto_msgTypes,
 	}.Build()
 	File_license_license_proto = out.File
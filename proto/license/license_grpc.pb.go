@@ -19,10 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LicenseReader_PushLicenseData_FullMethodName = "/license.LicenseReader/PushLicenseData"
-	LicenseReader_PushReadLog_FullMethodName     = "/license.LicenseReader/PushReadLog"
-	LicenseReader_GetLogs_FullMethodName         = "/license.LicenseReader/GetLogs"
-	LicenseReader_GetReadHistory_FullMethodName  = "/license.LicenseReader/GetReadHistory"
+	LicenseReader_PushLicenseData_FullMethodName    = "/license.LicenseReader/PushLicenseData"
+	LicenseReader_PushReadLog_FullMethodName        = "/license.LicenseReader/PushReadLog"
+	LicenseReader_GetLogs_FullMethodName            = "/license.LicenseReader/GetLogs"
+	LicenseReader_GetReadHistory_FullMethodName     = "/license.LicenseReader/GetReadHistory"
+	LicenseReader_PushAssignment_FullMethodName     = "/license.LicenseReader/PushAssignment"
+	LicenseReader_GetWorkHours_FullMethodName       = "/license.LicenseReader/GetWorkHours"
+	LicenseReader_ListAnomalies_FullMethodName      = "/license.LicenseReader/ListAnomalies"
+	LicenseReader_ResolveAnomaly_FullMethodName     = "/license.LicenseReader/ResolveAnomaly"
+	LicenseReader_WatchReads_FullMethodName         = "/license.LicenseReader/WatchReads"
+	LicenseReader_RegisterReader_FullMethodName     = "/license.LicenseReader/RegisterReader"
+	LicenseReader_ReaderHeartbeat_FullMethodName    = "/license.LicenseReader/ReaderHeartbeat"
+	LicenseReader_ListReaders_FullMethodName        = "/license.LicenseReader/ListReaders"
+	LicenseReader_WatchConfig_FullMethodName        = "/license.LicenseReader/WatchConfig"
+	LicenseReader_ReportConfigStatus_FullMethodName = "/license.LicenseReader/ReportConfigStatus"
+	LicenseReader_UploadBatch_FullMethodName        = "/license.LicenseReader/UploadBatch"
 )
 
 // LicenseReaderClient is the client API for LicenseReader service.
@@ -39,6 +50,28 @@ type LicenseReaderClient interface {
 	GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (*GetLogsResponse, error)
 	// 読み取り履歴を取得
 	GetReadHistory(ctx context.Context, in *GetReadHistoryRequest, opts ...grpc.CallOption) (*GetReadHistoryResponse, error)
+	// 配車（運転者と車両の割り当て）をプッシュ
+	PushAssignment(ctx context.Context, in *Assignment, opts ...grpc.CallOption) (*PushResponse, error)
+	// 勤務時間の集計を取得（給与計算用）
+	GetWorkHours(ctx context.Context, in *GetWorkHoursRequest, opts ...grpc.CallOption) (*GetWorkHoursResponse, error)
+	// 打刻の異常一覧を取得
+	ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*ListAnomaliesResponse, error)
+	// 打刻の異常を解決済みにする
+	ResolveAnomaly(ctx context.Context, in *ResolveAnomalyRequest, opts ...grpc.CallOption) (*ResolveAnomalyResponse, error)
+	// 読み取りイベントをリアルタイムで受信（PushLicenseData/PushReadLogを配信）
+	WatchReads(ctx context.Context, in *WatchReadsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadEvent], error)
+	// リーダーを登録（起動時。登録済みの場合は情報を更新）
+	RegisterReader(ctx context.Context, in *RegisterReaderRequest, opts ...grpc.CallOption) (*RegisterReaderResponse, error)
+	// リーダーの死活監視（登録されていない場合はNOT_FOUND）
+	ReaderHeartbeat(ctx context.Context, in *ReaderHeartbeatRequest, opts ...grpc.CallOption) (*ReaderHeartbeatResponse, error)
+	// 登録済みのリーダー一覧を取得
+	ListReaders(ctx context.Context, in *ListReadersRequest, opts ...grpc.CallOption) (*ListReadersResponse, error)
+	// リーダーの設定を受信（接続時と設定の変更時に配信）
+	WatchConfig(ctx context.Context, in *WatchConfigRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReaderConfig], error)
+	// 設定の適用結果を報告
+	ReportConfigStatus(ctx context.Context, in *ReportConfigStatusRequest, opts ...grpc.CallOption) (*ReportConfigStatusResponse, error)
+	// 未送信の読み取りをまとめて送信（障害復旧後のバックログ送信用）
+	UploadBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadItem, UploadBatchResponse], error)
 }
 
 type licenseReaderClient struct {
@@ -89,6 +122,137 @@ func (c *licenseReaderClient) GetReadHistory(ctx context.Context, in *GetReadHis
 	return out, nil
 }
 
+func (c *licenseReaderClient) PushAssignment(ctx context.Context, in *Assignment, opts ...grpc.CallOption) (*PushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_PushAssignment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) GetWorkHours(ctx context.Context, in *GetWorkHoursRequest, opts ...grpc.CallOption) (*GetWorkHoursResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWorkHoursResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_GetWorkHours_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*ListAnomaliesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAnomaliesResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_ListAnomalies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) ResolveAnomaly(ctx context.Context, in *ResolveAnomalyRequest, opts ...grpc.CallOption) (*ResolveAnomalyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveAnomalyResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_ResolveAnomaly_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) WatchReads(ctx context.Context, in *WatchReadsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LicenseReader_ServiceDesc.Streams[0], LicenseReader_WatchReads_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchReadsRequest, ReadEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LicenseReader_WatchReadsClient = grpc.ServerStreamingClient[ReadEvent]
+
+func (c *licenseReaderClient) RegisterReader(ctx context.Context, in *RegisterReaderRequest, opts ...grpc.CallOption) (*RegisterReaderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterReaderResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_RegisterReader_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) ReaderHeartbeat(ctx context.Context, in *ReaderHeartbeatRequest, opts ...grpc.CallOption) (*ReaderHeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReaderHeartbeatResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_ReaderHeartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) ListReaders(ctx context.Context, in *ListReadersRequest, opts ...grpc.CallOption) (*ListReadersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReadersResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_ListReaders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) WatchConfig(ctx context.Context, in *WatchConfigRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReaderConfig], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LicenseReader_ServiceDesc.Streams[1], LicenseReader_WatchConfig_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchConfigRequest, ReaderConfig]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LicenseReader_WatchConfigClient = grpc.ServerStreamingClient[ReaderConfig]
+
+func (c *licenseReaderClient) ReportConfigStatus(ctx context.Context, in *ReportConfigStatusRequest, opts ...grpc.CallOption) (*ReportConfigStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportConfigStatusResponse)
+	err := c.cc.Invoke(ctx, LicenseReader_ReportConfigStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *licenseReaderClient) UploadBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadItem, UploadBatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LicenseReader_ServiceDesc.Streams[2], LicenseReader_UploadBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadItem, UploadBatchResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LicenseReader_UploadBatchClient = grpc.ClientStreamingClient[UploadItem, UploadBatchResponse]
+
 // LicenseReaderServer is the server API for LicenseReader service.
 // All implementations must embed UnimplementedLicenseReaderServer
 // for forward compatibility.
@@ -103,6 +267,28 @@ type LicenseReaderServer interface {
 	GetLogs(context.Context, *GetLogsRequest) (*GetLogsResponse, error)
 	// 読み取り履歴を取得
 	GetReadHistory(context.Context, *GetReadHistoryRequest) (*GetReadHistoryResponse, error)
+	// 配車（運転者と車両の割り当て）をプッシュ
+	PushAssignment(context.Context, *Assignment) (*PushResponse, error)
+	// 勤務時間の集計を取得（給与計算用）
+	GetWorkHours(context.Context, *GetWorkHoursRequest) (*GetWorkHoursResponse, error)
+	// 打刻の異常一覧を取得
+	ListAnomalies(context.Context, *ListAnomaliesRequest) (*ListAnomaliesResponse, error)
+	// 打刻の異常を解決済みにする
+	ResolveAnomaly(context.Context, *ResolveAnomalyRequest) (*ResolveAnomalyResponse, error)
+	// 読み取りイベントをリアルタイムで受信（PushLicenseData/PushReadLogを配信）
+	WatchReads(*WatchReadsRequest, grpc.ServerStreamingServer[ReadEvent]) error
+	// リーダーを登録（起動時。登録済みの場合は情報を更新）
+	RegisterReader(context.Context, *RegisterReaderRequest) (*RegisterReaderResponse, error)
+	// リーダーの死活監視（登録されていない場合はNOT_FOUND）
+	ReaderHeartbeat(context.Context, *ReaderHeartbeatRequest) (*ReaderHeartbeatResponse, error)
+	// 登録済みのリーダー一覧を取得
+	ListReaders(context.Context, *ListReadersRequest) (*ListReadersResponse, error)
+	// リーダーの設定を受信（接続時と設定の変更時に配信）
+	WatchConfig(*WatchConfigRequest, grpc.ServerStreamingServer[ReaderConfig]) error
+	// 設定の適用結果を報告
+	ReportConfigStatus(context.Context, *ReportConfigStatusRequest) (*ReportConfigStatusResponse, error)
+	// 未送信の読み取りをまとめて送信（障害復旧後のバックログ送信用）
+	UploadBatch(grpc.ClientStreamingServer[UploadItem, UploadBatchResponse]) error
 	mustEmbedUnimplementedLicenseReaderServer()
 }
 
@@ -125,6 +311,39 @@ func (UnimplementedLicenseReaderServer) GetLogs(context.Context, *GetLogsRequest
 func (UnimplementedLicenseReaderServer) GetReadHistory(context.Context, *GetReadHistoryRequest) (*GetReadHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetReadHistory not implemented")
 }
+func (UnimplementedLicenseReaderServer) PushAssignment(context.Context, *Assignment) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushAssignment not implemented")
+}
+func (UnimplementedLicenseReaderServer) GetWorkHours(context.Context, *GetWorkHoursRequest) (*GetWorkHoursResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkHours not implemented")
+}
+func (UnimplementedLicenseReaderServer) ListAnomalies(context.Context, *ListAnomaliesRequest) (*ListAnomaliesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnomalies not implemented")
+}
+func (UnimplementedLicenseReaderServer) ResolveAnomaly(context.Context, *ResolveAnomalyRequest) (*ResolveAnomalyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveAnomaly not implemented")
+}
+func (UnimplementedLicenseReaderServer) WatchReads(*WatchReadsRequest, grpc.ServerStreamingServer[ReadEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchReads not implemented")
+}
+func (UnimplementedLicenseReaderServer) RegisterReader(context.Context, *RegisterReaderRequest) (*RegisterReaderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterReader not implemented")
+}
+func (UnimplementedLicenseReaderServer) ReaderHeartbeat(context.Context, *ReaderHeartbeatRequest) (*ReaderHeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReaderHeartbeat not implemented")
+}
+func (UnimplementedLicenseReaderServer) ListReaders(context.Context, *ListReadersRequest) (*ListReadersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReaders not implemented")
+}
+func (UnimplementedLicenseReaderServer) WatchConfig(*WatchConfigRequest, grpc.ServerStreamingServer[ReaderConfig]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchConfig not implemented")
+}
+func (UnimplementedLicenseReaderServer) ReportConfigStatus(context.Context, *ReportConfigStatusRequest) (*ReportConfigStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportConfigStatus not implemented")
+}
+func (UnimplementedLicenseReaderServer) UploadBatch(grpc.ClientStreamingServer[UploadItem, UploadBatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBatch not implemented")
+}
 func (UnimplementedLicenseReaderServer) mustEmbedUnimplementedLicenseReaderServer() {}
 func (UnimplementedLicenseReaderServer) testEmbeddedByValue()                       {}
 
@@ -218,6 +437,179 @@ func _LicenseReader_GetReadHistory_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LicenseReader_PushAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Assignment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).PushAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_PushAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).PushAssignment(ctx, req.(*Assignment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_GetWorkHours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkHoursRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).GetWorkHours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_GetWorkHours_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).GetWorkHours(ctx, req.(*GetWorkHoursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_ListAnomalies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAnomaliesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).ListAnomalies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_ListAnomalies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).ListAnomalies(ctx, req.(*ListAnomaliesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_ResolveAnomaly_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAnomalyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).ResolveAnomaly(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_ResolveAnomaly_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).ResolveAnomaly(ctx, req.(*ResolveAnomalyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_WatchReads_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchReadsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LicenseReaderServer).WatchReads(m, &grpc.GenericServerStream[WatchReadsRequest, ReadEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LicenseReader_WatchReadsServer = grpc.ServerStreamingServer[ReadEvent]
+
+func _LicenseReader_RegisterReader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterReaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).RegisterReader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_RegisterReader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).RegisterReader(ctx, req.(*RegisterReaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_ReaderHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReaderHeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).ReaderHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_ReaderHeartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).ReaderHeartbeat(ctx, req.(*ReaderHeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_ListReaders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReadersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).ListReaders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_ListReaders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).ListReaders(ctx, req.(*ListReadersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_WatchConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchConfigRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LicenseReaderServer).WatchConfig(m, &grpc.GenericServerStream[WatchConfigRequest, ReaderConfig]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LicenseReader_WatchConfigServer = grpc.ServerStreamingServer[ReaderConfig]
+
+func _LicenseReader_ReportConfigStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportConfigStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LicenseReaderServer).ReportConfigStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LicenseReader_ReportConfigStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LicenseReaderServer).ReportConfigStatus(ctx, req.(*ReportConfigStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LicenseReader_UploadBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LicenseReaderServer).UploadBatch(&grpc.GenericServerStream[UploadItem, UploadBatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LicenseReader_UploadBatchServer = grpc.ClientStreamingServer[UploadItem, UploadBatchResponse]
+
 // LicenseReader_ServiceDesc is the grpc.ServiceDesc for LicenseReader service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -241,7 +633,55 @@ var LicenseReader_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetReadHistory",
 			Handler:    _LicenseReader_GetReadHistory_Handler,
 		},
+		{
+			MethodName: "PushAssignment",
+			Handler:    _LicenseReader_PushAssignment_Handler,
+		},
+		{
+			MethodName: "GetWorkHours",
+			Handler:    _LicenseReader_GetWorkHours_Handler,
+		},
+		{
+			MethodName: "ListAnomalies",
+			Handler:    _LicenseReader_ListAnomalies_Handler,
+		},
+		{
+			MethodName: "ResolveAnomaly",
+			Handler:    _LicenseReader_ResolveAnomaly_Handler,
+		},
+		{
+			MethodName: "RegisterReader",
+			Handler:    _LicenseReader_RegisterReader_Handler,
+		},
+		{
+			MethodName: "ReaderHeartbeat",
+			Handler:    _LicenseReader_ReaderHeartbeat_Handler,
+		},
+		{
+			MethodName: "ListReaders",
+			Handler:    _LicenseReader_ListReaders_Handler,
+		},
+		{
+			MethodName: "ReportConfigStatus",
+			Handler:    _LicenseReader_ReportConfigStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchReads",
+			Handler:       _LicenseReader_WatchReads_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchConfig",
+			Handler:       _LicenseReader_WatchConfig_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadBatch",
+			Handler:       _LicenseReader_UploadBatch_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "license/license.proto",
 }
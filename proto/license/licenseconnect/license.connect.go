@@ -44,6 +44,39 @@ const (
 	// LicenseReaderGetReadHistoryProcedure is the fully-qualified name of the LicenseReader's
 	// GetReadHistory RPC.
 	LicenseReaderGetReadHistoryProcedure = "/license.LicenseReader/GetReadHistory"
+	// LicenseReaderPushAssignmentProcedure is the fully-qualified name of the LicenseReader's
+	// PushAssignment RPC.
+	LicenseReaderPushAssignmentProcedure = "/license.LicenseReader/PushAssignment"
+	// LicenseReaderGetWorkHoursProcedure is the fully-qualified name of the LicenseReader's
+	// GetWorkHours RPC.
+	LicenseReaderGetWorkHoursProcedure = "/license.LicenseReader/GetWorkHours"
+	// LicenseReaderListAnomaliesProcedure is the fully-qualified name of the LicenseReader's
+	// ListAnomalies RPC.
+	LicenseReaderListAnomaliesProcedure = "/license.LicenseReader/ListAnomalies"
+	// LicenseReaderResolveAnomalyProcedure is the fully-qualified name of the LicenseReader's
+	// ResolveAnomaly RPC.
+	LicenseReaderResolveAnomalyProcedure = "/license.LicenseReader/ResolveAnomaly"
+	// LicenseReaderWatchReadsProcedure is the fully-qualified name of the LicenseReader's WatchReads
+	// RPC.
+	LicenseReaderWatchReadsProcedure = "/license.LicenseReader/WatchReads"
+	// LicenseReaderRegisterReaderProcedure is the fully-qualified name of the LicenseReader's
+	// RegisterReader RPC.
+	LicenseReaderRegisterReaderProcedure = "/license.LicenseReader/RegisterReader"
+	// LicenseReaderReaderHeartbeatProcedure is the fully-qualified name of the LicenseReader's
+	// ReaderHeartbeat RPC.
+	LicenseReaderReaderHeartbeatProcedure = "/license.LicenseReader/ReaderHeartbeat"
+	// LicenseReaderListReadersProcedure is the fully-qualified name of the LicenseReader's ListReaders
+	// RPC.
+	LicenseReaderListReadersProcedure = "/license.LicenseReader/ListReaders"
+	// LicenseReaderWatchConfigProcedure is the fully-qualified name of the LicenseReader's WatchConfig
+	// RPC.
+	LicenseReaderWatchConfigProcedure = "/license.LicenseReader/WatchConfig"
+	// LicenseReaderReportConfigStatusProcedure is the fully-qualified name of the LicenseReader's
+	// ReportConfigStatus RPC.
+	LicenseReaderReportConfigStatusProcedure = "/license.LicenseReader/ReportConfigStatus"
+	// LicenseReaderUploadBatchProcedure is the fully-qualified name of the LicenseReader's UploadBatch
+	// RPC.
+	LicenseReaderUploadBatchProcedure = "/license.LicenseReader/UploadBatch"
 )
 
 // LicenseReaderClient is a client for the license.LicenseReader service.
@@ -56,6 +89,28 @@ type LicenseReaderClient interface {
 	GetLogs(context.Context, *connect.Request[license.GetLogsRequest]) (*connect.Response[license.GetLogsResponse], error)
 	// 読み取り履歴を取得
 	GetReadHistory(context.Context, *connect.Request[license.GetReadHistoryRequest]) (*connect.Response[license.GetReadHistoryResponse], error)
+	// 配車（運転者と車両の割り当て）をプッシュ
+	PushAssignment(context.Context, *connect.Request[license.Assignment]) (*connect.Response[license.PushResponse], error)
+	// 勤務時間の集計を取得（給与計算用）
+	GetWorkHours(context.Context, *connect.Request[license.GetWorkHoursRequest]) (*connect.Response[license.GetWorkHoursResponse], error)
+	// 打刻の異常一覧を取得
+	ListAnomalies(context.Context, *connect.Request[license.ListAnomaliesRequest]) (*connect.Response[license.ListAnomaliesResponse], error)
+	// 打刻の異常を解決済みにする
+	ResolveAnomaly(context.Context, *connect.Request[license.ResolveAnomalyRequest]) (*connect.Response[license.ResolveAnomalyResponse], error)
+	// 読み取りイベントをリアルタイムで受信（PushLicenseData/PushReadLogを配信）
+	WatchReads(context.Context, *connect.Request[license.WatchReadsRequest]) (*connect.ServerStreamForClient[license.ReadEvent], error)
+	// リーダーを登録（起動時。登録済みの場合は情報を更新）
+	RegisterReader(context.Context, *connect.Request[license.RegisterReaderRequest]) (*connect.Response[license.RegisterReaderResponse], error)
+	// リーダーの死活監視（登録されていない場合はNOT_FOUND）
+	ReaderHeartbeat(context.Context, *connect.Request[license.ReaderHeartbeatRequest]) (*connect.Response[license.ReaderHeartbeatResponse], error)
+	// 登録済みのリーダー一覧を取得
+	ListReaders(context.Context, *connect.Request[license.ListReadersRequest]) (*connect.Response[license.ListReadersResponse], error)
+	// リーダーの設定を受信（接続時と設定の変更時に配信）
+	WatchConfig(context.Context, *connect.Request[license.WatchConfigRequest]) (*connect.ServerStreamForClient[license.ReaderConfig], error)
+	// 設定の適用結果を報告
+	ReportConfigStatus(context.Context, *connect.Request[license.ReportConfigStatusRequest]) (*connect.Response[license.ReportConfigStatusResponse], error)
+	// 未送信の読み取りをまとめて送信（障害復旧後のバックログ送信用）
+	UploadBatch(context.Context) *connect.ClientStreamForClient[license.UploadItem, license.UploadBatchResponse]
 }
 
 // NewLicenseReaderClient constructs a client for the license.LicenseReader service. By default, it
@@ -93,15 +148,92 @@ func NewLicenseReaderClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(licenseReaderMethods.ByName("GetReadHistory")),
 			connect.WithClientOptions(opts...),
 		),
+		pushAssignment: connect.NewClient[license.Assignment, license.PushResponse](
+			httpClient,
+			baseURL+LicenseReaderPushAssignmentProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("PushAssignment")),
+			connect.WithClientOptions(opts...),
+		),
+		getWorkHours: connect.NewClient[license.GetWorkHoursRequest, license.GetWorkHoursResponse](
+			httpClient,
+			baseURL+LicenseReaderGetWorkHoursProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("GetWorkHours")),
+			connect.WithClientOptions(opts...),
+		),
+		listAnomalies: connect.NewClient[license.ListAnomaliesRequest, license.ListAnomaliesResponse](
+			httpClient,
+			baseURL+LicenseReaderListAnomaliesProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("ListAnomalies")),
+			connect.WithClientOptions(opts...),
+		),
+		resolveAnomaly: connect.NewClient[license.ResolveAnomalyRequest, license.ResolveAnomalyResponse](
+			httpClient,
+			baseURL+LicenseReaderResolveAnomalyProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("ResolveAnomaly")),
+			connect.WithClientOptions(opts...),
+		),
+		watchReads: connect.NewClient[license.WatchReadsRequest, license.ReadEvent](
+			httpClient,
+			baseURL+LicenseReaderWatchReadsProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("WatchReads")),
+			connect.WithClientOptions(opts...),
+		),
+		registerReader: connect.NewClient[license.RegisterReaderRequest, license.RegisterReaderResponse](
+			httpClient,
+			baseURL+LicenseReaderRegisterReaderProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("RegisterReader")),
+			connect.WithClientOptions(opts...),
+		),
+		readerHeartbeat: connect.NewClient[license.ReaderHeartbeatRequest, license.ReaderHeartbeatResponse](
+			httpClient,
+			baseURL+LicenseReaderReaderHeartbeatProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("ReaderHeartbeat")),
+			connect.WithClientOptions(opts...),
+		),
+		listReaders: connect.NewClient[license.ListReadersRequest, license.ListReadersResponse](
+			httpClient,
+			baseURL+LicenseReaderListReadersProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("ListReaders")),
+			connect.WithClientOptions(opts...),
+		),
+		watchConfig: connect.NewClient[license.WatchConfigRequest, license.ReaderConfig](
+			httpClient,
+			baseURL+LicenseReaderWatchConfigProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("WatchConfig")),
+			connect.WithClientOptions(opts...),
+		),
+		reportConfigStatus: connect.NewClient[license.ReportConfigStatusRequest, license.ReportConfigStatusResponse](
+			httpClient,
+			baseURL+LicenseReaderReportConfigStatusProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("ReportConfigStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		uploadBatch: connect.NewClient[license.UploadItem, license.UploadBatchResponse](
+			httpClient,
+			baseURL+LicenseReaderUploadBatchProcedure,
+			connect.WithSchema(licenseReaderMethods.ByName("UploadBatch")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // licenseReaderClient implements LicenseReaderClient.
 type licenseReaderClient struct {
-	pushLicenseData *connect.Client[license.LicenseData, license.PushResponse]
-	pushReadLog     *connect.Client[license.ReadLog, license.PushResponse]
-	getLogs         *connect.Client[license.GetLogsRequest, license.GetLogsResponse]
-	getReadHistory  *connect.Client[license.GetReadHistoryRequest, license.GetReadHistoryResponse]
+	pushLicenseData    *connect.Client[license.LicenseData, license.PushResponse]
+	pushReadLog        *connect.Client[license.ReadLog, license.PushResponse]
+	getLogs            *connect.Client[license.GetLogsRequest, license.GetLogsResponse]
+	getReadHistory     *connect.Client[license.GetReadHistoryRequest, license.GetReadHistoryResponse]
+	pushAssignment     *connect.Client[license.Assignment, license.PushResponse]
+	getWorkHours       *connect.Client[license.GetWorkHoursRequest, license.GetWorkHoursResponse]
+	listAnomalies      *connect.Client[license.ListAnomaliesRequest, license.ListAnomaliesResponse]
+	resolveAnomaly     *connect.Client[license.ResolveAnomalyRequest, license.ResolveAnomalyResponse]
+	watchReads         *connect.Client[license.WatchReadsRequest, license.ReadEvent]
+	registerReader     *connect.Client[license.RegisterReaderRequest, license.RegisterReaderResponse]
+	readerHeartbeat    *connect.Client[license.ReaderHeartbeatRequest, license.ReaderHeartbeatResponse]
+	listReaders        *connect.Client[license.ListReadersRequest, license.ListReadersResponse]
+	watchConfig        *connect.Client[license.WatchConfigRequest, license.ReaderConfig]
+	reportConfigStatus *connect.Client[license.ReportConfigStatusRequest, license.ReportConfigStatusResponse]
+	uploadBatch        *connect.Client[license.UploadItem, license.UploadBatchResponse]
 }
 
 // PushLicenseData calls license.LicenseReader.PushLicenseData.
@@ -124,6 +256,61 @@ func (c *licenseReaderClient) GetReadHistory(ctx context.Context, req *connect.R
 	return c.getReadHistory.CallUnary(ctx, req)
 }
 
+// PushAssignment calls license.LicenseReader.PushAssignment.
+func (c *licenseReaderClient) PushAssignment(ctx context.Context, req *connect.Request[license.Assignment]) (*connect.Response[license.PushResponse], error) {
+	return c.pushAssignment.CallUnary(ctx, req)
+}
+
+// GetWorkHours calls license.LicenseReader.GetWorkHours.
+func (c *licenseReaderClient) GetWorkHours(ctx context.Context, req *connect.Request[license.GetWorkHoursRequest]) (*connect.Response[license.GetWorkHoursResponse], error) {
+	return c.getWorkHours.CallUnary(ctx, req)
+}
+
+// ListAnomalies calls license.LicenseReader.ListAnomalies.
+func (c *licenseReaderClient) ListAnomalies(ctx context.Context, req *connect.Request[license.ListAnomaliesRequest]) (*connect.Response[license.ListAnomaliesResponse], error) {
+	return c.listAnomalies.CallUnary(ctx, req)
+}
+
+// ResolveAnomaly calls license.LicenseReader.ResolveAnomaly.
+func (c *licenseReaderClient) ResolveAnomaly(ctx context.Context, req *connect.Request[license.ResolveAnomalyRequest]) (*connect.Response[license.ResolveAnomalyResponse], error) {
+	return c.resolveAnomaly.CallUnary(ctx, req)
+}
+
+// WatchReads calls license.LicenseReader.WatchReads.
+func (c *licenseReaderClient) WatchReads(ctx context.Context, req *connect.Request[license.WatchReadsRequest]) (*connect.ServerStreamForClient[license.ReadEvent], error) {
+	return c.watchReads.CallServerStream(ctx, req)
+}
+
+// RegisterReader calls license.LicenseReader.RegisterReader.
+func (c *licenseReaderClient) RegisterReader(ctx context.Context, req *connect.Request[license.RegisterReaderRequest]) (*connect.Response[license.RegisterReaderResponse], error) {
+	return c.registerReader.CallUnary(ctx, req)
+}
+
+// ReaderHeartbeat calls license.LicenseReader.ReaderHeartbeat.
+func (c *licenseReaderClient) ReaderHeartbeat(ctx context.Context, req *connect.Request[license.ReaderHeartbeatRequest]) (*connect.Response[license.ReaderHeartbeatResponse], error) {
+	return c.readerHeartbeat.CallUnary(ctx, req)
+}
+
+// ListReaders calls license.LicenseReader.ListReaders.
+func (c *licenseReaderClient) ListReaders(ctx context.Context, req *connect.Request[license.ListReadersRequest]) (*connect.Response[license.ListReadersResponse], error) {
+	return c.listReaders.CallUnary(ctx, req)
+}
+
+// WatchConfig calls license.LicenseReader.WatchConfig.
+func (c *licenseReaderClient) WatchConfig(ctx context.Context, req *connect.Request[license.WatchConfigRequest]) (*connect.ServerStreamForClient[license.ReaderConfig], error) {
+	return c.watchConfig.CallServerStream(ctx, req)
+}
+
+// ReportConfigStatus calls license.LicenseReader.ReportConfigStatus.
+func (c *licenseReaderClient) ReportConfigStatus(ctx context.Context, req *connect.Request[license.ReportConfigStatusRequest]) (*connect.Response[license.ReportConfigStatusResponse], error) {
+	return c.reportConfigStatus.CallUnary(ctx, req)
+}
+
+// UploadBatch calls license.LicenseReader.UploadBatch.
+func (c *licenseReaderClient) UploadBatch(ctx context.Context) *connect.ClientStreamForClient[license.UploadItem, license.UploadBatchResponse] {
+	return c.uploadBatch.CallClientStream(ctx)
+}
+
 // LicenseReaderHandler is an implementation of the license.LicenseReader service.
 type LicenseReaderHandler interface {
 	// 読み取った免許証データをプッシュ
@@ -134,6 +321,28 @@ type LicenseReaderHandler interface {
 	GetLogs(context.Context, *connect.Request[license.GetLogsRequest]) (*connect.Response[license.GetLogsResponse], error)
 	// 読み取り履歴を取得
 	GetReadHistory(context.Context, *connect.Request[license.GetReadHistoryRequest]) (*connect.Response[license.GetReadHistoryResponse], error)
+	// 配車（運転者と車両の割り当て）をプッシュ
+	PushAssignment(context.Context, *connect.Request[license.Assignment]) (*connect.Response[license.PushResponse], error)
+	// 勤務時間の集計を取得（給与計算用）
+	GetWorkHours(context.Context, *connect.Request[license.GetWorkHoursRequest]) (*connect.Response[license.GetWorkHoursResponse], error)
+	// 打刻の異常一覧を取得
+	ListAnomalies(context.Context, *connect.Request[license.ListAnomaliesRequest]) (*connect.Response[license.ListAnomaliesResponse], error)
+	// 打刻の異常を解決済みにする
+	ResolveAnomaly(context.Context, *connect.Request[license.ResolveAnomalyRequest]) (*connect.Response[license.ResolveAnomalyResponse], error)
+	// 読み取りイベントをリアルタイムで受信（PushLicenseData/PushReadLogを配信）
+	WatchReads(context.Context, *connect.Request[license.WatchReadsRequest], *connect.ServerStream[license.ReadEvent]) error
+	// リーダーを登録（起動時。登録済みの場合は情報を更新）
+	RegisterReader(context.Context, *connect.Request[license.RegisterReaderRequest]) (*connect.Response[license.RegisterReaderResponse], error)
+	// リーダーの死活監視（登録されていない場合はNOT_FOUND）
+	ReaderHeartbeat(context.Context, *connect.Request[license.ReaderHeartbeatRequest]) (*connect.Response[license.ReaderHeartbeatResponse], error)
+	// 登録済みのリーダー一覧を取得
+	ListReaders(context.Context, *connect.Request[license.ListReadersRequest]) (*connect.Response[license.ListReadersResponse], error)
+	// リーダーの設定を受信（接続時と設定の変更時に配信）
+	WatchConfig(context.Context, *connect.Request[license.WatchConfigRequest], *connect.ServerStream[license.ReaderConfig]) error
+	// 設定の適用結果を報告
+	ReportConfigStatus(context.Context, *connect.Request[license.ReportConfigStatusRequest]) (*connect.Response[license.ReportConfigStatusResponse], error)
+	// 未送信の読み取りをまとめて送信（障害復旧後のバックログ送信用）
+	UploadBatch(context.Context, *connect.ClientStream[license.UploadItem]) (*connect.Response[license.UploadBatchResponse], error)
 }
 
 // NewLicenseReaderHandler builds an HTTP handler from the service implementation. It returns the
@@ -167,6 +376,72 @@ func NewLicenseReaderHandler(svc LicenseReaderHandler, opts ...connect.HandlerOp
 		connect.WithSchema(licenseReaderMethods.ByName("GetReadHistory")),
 		connect.WithHandlerOptions(opts...),
 	)
+	licenseReaderPushAssignmentHandler := connect.NewUnaryHandler(
+		LicenseReaderPushAssignmentProcedure,
+		svc.PushAssignment,
+		connect.WithSchema(licenseReaderMethods.ByName("PushAssignment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderGetWorkHoursHandler := connect.NewUnaryHandler(
+		LicenseReaderGetWorkHoursProcedure,
+		svc.GetWorkHours,
+		connect.WithSchema(licenseReaderMethods.ByName("GetWorkHours")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderListAnomaliesHandler := connect.NewUnaryHandler(
+		LicenseReaderListAnomaliesProcedure,
+		svc.ListAnomalies,
+		connect.WithSchema(licenseReaderMethods.ByName("ListAnomalies")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderResolveAnomalyHandler := connect.NewUnaryHandler(
+		LicenseReaderResolveAnomalyProcedure,
+		svc.ResolveAnomaly,
+		connect.WithSchema(licenseReaderMethods.ByName("ResolveAnomaly")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderWatchReadsHandler := connect.NewServerStreamHandler(
+		LicenseReaderWatchReadsProcedure,
+		svc.WatchReads,
+		connect.WithSchema(licenseReaderMethods.ByName("WatchReads")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderRegisterReaderHandler := connect.NewUnaryHandler(
+		LicenseReaderRegisterReaderProcedure,
+		svc.RegisterReader,
+		connect.WithSchema(licenseReaderMethods.ByName("RegisterReader")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderReaderHeartbeatHandler := connect.NewUnaryHandler(
+		LicenseReaderReaderHeartbeatProcedure,
+		svc.ReaderHeartbeat,
+		connect.WithSchema(licenseReaderMethods.ByName("ReaderHeartbeat")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderListReadersHandler := connect.NewUnaryHandler(
+		LicenseReaderListReadersProcedure,
+		svc.ListReaders,
+		connect.WithSchema(licenseReaderMethods.ByName("ListReaders")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderWatchConfigHandler := connect.NewServerStreamHandler(
+		LicenseReaderWatchConfigProcedure,
+		svc.WatchConfig,
+		connect.WithSchema(licenseReaderMethods.ByName("WatchConfig")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderReportConfigStatusHandler := connect.NewUnaryHandler(
+		LicenseReaderReportConfigStatusProcedure,
+		svc.ReportConfigStatus,
+		connect.WithSchema(licenseReaderMethods.ByName("ReportConfigStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	licenseReaderUploadBatchHandler := connect.NewClientStreamHandler(
+		LicenseReaderUploadBatchProcedure,
+		svc.UploadBatch,
+		connect.WithSchema(licenseReaderMethods.ByName("UploadBatch")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/license.LicenseReader/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case LicenseReaderPushLicenseDataProcedure:
@@ -177,6 +452,28 @@ func NewLicenseReaderHandler(svc LicenseReaderHandler, opts ...connect.HandlerOp
 			licenseReaderGetLogsHandler.ServeHTTP(w, r)
 		case LicenseReaderGetReadHistoryProcedure:
 			licenseReaderGetReadHistoryHandler.ServeHTTP(w, r)
+		case LicenseReaderPushAssignmentProcedure:
+			licenseReaderPushAssignmentHandler.ServeHTTP(w, r)
+		case LicenseReaderGetWorkHoursProcedure:
+			licenseReaderGetWorkHoursHandler.ServeHTTP(w, r)
+		case LicenseReaderListAnomaliesProcedure:
+			licenseReaderListAnomaliesHandler.ServeHTTP(w, r)
+		case LicenseReaderResolveAnomalyProcedure:
+			licenseReaderResolveAnomalyHandler.ServeHTTP(w, r)
+		case LicenseReaderWatchReadsProcedure:
+			licenseReaderWatchReadsHandler.ServeHTTP(w, r)
+		case LicenseReaderRegisterReaderProcedure:
+			licenseReaderRegisterReaderHandler.ServeHTTP(w, r)
+		case LicenseReaderReaderHeartbeatProcedure:
+			licenseReaderReaderHeartbeatHandler.ServeHTTP(w, r)
+		case LicenseReaderListReadersProcedure:
+			licenseReaderListReadersHandler.ServeHTTP(w, r)
+		case LicenseReaderWatchConfigProcedure:
+			licenseReaderWatchConfigHandler.ServeHTTP(w, r)
+		case LicenseReaderReportConfigStatusProcedure:
+			licenseReaderReportConfigStatusHandler.ServeHTTP(w, r)
+		case LicenseReaderUploadBatchProcedure:
+			licenseReaderUploadBatchHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -201,3 +498,47 @@ func (UnimplementedLicenseReaderHandler) GetLogs(context.Context, *connect.Reque
 func (UnimplementedLicenseReaderHandler) GetReadHistory(context.Context, *connect.Request[license.GetReadHistoryRequest]) (*connect.Response[license.GetReadHistoryResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.GetReadHistory is not implemented"))
 }
+
+func (UnimplementedLicenseReaderHandler) PushAssignment(context.Context, *connect.Request[license.Assignment]) (*connect.Response[license.PushResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.PushAssignment is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) GetWorkHours(context.Context, *connect.Request[license.GetWorkHoursRequest]) (*connect.Response[license.GetWorkHoursResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.GetWorkHours is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) ListAnomalies(context.Context, *connect.Request[license.ListAnomaliesRequest]) (*connect.Response[license.ListAnomaliesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.ListAnomalies is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) ResolveAnomaly(context.Context, *connect.Request[license.ResolveAnomalyRequest]) (*connect.Response[license.ResolveAnomalyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.ResolveAnomaly is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) WatchReads(context.Context, *connect.Request[license.WatchReadsRequest], *connect.ServerStream[license.ReadEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.WatchReads is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) RegisterReader(context.Context, *connect.Request[license.RegisterReaderRequest]) (*connect.Response[license.RegisterReaderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.RegisterReader is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) ReaderHeartbeat(context.Context, *connect.Request[license.ReaderHeartbeatRequest]) (*connect.Response[license.ReaderHeartbeatResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.ReaderHeartbeat is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) ListReaders(context.Context, *connect.Request[license.ListReadersRequest]) (*connect.Response[license.ListReadersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.ListReaders is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) WatchConfig(context.Context, *connect.Request[license.WatchConfigRequest], *connect.ServerStream[license.ReaderConfig]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.WatchConfig is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) ReportConfigStatus(context.Context, *connect.Request[license.ReportConfigStatusRequest]) (*connect.Response[license.ReportConfigStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.ReportConfigStatus is not implemented"))
+}
+
+func (UnimplementedLicenseReaderHandler) UploadBatch(context.Context, *connect.ClientStream[license.UploadItem]) (*connect.Response[license.UploadBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("license.LicenseReader.UploadBatch is not implemented"))
+}